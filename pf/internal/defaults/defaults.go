@@ -267,7 +267,7 @@ func (du *defaultsTransform) extendPropertyMapWithDefaults(
 	// Can iterate over SchemaInfo.Fields instead of iterating over every field in the schema, since the algorithm
 	// is only interested in properties where there is a SchemaInfo.Field specifying a default.
 	for key, fld := range infos {
-		if fld == nil || fld.Default == nil {
+		if fld == nil || fld.Default == nil || fld.Removed {
 			continue
 		}
 		fieldSchema, knownField := schemaMap.GetOk(key)
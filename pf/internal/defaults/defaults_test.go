@@ -337,6 +337,18 @@ func TestApplyDefaultInfoValues(t *testing.T) {
 			},
 			expected: resource.PropertyMap{},
 		},
+		{
+			name: "Removed fields do not receive defaults",
+			fieldInfos: map[string]*tfbridge.SchemaInfo{
+				"string_prop": {
+					Removed: true,
+					Default: &tfbridge.DefaultInfo{
+						Value: "defaultValue",
+					},
+				},
+			},
+			expected: resource.PropertyMap{},
+		},
 	}
 
 	for _, tc := range testCases {
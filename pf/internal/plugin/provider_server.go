@@ -118,7 +118,7 @@ func (p *providerServer) marshalDiff(diff pl.DiffResult) (*pulumirpc.DiffRespons
 			case pl.DiffDelete:
 				kind = pulumirpc.PropertyDiff_DELETE
 			case pl.DiffDeleteReplace:
-				kind, replaces = pulumirpc.PropertyDiff_DELETE, append(replaces, path)
+				kind, replaces = pulumirpc.PropertyDiff_DELETE_REPLACE, append(replaces, path)
 			case pl.DiffUpdate:
 				kind = pulumirpc.PropertyDiff_UPDATE
 			case pl.DiffUpdateReplace:
@@ -449,6 +449,14 @@ func (p *providerServer) Delete(ctx context.Context, req *pulumirpc.DeleteReques
 func (p *providerServer) Construct(ctx context.Context,
 	req *pulumirpc.ConstructRequest,
 ) (*pulumirpc.ConstructResponse, error) {
+	// Component resources implemented with the Pulumi Go SDK need the raw request and an engine connection, so
+	// providers that serve them are type tested here rather than going through ConstructWithContext.
+	if componentProvider, ok := p.provider.(interface {
+		ConstructComponent(ctx context.Context, req *pulumirpc.ConstructRequest) (*pulumirpc.ConstructResponse, error)
+	}); ok {
+		return componentProvider.ConstructComponent(ctx, req)
+	}
+
 	typ, name, parent := tokens.Type(req.GetType()), tokens.QName(req.GetName()), resource.URN(req.GetParent())
 
 	inputs, err := pl.UnmarshalProperties(req.GetInputs(), p.unmarshalOptions("inputs"))
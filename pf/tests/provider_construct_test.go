@@ -0,0 +1,57 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridgetests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	pprovider "github.com/pulumi/pulumi/sdk/v3/go/pulumi/provider"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pulumi/pulumi-terraform-bridge/pf/tests/internal/testprovider"
+	tfbridge0 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+)
+
+func TestConstruct(t *testing.T) {
+	info := testprovider.RandomProvider()
+	info.Components = map[string]*tfbridge0.ComponentInfo{
+		"random:index:Pet": {
+			Construct: tfbridge0.ConstructFunc(func(*pulumi.Context, string, string, pprovider.ConstructInputs,
+				pulumi.ResourceOption) (*pprovider.ConstructResult, error) {
+				t.Fatal("Construct should not be called without an engine")
+				return nil, nil
+			}),
+		},
+	}
+	server := newProviderServer(t, info)
+	ctx := context.Background()
+
+	t.Run("unknown component", func(t *testing.T) {
+		_, err := server.Construct(ctx, &pulumirpc.ConstructRequest{Type: "random:index:Unknown"})
+		require.Error(t, err)
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+
+	t.Run("no engine", func(t *testing.T) {
+		_, err := server.Construct(ctx, &pulumirpc.ConstructRequest{Type: "random:index:Pet"})
+		assert.ErrorContains(t, err, "not connected to an engine")
+	})
+}
@@ -15,6 +15,7 @@
 package tfbridgetests
 
 import (
+	"fmt"
 	"testing"
 
 	testutils "github.com/pulumi/providertest/replay"
@@ -195,3 +196,39 @@ func TestDiffVersionUpgrade(t *testing.T) {
         }`
 	testutils.Replay(t, server, testCase)
 }
+
+// Set elements are compared by value, so that changing one element does not report the others as changed.
+func TestSetElementDiff(t *testing.T) {
+	server := newProviderServer(t, testprovider.SyntheticTestBridgeProvider())
+	testCase := `
+        {
+          "method": "/pulumirpc.ResourceProvider/Diff",
+          "request": {
+            "id": "0",
+            "urn": "urn:pulumi:test-stack::basicprogram::testbridge:index/testres:Testres::testres1",
+            "olds": {
+              "id": "0",
+              "requiredInputString": "input1",
+              "requiredInputStringCopy": "input1",
+              "setOptionals": ["a", "b", "c"],
+              "statedir": "/tmp"
+            },
+            "news": {
+              "requiredInputString": "input1",
+              "setOptionals": [%s],
+              "statedir": "/tmp"
+            }
+          },
+          "response": {
+            "changes": "DIFF_SOME",
+            "diffs": ["setOptionals[1]"],
+            "detailedDiff": {"setOptionals[1]": {"kind": "%s"}}
+          }
+        }`
+	t.Run("delete", func(t *testing.T) {
+		testutils.Replay(t, server, fmt.Sprintf(testCase, `"c", "a"`, "DELETE"))
+	})
+	t.Run("update", func(t *testing.T) {
+		testutils.Replay(t, server, fmt.Sprintf(testCase, `"a", "d", "c"`, "UPDATE"))
+	})
+}
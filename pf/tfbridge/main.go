@@ -31,6 +31,7 @@ import (
 	"github.com/pulumi/pulumi-terraform-bridge/pf/internal/schemashim"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/metadata"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/recording"
 	"github.com/pulumi/pulumi-terraform-bridge/x/muxer"
 )
 
@@ -38,13 +39,20 @@ import (
 //
 // info.P must be constructed with ShimProvider or ShimProviderWithContext.
 func Main(ctx context.Context, pkg string, prov tfbridge.ProviderInfo, meta ProviderMetadata) {
-	handleFlags(ctx, prov.Version,
+	handleFlags(ctx, prov,
 		func() (*tfbridge.MarshallableProviderInfo, error) {
 			pp, err := newProviderWithContext(ctx, prov, meta)
 			if err != nil {
 				return nil, err
 			}
 			return pp.(*provider).marshalProviderInfo(ctx), nil
+		},
+		func() (tfbridge.TerraformInstanceMigrator, error) {
+			pp, err := newProviderWithContext(ctx, prov, meta)
+			if err != nil {
+				return nil, err
+			}
+			return pp.(*provider).migrateTerraformInstance, nil
 		})
 	// TODO[pulumi/pulumi-terraform-bridge#820]
 	// prov.P.InitLogging()
@@ -54,9 +62,12 @@ func Main(ctx context.Context, pkg string, prov tfbridge.ProviderInfo, meta Prov
 	}
 }
 
+// handleFlags handles the flags which make the provider binary exit without serving. newMigrator returns the
+// migrator of -migrate-tfstate; a nil newMigrator means the provider cannot migrate Terraform state.
 func handleFlags(
-	ctx context.Context, version string,
+	ctx context.Context, prov tfbridge.ProviderInfo,
 	getProviderInfo func() (*tfbridge.MarshallableProviderInfo, error),
+	newMigrator func() (tfbridge.TerraformInstanceMigrator, error),
 ) {
 	// Look for a request to dump the provider info to stdout.
 	flags := flag.NewFlagSet("tf-provider-flags", flag.ContinueOnError)
@@ -70,6 +81,14 @@ func handleFlags(
 
 	dumpInfo := flags.Bool("get-provider-info", false, "dump provider info as JSON to stdout")
 	providerVersion := flags.Bool("version", false, "get built provider version")
+	migrateState := flags.String("migrate-tfstate", "",
+		"migrate the resources of a Terraform state file and write them to stdout")
+	migrateFormat := flags.String("migrate-format", "import",
+		"format of the migrated resources: import for `pulumi import --file`, checkpoint for `pulumi stack import`")
+	migrateStack := flags.String("migrate-stack", "", "stack the migrated checkpoint is written for")
+	migrateProject := flags.String("migrate-project", "", "project the migrated checkpoint is written for")
+	migrateProviderID := flags.String("migrate-provider-id", "",
+		"ID of the default provider the migrated checkpoint attaches resources to; random if empty")
 
 	err := flags.Parse(os.Args[1:])
 	contract.IgnoreError(err)
@@ -95,7 +114,27 @@ func handleFlags(
 	}
 
 	if *providerVersion {
-		fmt.Println(version)
+		fmt.Println(prov.Version)
+		os.Exit(0)
+	}
+
+	if *migrateState != "" {
+		if newMigrator == nil {
+			cmdutil.ExitError("-migrate-tfstate is not supported by this provider")
+		}
+		migrate, err := newMigrator()
+		if err != nil {
+			cmdutil.ExitError(err.Error())
+		}
+		err = tfbridge.MigrateTerraformStateFile(ctx, prov, migrate, *migrateState, *migrateFormat,
+			tfbridge.CheckpointOptions{
+				Stack:      *migrateStack,
+				Project:    *migrateProject,
+				ProviderID: *migrateProviderID,
+			}, os.Stdout, os.Stderr)
+		if err != nil {
+			cmdutil.ExitError(err.Error())
+		}
 		os.Exit(0)
 	}
 }
@@ -107,14 +146,20 @@ func MainWithMuxer(ctx context.Context, pkg string, info tfbridge.ProviderInfo,
 	if len(info.MuxWith) > 0 {
 		panic("mixin providers via tfbridge.ProviderInfo.MuxWith is currently not supported")
 	}
-	handleFlags(ctx, info.Version, func() (*tfbridge.MarshallableProviderInfo, error) {
+	handleFlags(ctx, info, func() (*tfbridge.MarshallableProviderInfo, error) {
 		info := info
 		return tfbridge.MarshalProviderInfo(&info), nil
-	})
+	}, nil)
 
 	f := MakeMuxedServer(ctx, pkg, info, schema)
 
-	err := rprovider.Main(pkg, f)
+	err := rprovider.Main(pkg, func(host *rprovider.HostClient) (pulumirpc.ResourceProviderServer, error) {
+		server, err := f(host)
+		if err != nil {
+			return nil, err
+		}
+		return recording.FromEnv(server), nil
+	})
 	if err != nil {
 		cmdutil.ExitError(err.Error())
 	}
@@ -25,6 +25,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
 
+	rprovider "github.com/pulumi/pulumi/pkg/v3/resource/provider"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
@@ -39,6 +40,7 @@ import (
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/convert"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/cancel"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/logging"
 )
 
@@ -59,12 +61,16 @@ type provider struct {
 	configType    tftypes.Object
 	version       semver.Version
 	logSink       logging.Sink
+	host          *rprovider.HostClient
 
 	// Used by CheckConfig to remember the current Provider configuration so that it can be recalled and used for
 	// populating defaults specified via DefaultInfo.Config.
 	lastKnownProviderConfig resource.PropertyMap
 
 	schemaOnlyProvider shim.Provider
+
+	// Cancels in-flight operations when the engine calls Cancel.
+	cancellation cancel.Scope
 }
 
 var _ pl.ProviderWithContext = &provider{}
@@ -173,6 +179,9 @@ func NewProviderServer(
 	pp := p.(*provider)
 
 	pp.logSink = logSink
+	if host, ok := logSink.(*rprovider.HostClient); ok {
+		pp.host = host
+	}
 	configEnc := tfbridge.NewConfigEncoding(pp.schemaOnlyProvider.Schema(), pp.info.Config)
 	return pl.NewProviderServerWithContext(p, configEnc), nil
 }
@@ -206,8 +215,18 @@ func (p *provider) GetPluginInfoWithContext(_ context.Context) (workspace.Plugin
 // aborted in this way will return an error (e.g., `Update` and `Create` will either a creation error or an
 // initialization error. SignalCancellation is advisory and non-blocking; it is up to the host to decide how long to
 // wait after SignalCancellation is called before (e.g.) hard-closing any gRPC connection.
-func (p *provider) SignalCancellationWithContext(_ context.Context) error {
-	// Some improvements are possible here to gracefully shut down.
+//
+// In-flight and subsequent resource operations and invokes have their contexts canceled, and the Terraform provider
+// is asked to stop.
+func (p *provider) SignalCancellationWithContext(ctx context.Context) error {
+	p.cancellation.Cancel()
+	resp, err := p.tfServer.StopProvider(ctx, &tfprotov6.StopProviderRequest{})
+	if err != nil {
+		return fmt.Errorf("error calling StopProvider: %w", err)
+	}
+	if resp.Error != "" {
+		return fmt.Errorf("error stopping the provider: %s", resp.Error)
+	}
 	return nil
 }
 
@@ -229,15 +248,9 @@ func (p *provider) terraformDatasourceName(functionToken tokens.ModuleMember) (s
 	return "", fmt.Errorf("[pf/tfbridge] unknown datasource token: %v", functionToken)
 }
 
-// NOT IMPLEMENTED: Call dynamically executes a method in the provider associated with a component resource.
-func (p *provider) CallWithContext(_ context.Context,
-	tok tokens.ModuleMember, args resource.PropertyMap, info plugin.CallInfo,
-	options plugin.CallOptions) (plugin.CallResult, error) {
-	return plugin.CallResult{},
-		fmt.Errorf("Call is not implemented for Terraform Plugin Framework bridged providers")
-}
-
 // NOT IMPLEMENTED: Construct creates a new component resource.
+//
+// Components registered in ProviderInfo.Components are served over gRPC by ConstructComponent instead.
 func (p *provider) ConstructWithContext(_ context.Context,
 	info plugin.ConstructInfo, typ tokens.Type, name tokens.QName, parent resource.URN,
 	inputs resource.PropertyMap, options plugin.ConstructOptions) (plugin.ConstructResult, error) {
@@ -245,6 +258,12 @@ func (p *provider) ConstructWithContext(_ context.Context,
 		fmt.Errorf("Construct is not implemented for Terraform Plugin Framework bridged providers")
 }
 
+// ConstructComponent creates a new instance of a component resource registered in ProviderInfo.Components.
+func (p *provider) ConstructComponent(ctx context.Context,
+	req *pulumirpc.ConstructRequest) (*pulumirpc.ConstructResponse, error) {
+	return tfbridge.ConstructComponentResource(ctx, p.host, p.info.Components, req)
+}
+
 func newProviderServer6(ctx context.Context, p pfprovider.Provider) (tfprotov6.ProviderServer, error) {
 	newServer6 := providerserver.NewProtocol6(p)
 	server6 := newServer6()
@@ -24,5 +24,6 @@ func (p *provider) Attach(address string) error {
 		return err
 	}
 	p.logSink = host
+	p.host = host
 	return nil
 }
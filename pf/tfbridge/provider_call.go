@@ -0,0 +1,60 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"context"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+)
+
+// Call dynamically executes a method registered in ResourceInfo.Methods.
+func (p *provider) CallWithContext(
+	ctx context.Context,
+	tok tokens.ModuleMember,
+	args resource.PropertyMap,
+	_ plugin.CallInfo,
+	_ plugin.CallOptions,
+) (plugin.CallResult, error) {
+	ctx = p.initLogging(ctx, p.logSink, "")
+	ctx, done := p.cancellation.Context(ctx)
+	defer done()
+
+	ret, err := tfbridge.CallResourceMethod(ctx, p.info.Resources, tok, args, p.readResourceState)
+	if err != nil {
+		return plugin.CallResult{}, err
+	}
+	return plugin.CallResult{Return: ret}, nil
+}
+
+// readResourceState reads the state of a resource by ID in the same way as a Pulumi import.
+func (p *provider) readResourceState(
+	ctx context.Context,
+	urn resource.URN,
+	id resource.ID,
+) (resource.PropertyMap, error) {
+	result, _, err := p.ReadWithContext(ctx, urn, id, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if result.ID == "" {
+		return nil, nil
+	}
+	return result.Outputs, nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	pfprovider "github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	presource "github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+)
+
+func TestCancel(t *testing.T) {
+	ctx := context.Background()
+	started := make(chan struct{})
+	info := tfbridge.ProviderInfo{
+		Name:         "test",
+		Version:      "0.0.1",
+		P:            ShimProvider(&blockingProvider{started: started}),
+		MetadataInfo: tfbridge.NewProviderMetadata(nil),
+		Resources: map[string]*tfbridge.ResourceInfo{
+			"test_blocking": {Tok: "test:index:Blocking"},
+		},
+	}
+	p, err := newProviderWithContext(ctx, info, ProviderMetadata{})
+	require.NoError(t, err)
+
+	createErr := make(chan error)
+	go func() {
+		_, _, _, err := p.CreateWithContext(ctx, "urn:pulumi:dev::test::test:index:Blocking::b",
+			presource.PropertyMap{"name": presource.NewStringProperty("b")}, 0, false)
+		createErr <- err
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(time.Minute):
+		require.Fail(t, "Create did not start")
+	}
+
+	require.NoError(t, p.SignalCancellationWithContext(ctx))
+
+	select {
+	case err := <-createErr:
+		assert.ErrorContains(t, err, "context canceled")
+	case <-time.After(time.Minute):
+		require.Fail(t, "Create was not canceled")
+	}
+}
+
+// blockingProvider has a single resource whose Create blocks until its context is canceled, like a slow cloud
+// operation would.
+type blockingProvider struct {
+	started chan struct{}
+}
+
+func (p *blockingProvider) Metadata(_ context.Context, _ pfprovider.MetadataRequest,
+	resp *pfprovider.MetadataResponse) {
+	resp.TypeName = "test"
+}
+
+func (p *blockingProvider) Schema(context.Context, pfprovider.SchemaRequest, *pfprovider.SchemaResponse) {
+}
+
+func (p *blockingProvider) Configure(context.Context, pfprovider.ConfigureRequest, *pfprovider.ConfigureResponse) {
+}
+
+func (p *blockingProvider) DataSources(context.Context) []func() datasource.DataSource { return nil }
+
+func (p *blockingProvider) Resources(context.Context) []func() resource.Resource {
+	return []func() resource.Resource{func() resource.Resource { return &blockingResource{started: p.started} }}
+}
+
+type blockingResource struct {
+	started chan struct{}
+}
+
+func (r *blockingResource) Metadata(_ context.Context, req resource.MetadataRequest,
+	resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_blocking"
+}
+
+func (r *blockingResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = rschema.Schema{
+		Attributes: map[string]rschema.Attribute{
+			"id":   rschema.StringAttribute{Computed: true},
+			"name": rschema.StringAttribute{Optional: true},
+		},
+	}
+}
+
+func (r *blockingResource) Create(ctx context.Context, _ resource.CreateRequest, resp *resource.CreateResponse) {
+	close(r.started)
+	<-ctx.Done()
+	resp.Diagnostics.AddError("create interrupted", ctx.Err().Error())
+}
+
+func (r *blockingResource) Read(context.Context, resource.ReadRequest, *resource.ReadResponse) {}
+
+func (r *blockingResource) Update(context.Context, resource.UpdateRequest, *resource.UpdateResponse) {
+}
+
+func (r *blockingResource) Delete(context.Context, resource.DeleteRequest, *resource.DeleteResponse) {
+}
@@ -34,6 +34,8 @@ func (p *provider) CreateWithContext(
 	preview bool,
 ) (resource.ID, resource.PropertyMap, resource.Status, error) {
 	ctx = p.initLogging(ctx, p.logSink, urn)
+	ctx, done := p.cancellation.Context(ctx)
+	defer done()
 
 	rh, err := p.resourceHandle(ctx, urn)
 	if err != nil {
@@ -67,6 +69,8 @@ func (p *provider) CreateWithContext(
 			return "", nil, 0, err
 		}
 
+		plannedStatePropertyMap = restoreAssets(rh, checkedInputs, plannedStatePropertyMap)
+
 		if rh.pulumiResourceInfo.TransformOutputs != nil {
 			var err error
 			plannedStatePropertyMap, err = rh.pulumiResourceInfo.TransformOutputs(ctx,
@@ -114,6 +118,8 @@ func (p *provider) CreateWithContext(
 		return "", nil, 0, err
 	}
 
+	createdStateMap = restoreAssets(rh, checkedInputs, createdStateMap)
+
 	if rh.pulumiResourceInfo.TransformOutputs != nil {
 		var err error
 		createdStateMap, err = rh.pulumiResourceInfo.TransformOutputs(ctx, createdStateMap)
@@ -35,6 +35,8 @@ func (p *provider) DeleteWithContext(
 ) (resource.Status, error) {
 
 	ctx = p.initLogging(ctx, p.logSink, urn)
+	ctx, done := p.cancellation.Context(ctx)
+	defer done()
 
 	rh, err := p.resourceHandle(ctx, urn)
 	if err != nil {
@@ -18,6 +18,8 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
 
@@ -27,6 +29,7 @@ import (
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/convert"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/propertyvalue"
 )
 
@@ -109,11 +112,11 @@ func (p *provider) DiffWithContext(
 	replaceKeys := topLevelPropertyKeySet(resSchemaMap, resFields, planResp.RequiresReplace)
 	changedKeys := topLevelPropertyKeySet(resSchemaMap, resFields, diffAttributePaths(tfDiff))
 
-	// TODO[pulumi/pulumi-terraform-bridge#823] nameRequiresDeleteBeforeReplace intricacies
 	deleteBeforeReplace := false
 	if len(replaceKeys) > 0 {
 		info := rh.pulumiResourceInfo
-		if info != nil && info.DeleteBeforeReplace {
+		if info != nil && (info.DeleteBeforeReplace ||
+			nameRequiresDeleteBeforeReplace(resSchemaMap, info, priorStateMap, checkedInputs)) {
 			deleteBeforeReplace = true
 		}
 	}
@@ -128,12 +131,37 @@ func (p *provider) DiffWithContext(
 		ReplaceKeys:         replaceKeys,
 		ChangedKeys:         changedKeys,
 		DeleteBeforeReplace: deleteBeforeReplace,
+		DetailedDiff: makeDetailedDiff(resSchemaMap, resFields, priorStateMap, checkedInputs,
+			tfDiff, planResp.RequiresReplace, changedKeys, replaceKeys),
 	}
 
 	// TODO[pulumi/pulumi-terraform-bridge#824] StableKeys
 	return diffResult, nil
 }
 
+// nameRequiresDeleteBeforeReplace reports whether a replacement would reuse the unique name of the resource being
+// replaced, in which case the old resource must be deleted first. This follows the UniqueNameFields branch of the
+// SDKv2 bridge. Plugin Framework inputs do not record which properties were defaulted, so auto-named fields are
+// assumed to be renamed by the autonamer on replacement.
+func nameRequiresDeleteBeforeReplace(
+	sch shim.SchemaMap, info *tfbridge.ResourceInfo, olds, news resource.PropertyMap,
+) bool {
+	if len(info.UniqueNameFields) == 0 {
+		return false
+	}
+	for _, name := range info.UniqueNameFields {
+		key := resource.PropertyKey(name)
+		if !olds[key].DeepEquals(news[key]) {
+			return false
+		}
+		psi := info.Fields[tfbridge.PulumiToTerraformName(name, sch, info.Fields)]
+		if psi != nil && psi.HasDefault() && psi.Default.AutoNamed {
+			return false
+		}
+	}
+	return true
+}
+
 // For each path x.y.z extracts the next step x and converts it to a matching Pulumi key. Removes
 // duplicates and orders the result.
 func topLevelPropertyKeySet(
@@ -167,3 +195,239 @@ func diffAttributePaths(tfDiff []tftypes.ValueDiff) []*tftypes.AttributePath {
 	}
 	return paths
 }
+
+// makeDetailedDiff reports the elements of the changed sets that were added, deleted or updated, comparing them by
+// value. Since the engine only displays the paths of a detailed diff, the other changes are reported at the path of
+// the changed attribute within the top-level keys holding set changes, and as a whole for the remaining changed keys.
+// Returns nil when no set changed, leaving the engine to rely on ChangedKeys and ReplaceKeys.
+func makeDetailedDiff(
+	sch shim.SchemaMap,
+	ps map[string]*tfbridge.SchemaInfo,
+	olds, news resource.PropertyMap,
+	tfDiff []tftypes.ValueDiff,
+	requiresReplace []*tftypes.AttributePath,
+	changedKeys, replaceKeys []resource.PropertyKey,
+) map[string]plugin.PropertyDiff {
+	// A change to a set, to one of its ancestors or within its elements changes the set.
+	changed := newPathIndex(diffAttributePaths(tfDiff))
+	replacing := newPathIndex(requiresReplace)
+	setDiffs := tfbridge.MakeSetElementDiffs(sch, ps, olds, news, func(tfPath []string) (bool, bool) {
+		return changed.relatedTo(tfPath), replacing.relatedTo(tfPath)
+	})
+	if len(setDiffs) == 0 {
+		return nil
+	}
+
+	replaced := map[resource.PropertyKey]bool{}
+	for _, k := range replaceKeys {
+		replaced[k] = true
+	}
+	detailedDiff := map[string]plugin.PropertyDiff{}
+	keysWithSetDiffs := map[string]bool{}
+	for _, k := range changedKeys {
+		for path, d := range setDiffs {
+			if isPropertyPathWithin(path, string(k)) {
+				detailedDiff[path] = d
+				keysWithSetDiffs[string(k)] = true
+			}
+		}
+		if keysWithSetDiffs[string(k)] {
+			continue
+		}
+
+		kind := plugin.DiffUpdate
+		if _, ok := olds[k]; !ok {
+			kind = plugin.DiffAdd
+		} else if _, ok := news[k]; !ok && !isComputedKey(sch, ps, k) {
+			kind = plugin.DiffDelete
+		}
+		detailedDiff[string(k)] = plugin.PropertyDiff{Kind: replaceKind(kind, replaced[k])}
+	}
+
+	// Report the other changes of the keys holding set changes at their own paths. Changes within the sets already
+	// diffed are skipped, as are the changes to the length of their ancestors, which tftypes also reports per element.
+	kinds, replaces := map[string]plugin.DiffKind{}, map[string]bool{}
+	for _, d := range tfDiff {
+		path := propertyPath(sch, ps, d.Path)
+		if path == "" || !keysWithSetDiffs[topLevelKey(path)] {
+			continue
+		}
+		covered := false
+		for p := range setDiffs {
+			covered = covered || isPropertyPathWithin(p, path)
+		}
+		if covered {
+			continue
+		}
+
+		kind := plugin.DiffUpdate
+		switch {
+		case d.Value1 == nil || d.Value1.IsNull():
+			kind = plugin.DiffAdd
+		case d.Value2 == nil || d.Value2.IsNull():
+			kind = plugin.DiffDelete
+		}
+		if prev, ok := kinds[path]; ok && prev != kind {
+			kind = plugin.DiffUpdate
+		}
+		kinds[path] = kind
+		replaces[path] = replaces[path] || replacing.relatedTo(attributePathSteps(d.Path))
+	}
+	for path, kind := range kinds {
+		detailedDiff[path] = plugin.PropertyDiff{Kind: replaceKind(kind, replaces[path])}
+	}
+	return detailedDiff
+}
+
+// replaceKind returns the replacing variant of kind if replace is true.
+func replaceKind(kind plugin.DiffKind, replace bool) plugin.DiffKind {
+	if !replace {
+		return kind
+	}
+	switch kind {
+	case plugin.DiffAdd:
+		return plugin.DiffAddReplace
+	case plugin.DiffDelete:
+		return plugin.DiffDeleteReplace
+	default:
+		return plugin.DiffUpdateReplace
+	}
+}
+
+// attributePathSteps converts path to the attribute names and list indices passed to set change predicates, such as
+// [rule 0 match]. The steps within set elements are dropped, since set elements are identified by value.
+// pathIndex indexes attribute paths by their steps, so that the paths related to another one are found without
+// rescanning all of them.
+type pathIndex struct {
+	paths    map[string]bool // the indexed paths.
+	prefixes map[string]bool // the indexed paths and all their ancestors.
+}
+
+func newPathIndex(paths []*tftypes.AttributePath) pathIndex {
+	ix := pathIndex{paths: map[string]bool{}, prefixes: map[string]bool{}}
+	for _, path := range paths {
+		steps := attributePathSteps(path)
+		ix.paths[joinSteps(steps)] = true
+		for n := 0; n <= len(steps); n++ {
+			ix.prefixes[joinSteps(steps[:n])] = true
+		}
+	}
+	return ix
+}
+
+// relatedTo reports whether an indexed path is tfPath, one of its ancestors or a path within it.
+func (ix pathIndex) relatedTo(tfPath []string) bool {
+	if ix.prefixes[joinSteps(tfPath)] {
+		return true
+	}
+	for n := 0; n < len(tfPath); n++ {
+		if ix.paths[joinSteps(tfPath[:n])] {
+			return true
+		}
+	}
+	return false
+}
+
+// joinSteps joins the steps of a path with a separator that does not occur in attribute names or keys.
+func joinSteps(steps []string) string {
+	return strings.Join(steps, "\x00")
+}
+
+func attributePathSteps(path *tftypes.AttributePath) []string {
+	var steps []string
+	for _, step := range path.Steps() {
+		switch step := step.(type) {
+		case tftypes.AttributeName:
+			steps = append(steps, string(step))
+		case tftypes.ElementKeyInt:
+			steps = append(steps, strconv.FormatInt(int64(step), 10))
+		case tftypes.ElementKeyString:
+			steps = append(steps, string(step))
+		default:
+			return steps
+		}
+	}
+	return steps
+}
+
+// propertyPath converts the Terraform path of a change to the Pulumi property path it is reported at. Changes within
+// sets and maps are reported at the path of the set or map.
+func propertyPath(sch shim.SchemaMap, ps map[string]*tfbridge.SchemaInfo, path *tftypes.AttributePath) string {
+	steps := path.Steps()
+	if len(steps) == 0 {
+		return ""
+	}
+	name, ok := steps[0].(tftypes.AttributeName)
+	if !ok {
+		return ""
+	}
+	tfs, _ := sch.GetOk(string(name))
+	info := ps[string(name)]
+	result := tfbridge.TerraformToPulumiNameV2(string(name), sch, ps)
+	for _, step := range steps[1:] {
+		if tfs == nil || tfs.Type() == shim.TypeSet && !tfbridge.IsMaxItemsOne(tfs, info) {
+			break
+		}
+		switch step := step.(type) {
+		case tftypes.ElementKeyInt:
+			if tfs.Type() != shim.TypeList && tfs.Type() != shim.TypeSet {
+				return result
+			}
+			if !tfbridge.IsMaxItemsOne(tfs, info) {
+				result = fmt.Sprintf("%s[%d]", result, step)
+			}
+			tfs, info = elemSchema(tfs, info)
+		case tftypes.AttributeName:
+			res, ok := tfs.Elem().(shim.Resource)
+			if !ok {
+				return result
+			}
+			var fields map[string]*tfbridge.SchemaInfo
+			if info != nil {
+				fields = info.Fields
+			}
+			result += "." + tfbridge.TerraformToPulumiNameV2(string(step), res.Schema(), fields)
+			tfs, _ = res.Schema().GetOk(string(step))
+			info = fields[string(step)]
+		default:
+			return result
+		}
+	}
+	return result
+}
+
+// elemSchema returns the schema and info of the elements of a list or set, representing block elements as objects.
+func elemSchema(tfs shim.Schema, info *tfbridge.SchemaInfo) (shim.Schema, *tfbridge.SchemaInfo) {
+	var einfo *tfbridge.SchemaInfo
+	if info != nil {
+		einfo = info.Elem
+	}
+	switch e := tfs.Elem().(type) {
+	case shim.Schema:
+		return e, einfo
+	case shim.Resource:
+		return (&schema.Schema{Type: shim.TypeMap, Elem: e}).Shim(), einfo
+	default:
+		return nil, einfo
+	}
+}
+
+// isPropertyPathWithin returns true if the property path is equal to or nested within the given ancestor path.
+func isPropertyPathWithin(path, ancestor string) bool {
+	return path == ancestor || strings.HasPrefix(path, ancestor+"[") || strings.HasPrefix(path, ancestor+".")
+}
+
+// topLevelKey returns the top-level property key of a property path.
+func topLevelKey(path string) string {
+	if i := strings.IndexAny(path, ".["); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
+
+// isComputedKey returns true if the provider may fill in the top-level property k when it is missing from the inputs.
+func isComputedKey(sch shim.SchemaMap, ps map[string]*tfbridge.SchemaInfo, k resource.PropertyKey) bool {
+	tfName := tfbridge.PulumiToTerraformName(string(k), sch, ps)
+	s, ok := sch.GetOk(tfName)
+	return ok && s.Computed()
+}
@@ -21,7 +21,9 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
 
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
 )
@@ -85,3 +87,137 @@ func TestTopLevelPropertyKeySet(t *testing.T) {
 		})
 	}
 }
+
+func TestMakeDetailedDiff(t *testing.T) {
+	sch := schema.SchemaMap{
+		"name": (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+		"tags": (&schema.Schema{
+			Type:     shim.TypeSet,
+			Optional: true,
+			Elem:     (&schema.Schema{Type: shim.TypeString}).Shim(),
+		}).Shim(),
+	}
+	olds := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"name": "a",
+		"tags": []interface{}{"x", "y", "z"},
+	})
+	path := func(name string) *tftypes.AttributePath {
+		return tftypes.NewAttributePath().WithAttributeName(name)
+	}
+
+	t.Run("no set changes", func(t *testing.T) {
+		news := resource.NewPropertyMapFromMap(map[string]interface{}{
+			"name": "b",
+			"tags": []interface{}{"x", "y", "z"},
+		})
+		actual := makeDetailedDiff(sch, nil, olds, news, []tftypes.ValueDiff{{Path: path("name")}}, nil,
+			[]resource.PropertyKey{"name"}, nil)
+		require.Nil(t, actual)
+	})
+
+	t.Run("set changes", func(t *testing.T) {
+		news := resource.NewPropertyMapFromMap(map[string]interface{}{
+			"tags": []interface{}{"z", "w", "x"},
+		})
+		actual := makeDetailedDiff(sch, nil, olds, news,
+			[]tftypes.ValueDiff{{Path: path("name")}, {Path: path("tags")}}, []*tftypes.AttributePath{path("tags")},
+			[]resource.PropertyKey{"name", "tags"}, []resource.PropertyKey{"tags"})
+		require.Equal(t, map[string]plugin.PropertyDiff{
+			"name":    {Kind: plugin.DiffDelete},
+			"tags[1]": {Kind: plugin.DiffUpdateReplace},
+		}, actual)
+	})
+
+	t.Run("nested set changes", func(t *testing.T) {
+		sch := schema.SchemaMap{
+			"name": (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+			"rule": (&schema.Schema{
+				Type:     shim.TypeList,
+				Optional: true,
+				Elem: (&schema.Resource{
+					Schema: schema.SchemaMap{
+						"name": (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+						"ports": (&schema.Schema{
+							Type:     shim.TypeSet,
+							Optional: true,
+							Elem:     (&schema.Schema{Type: shim.TypeInt}).Shim(),
+						}).Shim(),
+					},
+				}).Shim(),
+			}).Shim(),
+		}
+		olds := resource.NewPropertyMapFromMap(map[string]interface{}{
+			"name":  "a",
+			"rules": []interface{}{map[string]interface{}{"name": "a", "ports": []interface{}{1, 2, 3}}},
+		})
+		news := resource.NewPropertyMapFromMap(map[string]interface{}{
+			"name":  "b",
+			"rules": []interface{}{map[string]interface{}{"name": "b", "ports": []interface{}{3, 4, 1}}},
+		})
+		rule := path("rule").WithElementKeyInt(0)
+		value := func(typ tftypes.Type, v interface{}) *tftypes.Value {
+			val := tftypes.NewValue(typ, v)
+			return &val
+		}
+		port := func(n int64) *tftypes.AttributePath {
+			return rule.WithAttributeName("ports").WithElementKeyValue(tftypes.NewValue(tftypes.Number, n))
+		}
+		actual := makeDetailedDiff(sch, nil, olds, news,
+			[]tftypes.ValueDiff{
+				{Path: path("name"), Value1: value(tftypes.String, "a"), Value2: value(tftypes.String, "b")},
+				{Path: rule.WithAttributeName("name"), Value1: value(tftypes.String, "a"), Value2: value(tftypes.String, "b")},
+				{Path: port(2), Value1: value(tftypes.Number, 2)},
+				{Path: port(4), Value2: value(tftypes.Number, 4)},
+			},
+			[]*tftypes.AttributePath{rule.WithAttributeName("ports")},
+			[]resource.PropertyKey{"name", "rules"}, []resource.PropertyKey{"rules"})
+		require.Equal(t, map[string]plugin.PropertyDiff{
+			"name":              {Kind: plugin.DiffUpdate},
+			"rules[0].name":     {Kind: plugin.DiffUpdate},
+			"rules[0].ports[1]": {Kind: plugin.DiffUpdateReplace},
+		}, actual)
+	})
+}
+
+func TestPathIndex(t *testing.T) {
+	ix := newPathIndex([]*tftypes.AttributePath{
+		tftypes.NewAttributePath().WithAttributeName("rules").WithElementKeyInt(0).WithAttributeName("ports"),
+		tftypes.NewAttributePath().WithAttributeName("tags"),
+	})
+
+	require.True(t, ix.relatedTo([]string{"rules"}), "ancestor of a changed path")
+	require.True(t, ix.relatedTo([]string{"rules", "0", "ports"}), "changed path")
+	require.True(t, ix.relatedTo([]string{"tags", "env"}), "within a changed path")
+	require.False(t, ix.relatedTo([]string{"rules", "1", "ports"}))
+	require.False(t, ix.relatedTo([]string{"name"}))
+}
+
+func TestNameRequiresDeleteBeforeReplace(t *testing.T) {
+	sch := schema.SchemaMap{
+		"name":     (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+		"location": (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+	}
+	olds := resource.PropertyMap{
+		"name":     resource.NewStringProperty("n1"),
+		"location": resource.NewStringProperty("l1"),
+	}
+	renamed := resource.PropertyMap{
+		"name":     resource.NewStringProperty("n2"),
+		"location": resource.NewStringProperty("l2"),
+	}
+	moved := resource.PropertyMap{
+		"name":     resource.NewStringProperty("n1"),
+		"location": resource.NewStringProperty("l2"),
+	}
+
+	unique := &tfbridge.ResourceInfo{UniqueNameFields: []string{"name"}}
+	require.False(t, nameRequiresDeleteBeforeReplace(sch, &tfbridge.ResourceInfo{}, olds, moved))
+	require.True(t, nameRequiresDeleteBeforeReplace(sch, unique, olds, moved))
+	require.False(t, nameRequiresDeleteBeforeReplace(sch, unique, olds, renamed))
+
+	autoNamed := &tfbridge.ResourceInfo{
+		UniqueNameFields: []string{"name"},
+		Fields:           map[string]*tfbridge.SchemaInfo{"name": tfbridge.AutoName("name", 255, "-")},
+	}
+	require.False(t, nameRequiresDeleteBeforeReplace(sch, autoNamed, olds, moved))
+}
@@ -38,6 +38,8 @@ func (p *provider) InvokeWithContext(
 	args resource.PropertyMap,
 ) (resource.PropertyMap, []plugin.CheckFailure, error) {
 	ctx = p.initLogging(ctx, p.logSink, "")
+	ctx, done := p.cancellation.Context(ctx)
+	defer done()
 
 	handle, err := p.datasourceHandle(ctx, tok)
 	if err != nil {
@@ -38,6 +38,8 @@ func (p *provider) ReadWithContext(
 	currentStateMap resource.PropertyMap,
 ) (plugin.ReadResult, resource.Status, error) {
 	ctx = p.initLogging(ctx, p.logSink, urn)
+	ctx, done := p.cancellation.Context(ctx)
+	defer done()
 
 	var err error
 
@@ -42,12 +42,16 @@ type resourceHandle struct {
 }
 
 func (p *provider) resourceHandle(ctx context.Context, urn pulumiresource.URN) (resourceHandle, error) {
-	resources := p.resources
-
 	typeName, err := p.terraformResourceName(urn.Type())
 	if err != nil {
 		return resourceHandle{}, err
 	}
+	return p.terraformResourceHandle(ctx, typeName)
+}
+
+// terraformResourceHandle returns the handle of the resource with the Terraform type name typeName.
+func (p *provider) terraformResourceHandle(ctx context.Context, typeName string) (resourceHandle, error) {
+	resources := p.resources
 
 	n := pfutils.TypeName(typeName)
 	schema := resources.Schema(n)
@@ -105,3 +109,13 @@ func transformFromState(
 	}
 	return o, err
 }
+
+// Restores the assets and archives passed as inputs to properties with a SchemaInfo.Asset translation, since
+// Terraform only tracks their translated paths or contents.
+func restoreAssets(rh resourceHandle, inputs, outputs pulumiresource.PropertyMap) pulumiresource.PropertyMap {
+	if rh.pulumiResourceInfo == nil || rh.schemaOnlyShimResource == nil {
+		return outputs
+	}
+	return tfbridge.RestoreAssets(rh.schemaOnlyShimResource.Schema(), rh.pulumiResourceInfo.GetFields(),
+		inputs, outputs)
+}
@@ -16,14 +16,35 @@ package tfbridge
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
 )
 
-func (p *provider) StreamInvokeWithContext(_ context.Context,
-	tok tokens.ModuleMember, args resource.PropertyMap,
-	onNext func(resource.PropertyMap) error) ([]plugin.CheckFailure, error) {
-	panic("StreamInvoke() should not be called for bridged providers")
+// StreamInvoke dynamically executes a data source that sets DataSourceInfo.StreamAttribute, passing the elements of
+// that attribute to onNext one at a time.
+func (p *provider) StreamInvokeWithContext(
+	ctx context.Context,
+	tok tokens.ModuleMember,
+	args resource.PropertyMap,
+	onNext func(resource.PropertyMap) error,
+) ([]plugin.CheckFailure, error) {
+	handle, err := p.datasourceHandle(ctx, tok)
+	if err != nil {
+		return nil, err
+	}
+	info := handle.pulumiDataSourceInfo
+	if info == nil || info.StreamAttribute == "" {
+		return nil, fmt.Errorf("[pf/tfbridge] data source %q does not support StreamInvoke", tok)
+	}
+
+	result, failures, err := p.InvokeWithContext(ctx, tok, args)
+	if err != nil || len(failures) > 0 {
+		return failures, err
+	}
+	return nil, tfbridge.StreamDataSourceResult(result, handle.schemaOnlyShim.Schema(), info, onNext)
 }
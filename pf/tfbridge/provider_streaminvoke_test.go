@@ -0,0 +1,149 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	dschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	pfprovider "github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	presource "github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+)
+
+func TestStreamInvoke(t *testing.T) {
+	ctx := context.Background()
+	newProvider := func(t *testing.T, streamAttribute string) *provider {
+		p, err := newProviderWithContext(ctx, tfbridge.ProviderInfo{
+			Name:         "test",
+			Version:      "1.2.3",
+			P:            ShimProvider(&imagesProvider{}),
+			MetadataInfo: tfbridge.NewProviderMetadata(nil),
+			DataSources: map[string]*tfbridge.DataSourceInfo{
+				"test_images": {Tok: "test:index:getImages", StreamAttribute: streamAttribute},
+			},
+		}, ProviderMetadata{})
+		require.NoError(t, err)
+		return p.(*provider)
+	}
+
+	streamInvoke := func(t *testing.T, p *provider) ([]presource.PropertyMap, error) {
+		var sent []presource.PropertyMap
+		failures, err := p.StreamInvokeWithContext(ctx, "test:index:getImages", presource.PropertyMap{
+			"prefix": presource.NewStringProperty("img-"),
+		}, func(item presource.PropertyMap) error {
+			sent = append(sent, item)
+			return nil
+		})
+		require.Empty(t, failures)
+		return sent, err
+	}
+
+	t.Run("objects", func(t *testing.T) {
+		sent, err := streamInvoke(t, newProvider(t, "images"))
+		require.NoError(t, err)
+		require.Len(t, sent, 3)
+		for i, id := range []string{"img-a", "img-b", "img-c"} {
+			assert.Equal(t, presource.NewStringProperty(id), sent[i]["imageId"])
+		}
+	})
+
+	t.Run("scalars", func(t *testing.T) {
+		sent, err := streamInvoke(t, newProvider(t, "ids"))
+		require.NoError(t, err)
+		require.Len(t, sent, 3)
+		for i, id := range []string{"img-a", "img-b", "img-c"} {
+			assert.Equal(t, presource.NewStringProperty(id), sent[i][tfbridge.StreamValueKey])
+		}
+	})
+
+	t.Run("not streamable", func(t *testing.T) {
+		_, err := streamInvoke(t, newProvider(t, ""))
+		assert.ErrorContains(t, err, `data source "test:index:getImages" does not support StreamInvoke`)
+	})
+}
+
+// imagesProvider has a single data source listing the images with a given prefix.
+type imagesProvider struct{}
+
+func (p *imagesProvider) Metadata(_ context.Context, _ pfprovider.MetadataRequest,
+	resp *pfprovider.MetadataResponse) {
+	resp.TypeName = "test"
+}
+
+func (p *imagesProvider) Schema(context.Context, pfprovider.SchemaRequest, *pfprovider.SchemaResponse) {
+}
+
+func (p *imagesProvider) Configure(context.Context, pfprovider.ConfigureRequest, *pfprovider.ConfigureResponse) {
+}
+
+func (p *imagesProvider) DataSources(context.Context) []func() datasource.DataSource {
+	return []func() datasource.DataSource{func() datasource.DataSource { return &imagesDataSource{} }}
+}
+
+func (p *imagesProvider) Resources(context.Context) []func() resource.Resource { return nil }
+
+type imagesDataSource struct{}
+
+var imageType = types.ObjectType{AttrTypes: map[string]attr.Type{"image_id": types.StringType}}
+
+func (d *imagesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_images"
+}
+
+func (d *imagesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = dschema.Schema{
+		Attributes: map[string]dschema.Attribute{
+			"prefix": dschema.StringAttribute{Required: true},
+			"images": dschema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: dschema.NestedAttributeObject{
+					Attributes: map[string]dschema.Attribute{
+						"image_id": dschema.StringAttribute{Computed: true},
+					},
+				},
+			},
+			"ids": dschema.ListAttribute{Computed: true, ElementType: types.StringType},
+		},
+	}
+}
+
+func (d *imagesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var prefix types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("prefix"), &prefix)...)
+
+	var images, ids []attr.Value
+	for _, id := range []string{"a", "b", "c"} {
+		id := types.StringValue(prefix.ValueString() + id)
+		images = append(images, types.ObjectValueMust(imageType.AttrTypes, map[string]attr.Value{"image_id": id}))
+		ids = append(ids, id)
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("prefix"), prefix)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("images"),
+		types.ListValueMust(imageType, images))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("ids"),
+		types.ListValueMust(types.StringType, ids))...)
+}
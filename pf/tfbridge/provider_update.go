@@ -39,6 +39,8 @@ func (p *provider) UpdateWithContext(
 	preview bool,
 ) (resource.PropertyMap, resource.Status, error) {
 	ctx = p.initLogging(ctx, p.logSink, urn)
+	ctx, done := p.cancellation.Context(ctx)
+	defer done()
 
 	rh, err := p.resourceHandle(ctx, urn)
 	if err != nil {
@@ -84,6 +86,8 @@ func (p *provider) UpdateWithContext(
 			return nil, 0, err
 		}
 
+		plannedStatePropertyMap = restoreAssets(rh, checkedInputs, plannedStatePropertyMap)
+
 		if rh.pulumiResourceInfo.TransformOutputs != nil {
 			var err error
 			plannedStatePropertyMap, err = rh.pulumiResourceInfo.TransformOutputs(ctx,
@@ -128,6 +132,8 @@ func (p *provider) UpdateWithContext(
 		return nil, 0, err
 	}
 
+	updatedStateMap = restoreAssets(rh, checkedInputs, updatedStateMap)
+
 	if rh.pulumiResourceInfo.TransformOutputs != nil {
 		var err error
 		updatedStateMap, err = rh.pulumiResourceInfo.TransformOutputs(ctx, updatedStateMap)
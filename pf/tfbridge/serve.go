@@ -19,11 +19,16 @@ import (
 	rprovider "github.com/pulumi/pulumi/pkg/v3/resource/provider"
 
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/recording"
 	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
 )
 
 func serve(ctx context.Context, pkg string, prov tfbridge.ProviderInfo, meta ProviderMetadata) error {
 	return rprovider.Main(pkg, func(host *rprovider.HostClient) (pulumirpc.ResourceProviderServer, error) {
-		return NewProviderServer(ctx, host, prov, meta)
+		server, err := NewProviderServer(ctx, host, prov, meta)
+		if err != nil {
+			return nil, err
+		}
+		return recording.FromEnv(server), nil
 	})
 }
@@ -0,0 +1,97 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+
+	"github.com/pulumi/pulumi-terraform-bridge/pf/internal/schemashim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+)
+
+// migrateTerraformInstance implements tfbridge.TerraformInstanceMigrator. Terraform state is read as Terraform does,
+// by passing the raw JSON attributes to UpgradeResourceState, which also upgrades states recorded at an older schema
+// version. The outputs are then translated as Read translates them. The provider is not configured, so state
+// upgraders that need a configured provider fail.
+func (p *provider) migrateTerraformInstance(
+	ctx context.Context, tfType string, _ *tfbridge.ResourceInfo, schemaVersion int,
+	attributes map[string]interface{},
+) (*tfbridge.MigratedResource, error) {
+	rh, err := p.terraformResourceHandle(ctx, tfType)
+	if err != nil {
+		return nil, err
+	}
+
+	rawState, err := json.Marshal(attributes)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.tfServer.UpgradeResourceState(ctx, &tfprotov6.UpgradeResourceStateRequest{
+		TypeName: rh.terraformResourceName,
+		Version:  int64(schemaVersion),
+		RawState: &tfprotov6.RawState{JSON: rawState},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("upgrading state from schema version %d: %w", schemaVersion, err)
+	}
+	if err := p.processDiagnostics(resp.Diagnostics); err != nil {
+		return nil, fmt.Errorf("upgrading state from schema version %d: %w", schemaVersion, err)
+	}
+
+	state, err := parseResourceStateFromTF(ctx, &rh, resp.UpgradedState, nil)
+	if err != nil {
+		return nil, err
+	}
+	outputs, err := state.ToPropertyMap(&rh)
+	if err != nil {
+		return nil, err
+	}
+	if rh.pulumiResourceInfo.TransformOutputs != nil {
+		outputs, err = rh.pulumiResourceInfo.TransformOutputs(ctx, outputs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// extractID panics on a missing ID, which would abort the whole migration.
+	if rh.pulumiResourceInfo.ComputeID == nil {
+		if id, ok := outputs["id"]; !ok || !(id.IsString() || id.IsNumber()) {
+			return nil, fmt.Errorf("state has no ID")
+		}
+	}
+	id, err := extractID(ctx, rh.terraformResourceName, rh.pulumiResourceInfo, outputs)
+	if err != nil {
+		return nil, fmt.Errorf("computing the ID: %w", err)
+	}
+
+	inputs, err := tfbridge.ExtractInputsFromOutputs(nil, outputs, schemashim.NewSchemaMap(rh.schema),
+		rh.pulumiResourceInfo.Fields, false)
+	if err != nil {
+		return nil, err
+	}
+	// __defaults is not needed for Plugin Framework bridged providers
+	delete(inputs, "__defaults")
+
+	return &tfbridge.MigratedResource{
+		Type:    rh.token,
+		ID:      id,
+		Inputs:  inputs,
+		Outputs: outputs,
+	}, nil
+}
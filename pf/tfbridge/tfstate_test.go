@@ -0,0 +1,186 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	pfprovider "github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	presource "github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+)
+
+func TestMigrateTerraformState(t *testing.T) {
+	ctx := context.Background()
+	info, p := newBucketProvider(t)
+
+	m, err := tfbridge.MigrateTerraformStateWith(ctx, info, strings.NewReader(`{
+  "version": 4,
+  "resources": [
+    {
+      "mode": "managed", "type": "test_bucket", "name": "main",
+      "instances": [{"schema_version": 1, "attributes": {"id": "b-1", "name": "main", "size": 3}}]
+    },
+    {
+      "mode": "managed", "type": "test_bucket", "name": "logs",
+      "instances": [{"schema_version": 0, "attributes": {"id": "b-2", "bucket_name": "logs"}}]
+    },
+    {
+      "mode": "managed", "type": "test_bucket", "name": "broken",
+      "instances": [{"schema_version": 1, "attributes": {"name": "broken"}}]
+    }
+  ]
+}`), p.migrateTerraformInstance)
+	require.NoError(t, err)
+
+	assert.Equal(t, []tfbridge.SkippedTerraformResource{
+		{Address: "test_bucket.broken", Reason: "state has no ID"},
+	}, m.Skipped)
+	require.Len(t, m.Resources, 2)
+	main, logs := m.Resources[0], m.Resources[1]
+
+	assert.Equal(t, presource.ID("b-1"), main.ID)
+	assert.Equal(t, "main", main.Name)
+	assert.Equal(t, presource.NewNumberProperty(3), main.Outputs["size"])
+	assert.Equal(t, presource.NewStringProperty("main"), main.Inputs["name"])
+	assert.NotContains(t, main.Inputs, presource.PropertyKey("__defaults"))
+
+	// The second instance was recorded at schema version 0 and is upgraded by the state upgrader.
+	assert.Equal(t, presource.ID("b-2"), logs.ID)
+	assert.Equal(t, presource.NewStringProperty("logs"), logs.Inputs["name"])
+}
+
+func TestMigrateTerraformStateFile(t *testing.T) {
+	ctx := context.Background()
+	info, p := newBucketProvider(t)
+
+	path := filepath.Join(t.TempDir(), "terraform.tfstate")
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": 4, "resources": [{
+  "mode": "managed", "type": "test_bucket", "name": "main",
+  "instances": [{"schema_version": 1, "attributes": {"id": "b-1", "name": "main"}}]
+}]}`), 0o600))
+
+	var stdout, stderr strings.Builder
+	err := tfbridge.MigrateTerraformStateFile(ctx, info, p.migrateTerraformInstance, path, "checkpoint",
+		tfbridge.CheckpointOptions{Stack: "dev", Project: "proj", ProviderID: "provider-id"}, &stdout, &stderr)
+	require.NoError(t, err)
+	assert.Contains(t, stdout.String(), "urn:pulumi:dev::proj::pulumi:providers:test::default_1_2_3::provider-id")
+	assert.Empty(t, stderr.String())
+}
+
+func newBucketProvider(t *testing.T) (tfbridge.ProviderInfo, *provider) {
+	info := tfbridge.ProviderInfo{
+		Name:         "test",
+		Version:      "1.2.3",
+		P:            ShimProvider(&bucketProvider{}),
+		MetadataInfo: tfbridge.NewProviderMetadata(nil),
+		Resources: map[string]*tfbridge.ResourceInfo{
+			"test_bucket": {Tok: "test:index/bucket:Bucket"},
+		},
+	}
+	p, err := newProviderWithContext(context.Background(), info, ProviderMetadata{})
+	require.NoError(t, err)
+	return info, p.(*provider)
+}
+
+// bucketProvider has a single resource at schema version 1, which renamed bucket_name to name.
+type bucketProvider struct{}
+
+func (p *bucketProvider) Metadata(_ context.Context, _ pfprovider.MetadataRequest,
+	resp *pfprovider.MetadataResponse) {
+	resp.TypeName = "test"
+}
+
+func (p *bucketProvider) Schema(context.Context, pfprovider.SchemaRequest, *pfprovider.SchemaResponse) {
+}
+
+func (p *bucketProvider) Configure(context.Context, pfprovider.ConfigureRequest, *pfprovider.ConfigureResponse) {
+}
+
+func (p *bucketProvider) DataSources(context.Context) []func() datasource.DataSource { return nil }
+
+func (p *bucketProvider) Resources(context.Context) []func() resource.Resource {
+	return []func() resource.Resource{func() resource.Resource { return &bucketResource{} }}
+}
+
+type bucketResource struct{}
+
+type bucketModel struct {
+	ID   types.String `tfsdk:"id"`
+	Name types.String `tfsdk:"name"`
+	Size types.Int64  `tfsdk:"size"`
+}
+
+func (r *bucketResource) Metadata(_ context.Context, req resource.MetadataRequest,
+	resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bucket"
+}
+
+func (r *bucketResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = rschema.Schema{
+		Version: 1,
+		Attributes: map[string]rschema.Attribute{
+			"id":   rschema.StringAttribute{Computed: true},
+			"name": rschema.StringAttribute{Required: true},
+			"size": rschema.Int64Attribute{Optional: true},
+		},
+	}
+}
+
+func (r *bucketResource) UpgradeState(context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: &rschema.Schema{
+				Attributes: map[string]rschema.Attribute{
+					"id":          rschema.StringAttribute{Computed: true},
+					"bucket_name": rschema.StringAttribute{Required: true},
+				},
+			},
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest,
+				resp *resource.UpgradeStateResponse) {
+				var prior struct {
+					ID         types.String `tfsdk:"id"`
+					BucketName types.String `tfsdk:"bucket_name"`
+				}
+				resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+				resp.Diagnostics.Append(resp.State.Set(ctx, bucketModel{
+					ID:   prior.ID,
+					Name: prior.BucketName,
+					Size: types.Int64Null(),
+				})...)
+			},
+		},
+	}
+}
+
+func (r *bucketResource) Create(context.Context, resource.CreateRequest, *resource.CreateResponse) {}
+
+func (r *bucketResource) Read(context.Context, resource.ReadRequest, *resource.ReadResponse) {}
+
+func (r *bucketResource) Update(context.Context, resource.UpdateRequest, *resource.UpdateResponse) {}
+
+func (r *bucketResource) Delete(context.Context, resource.DeleteRequest, *resource.DeleteResponse) {}
@@ -119,21 +119,13 @@ func (u *notSupportedUtil) datasource(path string, ds *tfbridge.DataSourceInfo)
 
 func (u *notSupportedUtil) resource(path string, res *tfbridge.ResourceInfo) {
 	u.fields(path, res.Fields)
-	u.assertIsZero(path+".UniqueNameFields", res.UniqueNameFields)
-	u.assertIsZero(path+".Docs", res.Docs)
-	u.assertIsZero(path+".Aliases", res.Aliases)
 }
 
 func (u *notSupportedUtil) schema(path string, schema *tfbridge.SchemaInfo) {
 	u.assertIsZero(path+".Type", schema.Type)
-	u.assertIsZero(path+".AltTypes", schema.AltTypes)
 	u.assertIsZero(path+".NestedType", schema.NestedType)
-	u.assertIsZero(path+".Transform", schema.Transform)
 	u.assertIsZero(path+".Elem", schema.Elem)
 	u.fields(path, schema.Fields)
-	u.assertIsZero(path+".Asset", schema.Asset)
 	u.assertIsZero(path+".Stable", schema.Stable)
 	u.assertIsZero(path+".SuppressEmptyMapElements", schema.SuppressEmptyMapElements)
-	u.assertIsZero(path+".ForceNew", schema.ForceNew)
-	u.assertIsZero(path+".Removed", schema.Removed)
 }
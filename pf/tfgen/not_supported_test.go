@@ -16,12 +16,15 @@ package tfgen
 
 import (
 	"bytes"
+	"io"
 	"testing"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
 	"github.com/stretchr/testify/require"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
 )
 
 func TestZeroRecognizer(t *testing.T) {
@@ -34,3 +37,18 @@ func TestZeroRecognizer(t *testing.T) {
 	u.assertIsZero("key", "value")
 	require.Contains(t, stderr.String(), "key received a non-zero custom value")
 }
+
+func TestSupportedResourceInfo(t *testing.T) {
+	var stderr bytes.Buffer
+	u := &notSupportedUtil{
+		sink: diag.DefaultSink(io.Discard, &stderr, diag.FormatOptions{Color: colors.Never}),
+	}
+
+	ty := "test:index/old:Old"
+	u.resource("resource:test_res", &tfbridge.ResourceInfo{
+		Aliases:          []tfbridge.AliasInfo{{Type: &ty}},
+		UniqueNameFields: []string{"name"},
+		Docs:             &tfbridge.DocInfo{Source: "res.md"},
+	})
+	require.Empty(t, stderr.String())
+}
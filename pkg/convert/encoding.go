@@ -148,6 +148,23 @@ func newPropertyDecoder(pctx *schemaPropContext, name terraformPropertyName,
 }
 
 func deriveEncoder(pctx *schemaPropContext, t tftypes.Type) (Encoder, error) {
+	enc, err := deriveTypeEncoder(pctx, t)
+	if err != nil {
+		return nil, err
+	}
+	if info := pctx.schemaInfo; info != nil {
+		if info.Asset != nil {
+			enc = newAssetEncoder(info.Asset, enc)
+		}
+		// The transform runs first so that it may produce assets.
+		if info.Transform != nil {
+			enc = newTransformEncoder(info.Transform, enc)
+		}
+	}
+	return enc, nil
+}
+
+func deriveTypeEncoder(pctx *schemaPropContext, t tftypes.Type) (Encoder, error) {
 	if elementType, mio := pctx.IsMaxItemsOne(t); mio {
 		elctx, err := pctx.Element()
 		if err != nil {
@@ -230,6 +230,62 @@ func TestResourceEncoder(t *testing.T) {
 			//nolint:lll
 			expect: autogold.Expect(`tftypes.Object["foo":tftypes.String, "id":tftypes.String]<"foo":tftypes.String<"bar">, "id":tftypes.String<"myid">>`),
 		},
+		{
+			testName: "transform",
+			schema: &schema.SchemaMap{
+				"foo": (&schema.Schema{
+					Type:     shim.TypeString,
+					Optional: true,
+				}).Shim(),
+			},
+			info: &tfbridge.ProviderInfo{
+				Resources: map[string]*tfbridge.ResourceInfo{
+					myResource: {
+						Fields: map[string]*tfbridge.SchemaInfo{
+							"foo": {Transform: tfbridge.TransformJSONDocument},
+						},
+					},
+				},
+			},
+			typ: tftypes.Object{
+				AttributeTypes: map[string]tftypes.Type{
+					"foo": tftypes.String,
+				},
+			},
+			val: resource.PropertyMap{
+				"foo": resource.NewObjectProperty(resource.PropertyMap{
+					"a": resource.NewNumberProperty(1),
+				}),
+			},
+			expect: autogold.Expect(`tftypes.Object["foo":tftypes.String]<"foo":tftypes.String<"{"a":1}">>`),
+		},
+		{
+			testName: "asset",
+			schema: &schema.SchemaMap{
+				"content": (&schema.Schema{
+					Type:     shim.TypeString,
+					Optional: true,
+				}).Shim(),
+			},
+			info: &tfbridge.ProviderInfo{
+				Resources: map[string]*tfbridge.ResourceInfo{
+					myResource: {
+						Fields: map[string]*tfbridge.SchemaInfo{
+							"content": {Asset: &tfbridge.AssetTranslation{Kind: tfbridge.BytesAsset}},
+						},
+					},
+				},
+			},
+			typ: tftypes.Object{
+				AttributeTypes: map[string]tftypes.Type{
+					"content": tftypes.String,
+				},
+			},
+			val: resource.PropertyMap{
+				"content": resource.NewAssetProperty(mustStringAsset(t, "hello")),
+			},
+			expect: autogold.Expect(`tftypes.Object["content":tftypes.String]<"content":tftypes.String<"hello">>`),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -245,6 +301,40 @@ func TestResourceEncoder(t *testing.T) {
 	}
 }
 
+func TestAssetEncoderKinds(t *testing.T) {
+	archive, err := resource.NewAssetArchive(map[string]interface{}{"hello.txt": mustStringAsset(t, "hello")})
+	require.NoError(t, err)
+	archiveBytes, err := archive.Bytes(resource.ZIPArchive)
+	require.NoError(t, err)
+
+	encode := func(kind tfbridge.AssetTranslationKind, v resource.PropertyValue) (tftypes.Value, error) {
+		enc := newAssetEncoder(&tfbridge.AssetTranslation{Kind: kind}, newStringEncoder())
+		return enc.fromPropertyValue(v)
+	}
+
+	t.Run("archive", func(t *testing.T) {
+		got, err := encode(tfbridge.BytesArchive, resource.NewArchiveProperty(archive))
+		require.NoError(t, err)
+		require.Equal(t, tftypes.NewValue(tftypes.String, string(archiveBytes)), got)
+	})
+
+	t.Run("archive for an asset", func(t *testing.T) {
+		_, err := encode(tfbridge.BytesAsset, resource.NewArchiveProperty(archive))
+		require.ErrorContains(t, err, "expected an asset, got an archive")
+	})
+
+	t.Run("asset for an archive", func(t *testing.T) {
+		_, err := encode(tfbridge.BytesArchive, resource.NewAssetProperty(mustStringAsset(t, "hello")))
+		require.ErrorContains(t, err, "expected an archive, got an asset")
+	})
+}
+
+func mustStringAsset(t *testing.T, text string) *resource.Asset {
+	asset, err := resource.NewTextAsset(text)
+	require.NoError(t, err)
+	return asset
+}
+
 func TestDataSourceDecoder(t *testing.T) {
 	myDataSource := "my_datasource"
 
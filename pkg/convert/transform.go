@@ -0,0 +1,87 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+)
+
+// Applies SchemaInfo.Transform to values before encoding them, like MakeTerraformInputs does for SDKv2 resources.
+type transformEncoder struct {
+	transform tfbridge.Transformer
+	encoder   Encoder
+}
+
+func newTransformEncoder(transform tfbridge.Transformer, encoder Encoder) Encoder {
+	return &transformEncoder{transform: transform, encoder: encoder}
+}
+
+func (enc *transformEncoder) fromPropertyValue(p resource.PropertyValue) (tftypes.Value, error) {
+	if !propertyValueIsUnkonwn(p) {
+		var err error
+		if p, err = enc.transform(p); err != nil {
+			return tftypes.Value{}, err
+		}
+	}
+	return enc.encoder.fromPropertyValue(p)
+}
+
+// Translates assets and archives to the file paths or contents expected by Terraform, as directed by
+// SchemaInfo.Asset. Other values are encoded as is.
+type assetEncoder struct {
+	asset   *tfbridge.AssetTranslation
+	encoder Encoder
+}
+
+func newAssetEncoder(asset *tfbridge.AssetTranslation, encoder Encoder) Encoder {
+	return &assetEncoder{asset: asset, encoder: encoder}
+}
+
+func (enc *assetEncoder) fromPropertyValue(p resource.PropertyValue) (tftypes.Value, error) {
+	var translated interface{}
+	var err error
+	switch {
+	case p.IsAsset():
+		if !enc.asset.IsAsset() {
+			return tftypes.Value{}, fmt.Errorf("expected an archive, got an asset")
+		}
+		translated, err = enc.asset.TranslateAsset(p.AssetValue())
+	case p.IsArchive():
+		if !enc.asset.IsArchive() {
+			return tftypes.Value{}, fmt.Errorf("expected an asset, got an archive")
+		}
+		translated, err = enc.asset.TranslateArchive(p.ArchiveValue())
+	default:
+		return enc.encoder.fromPropertyValue(p)
+	}
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+
+	switch t := translated.(type) {
+	case string:
+		return enc.encoder.fromPropertyValue(resource.NewStringProperty(t))
+	case []byte:
+		return enc.encoder.fromPropertyValue(resource.NewStringProperty(string(t)))
+	default:
+		return tftypes.Value{}, fmt.Errorf("unexpected asset translation result %T", translated)
+	}
+}
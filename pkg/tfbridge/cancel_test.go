@@ -0,0 +1,97 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pbempty "github.com/golang/protobuf/ptypes/empty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	schemav2 "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+
+	shimv2 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2"
+)
+
+func TestCancel(t *testing.T) {
+	started := make(chan struct{})
+	tfProvider := &schemav2.Provider{
+		ResourcesMap: map[string]*schemav2.Resource{
+			"test_blocking": {
+				Schema: map[string]*schemav2.Schema{
+					"name": {Type: schemav2.TypeString, Optional: true},
+				},
+				// Blocks until the operation is canceled, like a slow cloud operation would.
+				CreateContext: func(ctx context.Context, rd *schemav2.ResourceData, _ interface{}) diag.Diagnostics {
+					close(started)
+					<-ctx.Done()
+					return diag.FromErr(ctx.Err())
+				},
+				ReadContext: func(context.Context, *schemav2.ResourceData, interface{}) diag.Diagnostics {
+					return nil
+				},
+				DeleteContext: func(context.Context, *schemav2.ResourceData, interface{}) diag.Diagnostics {
+					return nil
+				},
+			},
+		},
+	}
+	shimProvider := shimv2.NewProvider(tfProvider)
+	p := &Provider{
+		tf:     shimProvider,
+		config: shimv2.NewSchemaMap(tfProvider.Schema),
+		info: ProviderInfo{
+			P: shimProvider,
+			Resources: map[string]*ResourceInfo{
+				"test_blocking": {Tok: "test:index:Blocking"},
+			},
+		},
+	}
+	p.initResourceMaps()
+
+	ctx := context.Background()
+	createErr := make(chan error)
+	go func() {
+		_, err := p.Create(ctx, &pulumirpc.CreateRequest{
+			Urn: "urn:pulumi:dev::test::test:index:Blocking::b",
+			Properties: &structpb.Struct{Fields: map[string]*structpb.Value{
+				"name": structpb.NewStringValue("b"),
+			}},
+		})
+		createErr <- err
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(time.Minute):
+		require.Fail(t, "Create did not start")
+	}
+
+	_, err := p.Cancel(ctx, &pbempty.Empty{})
+	require.NoError(t, err)
+
+	select {
+	case err := <-createErr:
+		assert.ErrorContains(t, err, "context canceled")
+	case <-time.After(time.Minute):
+		require.Fail(t, "Create was not canceled")
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/provider"
+	pprovider "github.com/pulumi/pulumi/sdk/v3/go/pulumi/provider"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge/info"
+)
+
+// ConstructComponentResource serves a Construct request by dispatching it to the matching entry of components.
+//
+// Internal. The signature of this function can change between major releases. Exposed to share the implementation
+// between SDKv2 and Plugin Framework based providers.
+func ConstructComponentResource(
+	ctx context.Context,
+	host *provider.HostClient,
+	components map[string]*ComponentInfo,
+	req *pulumirpc.ConstructRequest,
+) (*pulumirpc.ConstructResponse, error) {
+	component, ok := components[req.GetType()]
+	if !ok || component == nil || component.Construct == nil {
+		return nil, status.Errorf(codes.Unimplemented, "unknown component resource type %q", req.GetType())
+	}
+	if host == nil {
+		return nil, fmt.Errorf("cannot construct %s: the provider is not connected to an engine", req.GetType())
+	}
+	return component.Construct.Construct(ctx, req, host.EngineConn())
+}
+
+// ConstructFunc implements ComponentInfo.Construct with a component written with the Pulumi Go SDK.
+type ConstructFunc pprovider.ConstructFunc
+
+var _ info.ComponentConstructor = ConstructFunc(nil)
+
+func (f ConstructFunc) Construct(
+	ctx context.Context, req *pulumirpc.ConstructRequest, engineConn *grpc.ClientConn,
+) (*pulumirpc.ConstructResponse, error) {
+	return pprovider.Construct(ctx, req, engineConn, pprovider.ConstructFunc(f))
+}
@@ -0,0 +1,95 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	pprovider "github.com/pulumi/pulumi/sdk/v3/go/pulumi/provider"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+func TestConstruct(t *testing.T) {
+	p := &Provider{
+		info: ProviderInfo{
+			Name: "test",
+			Components: map[string]*ComponentInfo{
+				"test:index:Network": {
+					Construct: ConstructFunc(func(*pulumi.Context, string, string, pprovider.ConstructInputs,
+						pulumi.ResourceOption) (*pprovider.ConstructResult, error) {
+						t.Fatal("Construct should not be called without an engine")
+						return nil, nil
+					}),
+				},
+			},
+		},
+	}
+
+	t.Run("unknown component", func(t *testing.T) {
+		_, err := p.Construct(context.Background(), &pulumirpc.ConstructRequest{Type: "test:index:Unknown"})
+		require.Error(t, err)
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+
+	t.Run("no engine", func(t *testing.T) {
+		_, err := p.Construct(context.Background(), &pulumirpc.ConstructRequest{Type: "test:index:Network"})
+		assert.ErrorContains(t, err, "not connected to an engine")
+	})
+}
+
+type testComponentConstructor struct {
+	engineConn *grpc.ClientConn
+	resp       *pulumirpc.ConstructResponse
+}
+
+func (c *testComponentConstructor) Construct(
+	ctx context.Context, req *pulumirpc.ConstructRequest, engineConn *grpc.ClientConn,
+) (*pulumirpc.ConstructResponse, error) {
+	c.engineConn = engineConn
+	return c.resp, nil
+}
+
+func TestConstructDispatch(t *testing.T) {
+	host, err := provider.NewHostClient("127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, host.Close()) })
+
+	component := &testComponentConstructor{
+		resp: &pulumirpc.ConstructResponse{Urn: "urn:pulumi:test::test::test:index:Network::net"},
+	}
+	p := &Provider{
+		host: host,
+		info: ProviderInfo{
+			Name: "test",
+			Components: map[string]*ComponentInfo{
+				"test:index:Network": {Construct: component},
+			},
+		},
+	}
+
+	resp, err := p.Construct(context.Background(), &pulumirpc.ConstructRequest{Type: "test:index:Network"})
+	require.NoError(t, err)
+	assert.Equal(t, component.resp.Urn, resp.Urn)
+	assert.Same(t, host.EngineConn(), component.engineConn)
+}
@@ -376,6 +376,47 @@ func makeDetailedDiffExtra(
 			etf, eps, true)
 	}
 
+	// The diffs of set elements computed above are located by the position of the elements in olds and news, which
+	// are unrelated. Replace them with diffs comparing the elements by value.
+	// A set changed if any attribute within it did. The changes are indexed by every prefix of their flatmap keys,
+	// so that each set is looked up once rather than rescanning all the attributes.
+	type setChange struct{ changed, replace bool }
+	setChanges := map[string]setChange{}
+	for k, d := range tfDiff.Attributes() {
+		for prefix := k; ; {
+			c := setChanges[prefix]
+			c.changed = c.changed || d.Old != d.New || d.NewRemoved
+			c.replace = c.replace || d.RequiresNew
+			setChanges[prefix] = c
+
+			i := strings.LastIndex(prefix, ".")
+			if i < 0 {
+				break
+			}
+			prefix = prefix[:i]
+		}
+	}
+	setDiffs := makeSetElementDiffs(tfs, ps, olds, news, func(tfPath []string) (bool, bool) {
+		c := setChanges[strings.Join(tfPath, ".")]
+		return c.changed, c.replace
+	}, setComparer{zeroIsNull: true})
+	for _, sd := range setDiffs {
+		// Sets whose changes were ignored or suppressed have no position-based diffs to replace.
+		replaced := false
+		for path := range diff {
+			if path == sd.path || strings.HasPrefix(path, sd.path+"[") {
+				delete(diff, path)
+				replaced = true
+			}
+		}
+		if !replaced {
+			continue
+		}
+		for path, d := range sd.diffs {
+			diff[path] = d
+		}
+	}
+
 	changes := pulumirpc.DiffResponse_DIFF_NONE
 	if len(diff) > 0 || *forceDiff {
 		changes = pulumirpc.DiffResponse_DIFF_SOME
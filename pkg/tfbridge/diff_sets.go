@@ -0,0 +1,445 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+)
+
+// setElementDiff holds the element-level diff of a set property found at the same path in the old state and the new
+// inputs of a resource.
+type setElementDiff struct {
+	path  string                             // the Pulumi property path of the set, such as ingress or rule[0].match
+	diffs map[string]*pulumirpc.PropertyDiff // the diff entries of the elements of the set, keyed by property path
+}
+
+// A setChangePredicate reports whether the set at the given Terraform path changed and whether the change requires
+// replacing the resource. The path is made of attribute names and list indices, such as [rule 0 match].
+type setChangePredicate func(tfPath []string) (changed, replace bool)
+
+// setComparer compares the elements of sets by value.
+type setComparer struct {
+	// zeroIsNull treats zero values as null. SDKv2 states do not distinguish them, but Plugin Framework states do.
+	zeroIsNull bool
+}
+
+// makeSetElementDiffs compares the elements of the sets of olds and news by value rather than by position.
+//
+// Terraform identifies set elements by their hash in SDKv2 and by their value in the Plugin Framework, but sets are
+// projected to Pulumi as arrays: the position of an element in the old state and in the new inputs are unrelated, and
+// a change to one element shifts the positions of the others. Diffs computed from positions therefore report most of
+// a set as changed when a single element changes.
+//
+// Instead, each new element is matched with an equal old element, ignoring computed-only fields and the fields of
+// the old element that the new one leaves for the provider to fill in. An unmatched new element that shares an
+// identity with an unmatched old element, that is the same values for the required fields of a block, is reported as
+// an update of the fields that differ. Other unmatched new elements are reported as added at their position in news
+// and unmatched old elements as deleted at their position in olds; an element deleted and added at the same position
+// is reported as updated. Sets containing unknown values cannot be compared and are skipped.
+func makeSetElementDiffs(
+	tfs shim.SchemaMap,
+	ps map[string]*SchemaInfo,
+	olds, news resource.PropertyMap,
+	changed setChangePredicate,
+	c setComparer,
+) []setElementDiff {
+	var result []setElementDiff
+	visit := func(tfPath []string, path string, oldV, newV resource.PropertyValue, tfs shim.Schema, ps *SchemaInfo) {
+		isChanged, replace := changed(tfPath)
+		if !isChanged || containsComputedValues(oldV) || containsComputedValues(newV) {
+			return
+		}
+		diffs := map[string]*pulumirpc.PropertyDiff{}
+		c.diffSetElements(path, oldV, newV, tfs, ps, replace, diffs)
+		if len(diffs) > 0 {
+			result = append(result, setElementDiff{path: path, diffs: diffs})
+		}
+	}
+
+	for _, k := range unionKeys(olds, news) {
+		en, etf, eps := getInfoFromPulumiName(k, tfs, ps)
+		walkSets([]string{en}, string(k), olds[k], news[k], etf, eps, visit)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].path < result[j].path })
+	return result
+}
+
+// MakeSetElementDiffs computes the element-level detailed diff of the sets of olds and news for which changed returns
+// true. It is used by the Plugin Framework bridge, which keeps zero values distinct from null; see makeSetElementDiffs
+// for how elements are compared.
+func MakeSetElementDiffs(
+	tfs shim.SchemaMap,
+	ps map[string]*SchemaInfo,
+	olds, news resource.PropertyMap,
+	changed func(tfPath []string) (changed, replace bool),
+) map[string]plugin.PropertyDiff {
+	result := map[string]plugin.PropertyDiff{}
+	for _, sd := range makeSetElementDiffs(tfs, ps, olds, news, changed, setComparer{}) {
+		for path, d := range sd.diffs {
+			result[path] = plugin.PropertyDiff{Kind: plugin.DiffKind(d.Kind)}
+		}
+	}
+	return result
+}
+
+// walkSets calls visit for each set found at the same path in old and new. It descends into objects and into the
+// elements of lists found at the same position, but not into sets.
+func walkSets(
+	tfPath []string, path string, oldV, newV resource.PropertyValue, tfs shim.Schema, ps *SchemaInfo,
+	visit func(tfPath []string, path string, oldV, newV resource.PropertyValue, tfs shim.Schema, ps *SchemaInfo),
+) {
+	if tfs == nil {
+		return
+	}
+	oldV, newV = unwrapSecret(oldV), unwrapSecret(newV)
+
+	if IsMaxItemsOne(tfs, ps) {
+		etfs, eps := elemSchemas(tfs, ps)
+		walkSets(appendTFPath(tfPath, "0"), path, oldV, newV, etfs, eps, visit)
+		return
+	}
+
+	switch {
+	case tfs.Type() == shim.TypeSet:
+		if oldV.IsArray() && newV.IsArray() {
+			visit(tfPath, path, oldV, newV, tfs, ps)
+		}
+	case oldV.IsArray() && newV.IsArray():
+		etfs, eps := elemSchemas(tfs, ps)
+		olds, news := oldV.ArrayValue(), newV.ArrayValue()
+		for i := 0; i < len(olds) && i < len(news); i++ {
+			walkSets(appendTFPath(tfPath, strconv.Itoa(i)), fmt.Sprintf("%s[%d]", path, i),
+				olds[i], news[i], etfs, eps, visit)
+		}
+	case oldV.IsObject() && newV.IsObject():
+		res, ok := tfs.Elem().(shim.Resource)
+		if !ok {
+			return
+		}
+		var psflds map[string]*SchemaInfo
+		if ps != nil {
+			psflds = ps.Fields
+		}
+		olds, news := oldV.ObjectValue(), newV.ObjectValue()
+		for _, k := range unionKeys(olds, news) {
+			en, etf, eps := getInfoFromPulumiName(k, res.Schema(), psflds)
+			walkSets(appendTFPath(tfPath, en), appendPropertyPath(path, string(k)), olds[k], news[k], etf, eps, visit)
+		}
+	}
+}
+
+// diffSetElements matches the elements of the new set with those of the old set and records the unmatched ones in
+// diffs. See makeSetElementDiffs.
+func (c setComparer) diffSetElements(
+	path string, oldV, newV resource.PropertyValue, tfs shim.Schema, ps *SchemaInfo, replace bool,
+	diffs map[string]*pulumirpc.PropertyDiff,
+) {
+	etfs, eps := elemSchemas(tfs, ps)
+	olds, news := oldV.ArrayValue(), newV.ArrayValue()
+
+	matched := make([]bool, len(olds))
+	var added []int
+	for j, n := range news {
+		found := false
+		for i, o := range olds {
+			if !matched[i] && c.sameValue(o, n, etfs, eps) {
+				matched[i], found = true, true
+				break
+			}
+		}
+		if !found {
+			added = append(added, j)
+		}
+	}
+
+	var unmatched []int
+	for _, j := range added {
+		found := false
+		for i, o := range olds {
+			if !matched[i] && c.sameIdentity(o, news[j], etfs, eps) {
+				// The new element updates the old one: report which of their fields changed rather than
+				// replacing the whole element.
+				matched[i], found = true, true
+				ep := fmt.Sprintf("%s[%d]", path, j)
+				if !c.diffSetElementFields(ep, o, news[j], etfs, eps, replace, diffs) {
+					diffs[ep] = &pulumirpc.PropertyDiff{Kind: diffKind(pulumirpc.PropertyDiff_UPDATE, replace)}
+				}
+				break
+			}
+		}
+		if !found {
+			unmatched = append(unmatched, j)
+		}
+	}
+
+	for i := range olds {
+		if !matched[i] {
+			diffs[fmt.Sprintf("%s[%d]", path, i)] = &pulumirpc.PropertyDiff{
+				Kind: diffKind(pulumirpc.PropertyDiff_DELETE, replace),
+			}
+		}
+	}
+	for _, j := range unmatched {
+		ep := fmt.Sprintf("%s[%d]", path, j)
+		kind := pulumirpc.PropertyDiff_ADD
+		if d, ok := diffs[ep]; ok && d.Kind == diffKind(pulumirpc.PropertyDiff_DELETE, replace) {
+			// An element is deleted and another added at the same position, which has a single path.
+			kind = pulumirpc.PropertyDiff_UPDATE
+		}
+		diffs[ep] = &pulumirpc.PropertyDiff{Kind: diffKind(kind, replace)}
+	}
+}
+
+// sameIdentity reports whether an old and a new set element are the same block with different settings: both are
+// objects with required fields, and they agree on all of them.
+func (c setComparer) sameIdentity(oldV, newV resource.PropertyValue, tfs shim.Schema, ps *SchemaInfo) bool {
+	oldV, newV = unwrapSecret(oldV), unwrapSecret(newV)
+	if tfs == nil || !oldV.IsObject() || !newV.IsObject() {
+		return false
+	}
+	res, ok := tfs.Elem().(shim.Resource)
+	if !ok {
+		return false
+	}
+	var psflds map[string]*SchemaInfo
+	if ps != nil {
+		psflds = ps.Fields
+	}
+
+	required := false
+	olds, news := oldV.ObjectValue(), newV.ObjectValue()
+	for _, k := range unionKeys(olds, news) {
+		_, etf, eps := getInfoFromPulumiName(k, res.Schema(), psflds)
+		if etf == nil || !etf.Required() {
+			continue
+		}
+		if !c.sameValue(olds[k], news[k], etf, eps) {
+			return false
+		}
+		required = true
+	}
+	return required
+}
+
+// diffSetElementFields records the fields that differ between an old and a new set element in diffs, descending into
+// nested sets. It returns false if the elements are not objects and cannot be compared field by field.
+func (c setComparer) diffSetElementFields(
+	path string, oldV, newV resource.PropertyValue, tfs shim.Schema, ps *SchemaInfo, replace bool,
+	diffs map[string]*pulumirpc.PropertyDiff,
+) bool {
+	oldV, newV = unwrapSecret(oldV), unwrapSecret(newV)
+	res, ok := tfs.Elem().(shim.Resource)
+	if !ok || !oldV.IsObject() || !newV.IsObject() {
+		return false
+	}
+	var psflds map[string]*SchemaInfo
+	if ps != nil {
+		psflds = ps.Fields
+	}
+
+	found := false
+	olds, news := oldV.ObjectValue(), newV.ObjectValue()
+	for _, k := range unionKeys(olds, news) {
+		_, etf, eps := getInfoFromPulumiName(k, res.Schema(), psflds)
+		if isComputedOnly(etf) || c.sameField(olds[k], news[k], etf, eps) {
+			continue
+		}
+		found = true
+
+		fp := appendPropertyPath(path, string(k))
+		ov, nv := unwrapSecret(olds[k]), unwrapSecret(news[k])
+		switch {
+		case c.isEmptyValue(ov):
+			diffs[fp] = &pulumirpc.PropertyDiff{Kind: diffKind(pulumirpc.PropertyDiff_ADD, replace)}
+		case c.isEmptyValue(nv):
+			diffs[fp] = &pulumirpc.PropertyDiff{Kind: diffKind(pulumirpc.PropertyDiff_DELETE, replace)}
+		case etf != nil && etf.Type() == shim.TypeSet && !IsMaxItemsOne(etf, eps) && ov.IsArray() && nv.IsArray():
+			c.diffSetElements(fp, ov, nv, etf, eps, replace, diffs)
+		default:
+			diffs[fp] = &pulumirpc.PropertyDiff{Kind: diffKind(pulumirpc.PropertyDiff_UPDATE, replace)}
+		}
+	}
+	return found
+}
+
+// sameField compares a field of an old set element with the same field of a new set element. A field that the new
+// element leaves empty matches when the provider may have filled it in the old element.
+func (c setComparer) sameField(oldV, newV resource.PropertyValue, tfs shim.Schema, ps *SchemaInfo) bool {
+	if c.isEmptyValue(unwrapSecret(newV)) && tfs != nil {
+		if tfs.Computed() {
+			return true
+		}
+		if d, err := tfs.DefaultValue(); err == nil && d != nil {
+			return true
+		}
+	}
+	return c.sameValue(oldV, newV, tfs, ps)
+}
+
+// sameValue compares an old value with a new value of the given schema, treating sets as unordered.
+func (c setComparer) sameValue(oldV, newV resource.PropertyValue, tfs shim.Schema, ps *SchemaInfo) bool {
+	oldV, newV = unwrapSecret(oldV), unwrapSecret(newV)
+	if c.isEmptyValue(oldV) || c.isEmptyValue(newV) {
+		return c.isEmptyValue(oldV) && c.isEmptyValue(newV)
+	}
+
+	if IsMaxItemsOne(tfs, ps) {
+		etfs, eps := elemSchemas(tfs, ps)
+		return c.sameValue(oldV, newV, etfs, eps)
+	}
+
+	switch {
+	case oldV.IsArray() && newV.IsArray():
+		etfs, eps := elemSchemas(tfs, ps)
+		olds, news := oldV.ArrayValue(), newV.ArrayValue()
+		if len(olds) != len(news) {
+			return false
+		}
+		if tfs != nil && tfs.Type() == shim.TypeSet {
+			matched := make([]bool, len(olds))
+			for _, n := range news {
+				found := false
+				for i, o := range olds {
+					if !matched[i] && c.sameValue(o, n, etfs, eps) {
+						matched[i], found = true, true
+						break
+					}
+				}
+				if !found {
+					return false
+				}
+			}
+			return true
+		}
+		for i := range olds {
+			if !c.sameValue(olds[i], news[i], etfs, eps) {
+				return false
+			}
+		}
+		return true
+	case oldV.IsObject() && newV.IsObject():
+		olds, news := oldV.ObjectValue(), newV.ObjectValue()
+		if tfs != nil {
+			if res, ok := tfs.Elem().(shim.Resource); ok {
+				var psflds map[string]*SchemaInfo
+				if ps != nil {
+					psflds = ps.Fields
+				}
+				for _, k := range unionKeys(olds, news) {
+					_, etf, eps := getInfoFromPulumiName(k, res.Schema(), psflds)
+					if !isComputedOnly(etf) && !c.sameField(olds[k], news[k], etf, eps) {
+						return false
+					}
+				}
+				return true
+			}
+		}
+		etfs, eps := elemSchemas(tfs, ps)
+		for _, k := range unionKeys(olds, news) {
+			if !c.sameValue(olds[k], news[k], etfs, eps) {
+				return false
+			}
+		}
+		return true
+	case oldV.IsNumber() && newV.IsString(), oldV.IsString() && newV.IsNumber(),
+		oldV.IsBool() && newV.IsString(), oldV.IsString() && newV.IsBool():
+		// Terraform may record a value with a different primitive type than the one given in the inputs.
+		return fmt.Sprint(oldV.V) == fmt.Sprint(newV.V)
+	default:
+		return oldV.DeepEquals(newV)
+	}
+}
+
+// isComputedOnly returns true for fields that are set by the provider and cannot be given in the inputs.
+func isComputedOnly(tfs shim.Schema) bool {
+	return tfs != nil && tfs.Computed() && !tfs.Optional() && !tfs.Required()
+}
+
+// isEmptyValue returns true for null values, and for zero values unless they are distinct from null.
+func (c setComparer) isEmptyValue(v resource.PropertyValue) bool {
+	switch {
+	case v.IsNull():
+		return true
+	case !c.zeroIsNull:
+		return false
+	case v.IsString():
+		return v.StringValue() == ""
+	case v.IsBool():
+		return !v.BoolValue()
+	case v.IsNumber():
+		return v.NumberValue() == 0
+	case v.IsArray():
+		return len(v.ArrayValue()) == 0
+	case v.IsObject():
+		return len(v.ObjectValue()) == 0
+	default:
+		return false
+	}
+}
+
+func unwrapSecret(v resource.PropertyValue) resource.PropertyValue {
+	for v.IsSecret() {
+		v = v.SecretValue().Element
+	}
+	return v
+}
+
+func diffKind(kind pulumirpc.PropertyDiff_Kind, replace bool) pulumirpc.PropertyDiff_Kind {
+	if !replace {
+		return kind
+	}
+	switch kind {
+	case pulumirpc.PropertyDiff_ADD:
+		return pulumirpc.PropertyDiff_ADD_REPLACE
+	case pulumirpc.PropertyDiff_DELETE:
+		return pulumirpc.PropertyDiff_DELETE_REPLACE
+	default:
+		return pulumirpc.PropertyDiff_UPDATE_REPLACE
+	}
+}
+
+// unionKeys returns the keys of both maps in a stable order.
+func unionKeys(a, b resource.PropertyMap) []resource.PropertyKey {
+	keys := a.StableKeys()
+	for _, k := range b.StableKeys() {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// appendTFPath returns a copy of tfPath with step appended, so that paths handed to visitors are never shared.
+func appendTFPath(tfPath []string, step string) []string {
+	return append(append(make([]string, 0, len(tfPath)+1), tfPath...), step)
+}
+
+// appendPropertyPath appends an object key to a Pulumi property path, quoting it if needed.
+func appendPropertyPath(path, key string) string {
+	if strings.ContainsAny(key, `."[]`) {
+		return fmt.Sprintf(`%s.["%s"]`, path, strings.ReplaceAll(key, `"`, `\"`))
+	}
+	return fmt.Sprintf("%s.%s", path, key)
+}
@@ -1123,6 +1123,7 @@ func TestMaxItemsOneSetUpdateReplace(t *testing.T) {
 		pulumirpc.DiffResponse_DIFF_SOME)
 }
 
+// The element whose required field changed is deleted and another added at the same position.
 func TestSetNestedUpdate(t *testing.T) {
 	diffTest(t,
 		map[string]*schema.Schema{
@@ -1145,7 +1146,7 @@ func TestSetNestedUpdate(t *testing.T) {
 			"outp": "bar",
 		},
 		map[string]DiffKind{
-			"prop[0].nest": U,
+			"prop[0]": U,
 		},
 		pulumirpc.DiffResponse_DIFF_SOME)
 }
@@ -1172,7 +1173,7 @@ func TestSetNestedUpdateReplace(t *testing.T) {
 			"outp": "bar",
 		},
 		map[string]DiffKind{
-			"prop[0].nest": UR,
+			"prop[0]": UR,
 		},
 		pulumirpc.DiffResponse_DIFF_SOME)
 }
@@ -1205,6 +1206,130 @@ func TestSetNestedIgnore(t *testing.T) {
 	}
 }
 
+// Changing one element of a set of blocks reports only that element, whatever the order of the other elements.
+func TestSetNestedElementDiff(t *testing.T) {
+	rule := func(name string, port float64) resource.PropertyValue {
+		return resource.NewObjectProperty(resource.PropertyMap{
+			"name": resource.NewStringProperty(name),
+			"port": resource.NewNumberProperty(port),
+		})
+	}
+	tfs := map[string]*schema.Schema{
+		"rule": {
+			Type:     schema.TypeSet,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {Type: schema.TypeString, Required: true},
+					"port": {Type: schema.TypeInt, Optional: true},
+				},
+			},
+		},
+	}
+	state := resource.PropertyMap{
+		"rules": resource.NewArrayProperty([]resource.PropertyValue{rule("a", 1), rule("b", 2), rule("c", 3)}),
+	}
+
+	t.Run("update", func(t *testing.T) {
+		diffTest2(t, diffTestCase{
+			resourceSchema: tfs,
+			state:          state,
+			inputs: resource.PropertyMap{
+				"rules": resource.NewArrayProperty([]resource.PropertyValue{rule("c", 3), rule("b", 5), rule("a", 1)}),
+			},
+			expected: map[string]*pulumirpc.PropertyDiff{
+				"rules[1].port": {Kind: U},
+			},
+			expectedDiffChanges: pulumirpc.DiffResponse_DIFF_SOME,
+		})
+	})
+
+	t.Run("update at another position", func(t *testing.T) {
+		diffTest2(t, diffTestCase{
+			resourceSchema: tfs,
+			state:          state,
+			inputs: resource.PropertyMap{
+				"rules": resource.NewArrayProperty([]resource.PropertyValue{rule("b", 5), rule("c", 3), rule("a", 1)}),
+			},
+			expected: map[string]*pulumirpc.PropertyDiff{
+				"rules[0].port": {Kind: U},
+			},
+			expectedDiffChanges: pulumirpc.DiffResponse_DIFF_SOME,
+		})
+	})
+
+	t.Run("replace at the same position", func(t *testing.T) {
+		diffTest2(t, diffTestCase{
+			resourceSchema: tfs,
+			state:          state,
+			inputs: resource.PropertyMap{
+				"rules": resource.NewArrayProperty([]resource.PropertyValue{rule("a", 1), rule("d", 2), rule("c", 3)}),
+			},
+			expected: map[string]*pulumirpc.PropertyDiff{
+				"rules[1]": {Kind: U},
+			},
+			expectedDiffChanges: pulumirpc.DiffResponse_DIFF_SOME,
+		})
+	})
+
+	t.Run("add and delete", func(t *testing.T) {
+		diffTest2(t, diffTestCase{
+			resourceSchema: tfs,
+			state:          state,
+			inputs: resource.PropertyMap{
+				"rules": resource.NewArrayProperty([]resource.PropertyValue{rule("c", 3), rule("a", 1), rule("d", 4)}),
+			},
+			expected: map[string]*pulumirpc.PropertyDiff{
+				"rules[1]": {Kind: D},
+				"rules[2]": {Kind: A},
+			},
+			expectedDiffChanges: pulumirpc.DiffResponse_DIFF_SOME,
+		})
+	})
+}
+
+func TestMakeSetElementDiffsZeroValues(t *testing.T) {
+	tfs := shimv1.NewSchemaMap(map[string]*schema.Schema{
+		"rule": {
+			Type:     schema.TypeSet,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name":    {Type: schema.TypeString, Required: true},
+					"enabled": {Type: schema.TypeBool, Optional: true},
+				},
+			},
+		},
+	})
+	olds := resource.PropertyMap{
+		"rules": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.NewObjectProperty(resource.PropertyMap{
+				"name":    resource.NewStringProperty("a"),
+				"enabled": resource.NewBoolProperty(false),
+			}),
+		}),
+	}
+	news := resource.PropertyMap{
+		"rules": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.NewObjectProperty(resource.PropertyMap{
+				"name":    resource.NewStringProperty("a"),
+				"enabled": resource.NewNullProperty(),
+			}),
+		}),
+	}
+	changed := func([]string) (bool, bool) { return true, false }
+
+	t.Run("sdkv2", func(t *testing.T) {
+		assert.Empty(t, makeSetElementDiffs(tfs, nil, olds, news, changed, setComparer{zeroIsNull: true}))
+	})
+
+	t.Run("pf", func(t *testing.T) {
+		assert.Equal(t, map[string]plugin.PropertyDiff{
+			"rules[0].enabled": {Kind: plugin.DiffDelete},
+		}, MakeSetElementDiffs(tfs, nil, olds, news, changed))
+	})
+}
+
 func TestComputedSimpleUpdate(t *testing.T) {
 	diffTest(t,
 		map[string]*schema.Schema{
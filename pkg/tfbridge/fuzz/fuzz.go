@@ -0,0 +1,421 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fuzz is a property-based test harness for the value conversions of bridged providers.
+//
+// It generates random resource inputs from the schema of each resource and converts them the way the bridge does
+// when checking and refreshing resources, in-process and without the Terraform or Pulumi CLIs:
+//
+//	func TestConversions(t *testing.T) {
+//		fuzz.Provider(t, myprovider.Provider(), fuzz.Options{})
+//	}
+//
+// The number of generated inputs is controlled by the -rapid.checks flag.
+package fuzz
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"pgregory.net/rapid"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge/info"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+// Options configure the generated inputs.
+type Options struct {
+	// Do not generate unknown values.
+	NoUnknowns bool
+
+	// Do not generate secret values.
+	NoSecrets bool
+
+	// The maximum nesting depth of generated values. Defaults to 3.
+	MaxDepth int
+}
+
+// Provider fuzzes the value conversions of every resource of the provider, each in its own sub-test.
+func Provider(t *testing.T, prov info.Provider, opts Options) {
+	names := make([]string, 0, len(prov.Resources))
+	for name := range prov.Resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			Resource(t, prov, name, opts)
+		})
+	}
+}
+
+// Resource fuzzes the value conversions of the resource with the given Terraform name.
+//
+// The generated inputs keep their secret markers, as Check receives them. They are converted with MakeTerraformInputs
+// and back with MakeTerraformOutputs, as Check does, their secret markers are restored with MarkInputSecrets, and they
+// are then refreshed with ExtractInputsFromOutputs. The harness fails if a conversion errors or panics, or if:
+//
+//   - a known input value does not round-trip, including the flattening of MaxItemsOne properties;
+//   - an unknown input value is not unknown after the round-trip;
+//   - a secret input is no longer secret after the conversions or the refresh, or a sensitive property is not
+//     marked as secret;
+//   - a default from SchemaInfo.Default is not applied, or is not recorded as a default after a refresh.
+func Resource(t *testing.T, prov info.Provider, tfName string, opts Options) {
+	res, ok := prov.P.ResourcesMap().GetOk(tfName)
+	if !ok {
+		t.Fatalf("resource %q is not in the schema of the provider", tfName)
+	}
+	resInfo := prov.Resources[tfName]
+	if resInfo == nil {
+		resInfo = &info.Resource{}
+	}
+	if opts.MaxDepth == 0 {
+		opts.MaxDepth = 3
+	}
+
+	c := &checker{
+		prov:   prov,
+		tok:    resInfo.Tok,
+		schema: res.Schema(),
+		fields: resInfo.GetFields(),
+	}
+	g := &generator{opts: opts}
+
+	rapid.Check(t, func(t *rapid.T) {
+		inputs := g.object(c.schema, c.fields, opts.MaxDepth).Draw(t, "inputs").ObjectValue()
+		c.check(t, inputs)
+	})
+}
+
+type checker struct {
+	prov   info.Provider
+	tok    tokens.Type
+	schema shim.SchemaMap
+	fields map[string]*info.Schema
+}
+
+func (c *checker) check(t *rapid.T, inputs resource.PropertyMap) {
+	ctx := context.Background()
+
+	// Pass the inputs through the gRPC protocol the way Check receives them.
+	wire, err := plugin.MarshalProperties(inputs, plugin.MarshalOptions{KeepUnknowns: true, KeepSecrets: true})
+	if err != nil {
+		t.Fatalf("failed to marshal the inputs: %v", err)
+	}
+	news, err := plugin.UnmarshalProperties(wire, plugin.MarshalOptions{
+		KeepUnknowns: true, KeepSecrets: true, SkipNulls: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to unmarshal the inputs: %v", err)
+	}
+	for key, in := range inputs {
+		if in.ContainsSecrets() && !news[key].ContainsSecrets() {
+			t.Fatalf("secret input %q lost its secret marker on the wire", key)
+		}
+	}
+
+	urn := resource.NewURN("stack", "project", "", c.tok, "name")
+	instance := &tfbridge.PulumiResource{URN: urn, Properties: news, Seed: []byte("seed")}
+	tfInputs, assets, err := tfbridge.MakeTerraformInputs(ctx, instance, nil, nil, news, c.schema, c.fields)
+	if err != nil {
+		t.Fatalf("MakeTerraformInputs failed: %v", err)
+	}
+
+	pinputs := tfbridge.MakeTerraformOutputs(ctx, c.prov.P, tfInputs, c.schema, c.fields, assets, true)
+	pinputs = tfbridge.MarkSchemaSecrets(ctx, c.schema, c.fields, resource.NewObjectProperty(pinputs)).ObjectValue()
+	pinputs = tfbridge.MarkInputSecrets(
+		resource.NewObjectProperty(pinputs), resource.NewObjectProperty(news)).ObjectValue()
+
+	for key, in := range inputs {
+		tfName, tfs, ps := c.lookup(key)
+		out, ok := pinputs[key]
+		if !ok {
+			if !isEmpty(in) {
+				t.Fatalf("input %q was dropped", key)
+			}
+			continue
+		}
+		checkValue(t, resource.PropertyPath{string(key)}, in, out, tfs, ps)
+
+		if in.ContainsSecrets() && !out.ContainsUnknowns() && !out.ContainsSecrets() {
+			t.Fatalf("secret input %q is not a secret after MakeTerraformOutputs", key)
+		}
+
+		if tfs.Sensitive() && !out.IsNull() && !out.ContainsUnknowns() && !out.IsSecret() {
+			t.Fatalf("sensitive input %q (%s) is not a secret", key, tfName)
+		}
+	}
+
+	defaults := map[string]bool{}
+	if d, ok := pinputs["__defaults"]; ok && d.IsArray() {
+		for _, k := range d.ArrayValue() {
+			defaults[k.StringValue()] = true
+		}
+	}
+	for tfName, fld := range c.fields {
+		key := tfbridge.TerraformToPulumiNameV2(tfName, c.schema, c.fields)
+		if fld == nil || fld.Default == nil || fld.Name != "" {
+			continue
+		}
+		if _, set := news[resource.PropertyKey(key)]; set {
+			continue
+		}
+		if _, applied := tfInputs[tfName]; !applied {
+			continue
+		}
+		if _, ok := pinputs[resource.PropertyKey(key)]; !ok || !defaults[key] {
+			t.Fatalf("default for %q was not recorded in the inputs", key)
+		}
+	}
+
+	// Refreshing a resource whose state matches its inputs must keep the inputs and their defaults.
+	refreshed, err := tfbridge.ExtractInputsFromOutputs(deepCopy(pinputs), pinputs, c.schema, c.fields, true)
+	if err != nil {
+		t.Fatalf("ExtractInputsFromOutputs failed: %v", err)
+	}
+	if !refreshed["__defaults"].DeepEquals(pinputs["__defaults"]) {
+		t.Fatalf("refresh changed the defaults from %v to %v", pinputs["__defaults"], refreshed["__defaults"])
+	}
+	for key, in := range pinputs {
+		out, ok := refreshed[key]
+		if !ok {
+			t.Fatalf("refresh dropped input %q", key)
+		}
+		if in.ContainsSecrets() && !out.ContainsSecrets() {
+			t.Fatalf("secret input %q is not a secret after ExtractInputsFromOutputs", key)
+		}
+	}
+}
+
+func (c *checker) lookup(key resource.PropertyKey) (string, shim.Schema, *info.Schema) {
+	tfName := tfbridge.PulumiToTerraformName(string(key), c.schema, c.fields)
+	return tfName, c.schema.Get(tfName), c.fields[tfName]
+}
+
+// checkValue checks that the input value in survived its conversion to out.
+func checkValue(
+	t *rapid.T, path resource.PropertyPath, in, out resource.PropertyValue, tfs shim.Schema, ps *info.Schema,
+) {
+	if in.IsSecret() {
+		in = in.SecretValue().Element
+	}
+	if out.IsSecret() {
+		out = out.SecretValue().Element
+	}
+
+	switch {
+	case in.IsComputed():
+		// Unknown collections may be converted to collections of unknown elements.
+		if !out.ContainsUnknowns() {
+			t.Fatalf("unknown input %s became %v", path, out)
+		}
+		return
+	case out.IsComputed():
+		if !in.ContainsUnknowns() {
+			t.Fatalf("known input %s became unknown", path)
+		}
+		return
+	case isEmpty(in) && isEmpty(out):
+		// Terraform does not distinguish empty collections from missing ones.
+		return
+	}
+
+	if tfbridge.IsMaxItemsOne(tfs, ps) {
+		if in.IsArray() {
+			t.Fatalf("input %s of a MaxItemsOne property was not flattened by the generator", path)
+		}
+		if out.IsArray() {
+			t.Fatalf("output %s of a MaxItemsOne property is not flattened: %v", path, out)
+		}
+		tfs, ps = elemSchemas(tfs, ps)
+	}
+
+	switch {
+	case in.IsArray():
+		if !out.IsArray() {
+			t.Fatalf("input %s became %v", path, out)
+		}
+		checkArray(t, path, in.ArrayValue(), out.ArrayValue(), tfs, ps)
+	case in.IsObject():
+		if !out.IsObject() {
+			t.Fatalf("input %s became %v", path, out)
+		}
+		checkObject(t, path, in.ObjectValue(), out.ObjectValue(), tfs, ps)
+	default:
+		if !in.DeepEquals(out) {
+			t.Fatalf("input %s changed from %v to %v", path, in, out)
+		}
+	}
+}
+
+func checkArray(
+	t *rapid.T, path resource.PropertyPath, in, out []resource.PropertyValue, tfs shim.Schema, ps *info.Schema,
+) {
+	etfs, eps := elemSchemas(tfs, ps)
+	if tfs != nil && tfs.Type() == shim.TypeSet {
+		// Sets are unordered and may drop duplicates, so only compare the known elements as sets.
+		if resource.NewArrayProperty(in).ContainsUnknowns() {
+			return
+		}
+		for i, e := range in {
+			if !containsElement(out, e) {
+				t.Fatalf("element %v of set %s was lost: %v", e, append(path, i), out)
+			}
+		}
+		for _, e := range out {
+			if !containsElement(in, e) {
+				t.Fatalf("set %s gained element %v", path, e)
+			}
+		}
+		return
+	}
+
+	if len(in) != len(out) {
+		t.Fatalf("input %s changed length from %d to %d", path, len(in), len(out))
+	}
+	for i := range in {
+		checkValue(t, append(path, i), in[i], out[i], etfs, eps)
+	}
+}
+
+func checkObject(
+	t *rapid.T, path resource.PropertyPath, in, out resource.PropertyMap, tfs shim.Schema, ps *info.Schema,
+) {
+	if tfs != nil {
+		if r, ok := tfs.Elem().(shim.Resource); ok {
+			var fields map[string]*info.Schema
+			if ps != nil {
+				fields = ps.Fields
+			}
+			for key, v := range in {
+				tfName := tfbridge.PulumiToTerraformName(string(key), r.Schema(), fields)
+				o, ok := out[key]
+				if !ok && !isEmpty(v) {
+					t.Fatalf("input %s was dropped", append(path, string(key)))
+				}
+				if ok {
+					checkValue(t, append(path, string(key)), v, o, r.Schema().Get(tfName), fields[tfName])
+				}
+			}
+			return
+		}
+	}
+
+	// A map: the keys are not renamed.
+	etfs, eps := elemSchemas(tfs, ps)
+	for key, v := range in {
+		o, ok := out[key]
+		if !ok && !isEmpty(v) {
+			t.Fatalf("input %s was dropped", append(path, string(key)))
+		}
+		if ok {
+			checkValue(t, append(path, string(key)), v, o, etfs, eps)
+		}
+	}
+}
+
+func containsElement(values []resource.PropertyValue, v resource.PropertyValue) bool {
+	v = stripSecrets(v)
+	for _, e := range values {
+		if stripSecrets(e).DeepEquals(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func stripSecrets(v resource.PropertyValue) resource.PropertyValue {
+	switch {
+	case v.IsSecret():
+		return stripSecrets(v.SecretValue().Element)
+	case v.IsArray():
+		arr := make([]resource.PropertyValue, len(v.ArrayValue()))
+		for i, e := range v.ArrayValue() {
+			arr[i] = stripSecrets(e)
+		}
+		return resource.NewArrayProperty(arr)
+	case v.IsObject():
+		obj := make(resource.PropertyMap, len(v.ObjectValue()))
+		for k, e := range v.ObjectValue() {
+			obj[k] = stripSecrets(e)
+		}
+		return resource.NewObjectProperty(obj)
+	default:
+		return v
+	}
+}
+
+func deepCopy(m resource.PropertyMap) resource.PropertyMap {
+	var cp func(resource.PropertyValue) resource.PropertyValue
+	cp = func(v resource.PropertyValue) resource.PropertyValue {
+		switch {
+		case v.IsSecret():
+			return resource.MakeSecret(cp(v.SecretValue().Element))
+		case v.IsArray():
+			arr := make([]resource.PropertyValue, len(v.ArrayValue()))
+			for i, e := range v.ArrayValue() {
+				arr[i] = cp(e)
+			}
+			return resource.NewArrayProperty(arr)
+		case v.IsObject():
+			return resource.NewObjectProperty(deepCopy(v.ObjectValue()))
+		default:
+			return v
+		}
+	}
+	result := make(resource.PropertyMap, len(m))
+	for k, v := range m {
+		result[k] = cp(v)
+	}
+	return result
+}
+
+func isEmpty(v resource.PropertyValue) bool {
+	switch {
+	case v.IsSecret():
+		return isEmpty(v.SecretValue().Element)
+	case v.IsNull():
+		return true
+	case v.IsArray():
+		return len(v.ArrayValue()) == 0
+	case v.IsObject():
+		return len(v.ObjectValue()) == 0
+	default:
+		return false
+	}
+}
+
+func elemSchemas(tfs shim.Schema, ps *info.Schema) (shim.Schema, *info.Schema) {
+	var etfs shim.Schema
+	if tfs != nil {
+		if e, ok := tfs.Elem().(shim.Schema); ok {
+			etfs = e
+		} else if r, ok := tfs.Elem().(shim.Resource); ok {
+			etfs = (&schema.Schema{Elem: r}).Shim()
+		}
+	}
+	var eps *info.Schema
+	if ps != nil {
+		eps = ps.Elem
+	}
+	return etfs, eps
+}
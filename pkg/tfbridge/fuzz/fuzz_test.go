@@ -0,0 +1,59 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fuzz
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/internal/testprovider"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge/info"
+	shimv2 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2"
+)
+
+func TestProvider(t *testing.T) {
+	maxItemsOne := false
+	prov := info.Provider{
+		P: shimv2.NewProvider(testprovider.ProviderV2()),
+		Resources: map[string]*info.Resource{
+			"example_resource": {
+				Tok: "example:index:ExampleResource",
+				Fields: map[string]*info.Schema{
+					"string_property_value": {Default: &info.Default{Value: "default"}},
+					"nested_resources":      {MaxItemsOne: &maxItemsOne},
+				},
+			},
+			"second_resource": {
+				Tok: "example:index:SecondResource",
+				Fields: map[string]*info.Schema{
+					"float_property_value": {Default: &info.Default{Value: 1.5}},
+				},
+			},
+			"nested_secret_resource": {Tok: "example:index:NestedSecretResource"},
+		},
+	}
+
+	Provider(t, prov, Options{})
+}
+
+func TestProviderWithoutUnknowns(t *testing.T) {
+	prov := info.Provider{
+		P: shimv2.NewProvider(testprovider.ProviderV2()),
+		Resources: map[string]*info.Resource{
+			"example_resource": {Tok: "example:index:ExampleResource"},
+		},
+	}
+
+	Provider(t, prov, Options{NoUnknowns: true, NoSecrets: true, MaxDepth: 2})
+}
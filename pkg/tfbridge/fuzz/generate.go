@@ -0,0 +1,162 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fuzz
+
+import (
+	"sort"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"pgregory.net/rapid"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge/info"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+var stringSchema = (&schema.Schema{Type: shim.TypeString}).Shim()
+
+// generator draws Pulumi property values that conform to a Terraform schema.
+type generator struct {
+	opts Options
+}
+
+// object draws the inputs of a resource or nested block. Computed-only properties are never set, required properties
+// are always set and optional properties are set at random.
+func (g *generator) object(
+	sch shim.SchemaMap, fields map[string]*info.Schema, depth int,
+) *rapid.Generator[resource.PropertyValue] {
+	var keys []string
+	sch.Range(func(key string, tfs shim.Schema) bool {
+		if generated(tfs, fields[key]) {
+			keys = append(keys, key)
+		}
+		return true
+	})
+	sort.Strings(keys)
+	if len(keys) == 0 {
+		return rapid.Just(resource.NewObjectProperty(resource.PropertyMap{}))
+	}
+
+	return rapid.Custom(func(t *rapid.T) resource.PropertyValue {
+		obj := resource.PropertyMap{}
+		for _, key := range keys {
+			tfs, ps := sch.Get(key), fields[key]
+			name := tfbridge.TerraformToPulumiNameV2(key, sch, fields)
+			if !tfs.Required() && !rapid.Bool().Draw(t, name+".set") {
+				continue
+			}
+			obj[resource.PropertyKey(name)] = g.value(tfs, ps, depth).Draw(t, name)
+		}
+		return resource.NewObjectProperty(obj)
+	})
+}
+
+// value draws the value of a property, which may be wrapped as a secret or be unknown.
+func (g *generator) value(tfs shim.Schema, ps *info.Schema, depth int) *rapid.Generator[resource.PropertyValue] {
+	known := g.known(tfs, ps, depth)
+	return rapid.Custom(func(t *rapid.T) resource.PropertyValue {
+		if !g.opts.NoUnknowns && rapid.IntRange(0, 9).Draw(t, "unknown") == 0 {
+			return resource.MakeComputed(resource.NewStringProperty(""))
+		}
+		v := known.Draw(t, "value")
+		if !g.opts.NoSecrets && rapid.IntRange(0, 9).Draw(t, "secret") == 0 {
+			return resource.MakeSecret(v)
+		}
+		return v
+	})
+}
+
+func (g *generator) known(tfs shim.Schema, ps *info.Schema, depth int) *rapid.Generator[resource.PropertyValue] {
+	if tfbridge.IsMaxItemsOne(tfs, ps) {
+		etfs, eps := elemSchemas(tfs, ps)
+		return g.elem(etfs, eps, depth)
+	}
+
+	switch tfs.Type() {
+	case shim.TypeBool:
+		return rapid.Map(rapid.Bool(), resource.NewBoolProperty)
+	case shim.TypeInt:
+		return rapid.Map(rapid.IntRange(-1000, 1000), func(i int) resource.PropertyValue {
+			return resource.NewNumberProperty(float64(i))
+		})
+	case shim.TypeFloat:
+		return rapid.Map(rapid.Float64Range(-1000, 1000), resource.NewNumberProperty)
+	case shim.TypeString:
+		return rapid.Map(rapid.StringMatching(`[a-zA-Z0-9 _-]{0,8}`), resource.NewStringProperty)
+	case shim.TypeList, shim.TypeSet:
+		etfs, eps := elemSchemas(tfs, ps)
+		elems := rapid.SliceOfN(g.elem(etfs, eps, depth), 0, g.maxElems(depth))
+		return rapid.Map(elems, resource.NewArrayProperty)
+	case shim.TypeMap:
+		if r, ok := tfs.Elem().(shim.Resource); ok {
+			// A legacy object nested in a map, whose fields are listed by the SchemaInfo of the map itself.
+			var fields map[string]*info.Schema
+			if ps != nil {
+				fields = ps.Fields
+			}
+			return g.object(r.Schema(), fields, depth-1)
+		}
+		etfs, eps := elemSchemas(tfs, ps)
+		elems := rapid.MapOfN(rapid.StringMatching(`[a-z]{1,5}`), g.elem(etfs, eps, depth), 0, g.maxElems(depth))
+		return rapid.Map(elems, func(m map[string]resource.PropertyValue) resource.PropertyValue {
+			obj := make(resource.PropertyMap, len(m))
+			for k, v := range m {
+				obj[resource.PropertyKey(k)] = v
+			}
+			return resource.NewObjectProperty(obj)
+		})
+	default:
+		return rapid.Just(resource.NewNullProperty())
+	}
+}
+
+// elem draws an element of a collection, which is either a nested block or a plain value.
+func (g *generator) elem(etfs shim.Schema, eps *info.Schema, depth int) *rapid.Generator[resource.PropertyValue] {
+	if etfs == nil {
+		// Collections without an element type hold strings.
+		return g.value(stringSchema, eps, depth-1)
+	}
+	if r, ok := etfs.Elem().(shim.Resource); ok {
+		var fields map[string]*info.Schema
+		if eps != nil {
+			fields = eps.Fields
+		}
+		return g.object(r.Schema(), fields, depth-1)
+	}
+	return g.value(etfs, eps, depth-1)
+}
+
+func (g *generator) maxElems(depth int) int {
+	if depth <= 1 {
+		return 0
+	}
+	return 2
+}
+
+// generated returns whether inputs are generated for a property. Assets and transformed properties cannot be
+// generated from the schema alone.
+func generated(tfs shim.Schema, ps *info.Schema) bool {
+	if !tfs.Required() && !tfs.Optional() {
+		return false
+	}
+	if tfs.Removed() != "" {
+		return false
+	}
+	if ps != nil && (ps.Asset != nil || ps.Transform != nil || ps.Removed || ps.Type != "") {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+)
+
+// importSuggestion is a resource returned by a Terraform importer next to the resource being imported, such as the
+// rules of an AWS security group. It is formatted as an entry of a `pulumi import --file` document.
+type importSuggestion struct {
+	Type tokens.Type `json:"type"`
+	Name string      `json:"name"`
+	ID   string      `json:"id"`
+}
+
+// importSuggestions converts the secondary states returned by a Terraform importer to import suggestions. States of
+// Terraform resources that are not mapped by the provider are skipped.
+func (p *Provider) importSuggestions(
+	ctx context.Context, urn resource.URN, states []shim.InstanceState,
+) []importSuggestion {
+	byTFName := make(map[string]tokens.Type, len(p.resources))
+	for tok, res := range p.resources {
+		byTFName[res.TFName] = tok
+	}
+
+	counts := map[tokens.Type]int{}
+	var suggestions []importSuggestion
+	for _, state := range states {
+		tok, ok := byTFName[state.Type()]
+		if !ok {
+			glog.V(9).Infof("importer for %s returned a state for unmapped resource %s", urn, state.Type())
+			continue
+		}
+		res := p.resources[tok]
+
+		props, err := MakeTerraformResult(ctx, p.tf, state, res.TF.Schema(), res.Schema.Fields, nil, p.supportsSecrets)
+		if err != nil {
+			glog.V(9).Infof("failed to convert the imported state of %s %s: %v", tok, state.ID(), err)
+			continue
+		}
+		if res.Schema.TransformOutputs != nil {
+			if props, err = res.Schema.TransformOutputs(ctx, props); err != nil {
+				glog.V(9).Infof("failed to transform the imported state of %s %s: %v", tok, state.ID(), err)
+				continue
+			}
+		}
+		// The state is only needed to compute the ID; `pulumi import` reads the resource again.
+		id, err := res.computeID(ctx, state, props)
+		if err != nil {
+			glog.V(9).Infof("failed to compute the ID of the imported %s %s: %v", tok, state.ID(), err)
+			continue
+		}
+
+		name := fmt.Sprintf("%s-%s", urn.Name(), strings.ToLower(string(tok.Name())))
+		if n := counts[tok]; n > 0 {
+			name = fmt.Sprintf("%s-%d", name, n)
+		}
+		counts[tok]++
+
+		suggestions = append(suggestions, importSuggestion{Type: tok, Name: name, ID: id})
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Type < suggestions[j].Type
+	})
+	return suggestions
+}
+
+// suggestSecondaryImports warns the user about the resources returned by a Terraform importer next to the resource
+// being imported, so that they do not silently remain unmanaged.
+func (p *Provider) suggestSecondaryImports(ctx context.Context, urn resource.URN, states []shim.InstanceState) {
+	suggestions := p.importSuggestions(ctx, urn, states)
+	if len(suggestions) == 0 {
+		return
+	}
+	GetLogger(ctx).Warn(formatImportSuggestions(urn, suggestions))
+}
+
+func formatImportSuggestions(urn resource.URN, suggestions []importSuggestion) string {
+	doc, err := json.MarshalIndent(struct {
+		Resources []importSuggestion `json:"resources"`
+	}{suggestions}, "", "  ")
+	if err != nil {
+		// The suggestions only contain strings, so this should never happen.
+		return fmt.Sprintf("importing %s also found %d related resources", urn.Name(), len(suggestions))
+	}
+	return fmt.Sprintf("Importing %s also found %d related resources that are not managed by Pulumi. "+
+		"To import them, save the following to a file and run `pulumi import --file <file>`:\n%s",
+		urn.Name(), len(suggestions), doc)
+}
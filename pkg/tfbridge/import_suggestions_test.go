@@ -0,0 +1,115 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	shimv2 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2"
+)
+
+func TestImportSuggestions(t *testing.T) {
+	rule := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"port": {Type: schema.TypeInt, Optional: true},
+		},
+	}
+	tfProvider := &schema.Provider{
+		ResourcesMap: map[string]*schema.Resource{
+			"example_group": {
+				Schema: map[string]*schema.Schema{
+					"name": {Type: schema.TypeString, Optional: true},
+				},
+				Importer: &schema.ResourceImporter{
+					StateContext: func(
+						_ context.Context, d *schema.ResourceData, _ interface{},
+					) ([]*schema.ResourceData, error) {
+						results := []*schema.ResourceData{d}
+						for _, r := range []struct {
+							name string
+							port int
+						}{{"http", 80}, {"https", 443}} {
+							data := rule.Data(nil)
+							data.SetType("example_group_rule")
+							data.SetId(d.Id() + "-" + r.name)
+							require.NoError(t, data.Set("port", r.port))
+							results = append(results, data)
+						}
+						unmapped := rule.Data(nil)
+						unmapped.SetType("example_unmapped")
+						unmapped.SetId("unmapped")
+						return append(results, unmapped), nil
+					},
+				},
+			},
+			"example_group_rule": rule,
+		},
+	}
+
+	shimProv := shimv2.NewProvider(tfProvider)
+	p := &Provider{
+		tf: shimProv,
+		info: ProviderInfo{
+			P: shimProv,
+			Resources: map[string]*ResourceInfo{
+				"example_group":      {Tok: "example:index:Group"},
+				"example_group_rule": {Tok: "example:index:GroupRule"},
+			},
+		},
+	}
+	p.initResourceMaps()
+
+	ctx := context.Background()
+	urn := resource.URN("urn:pulumi:dev::stack::example:index:Group::web")
+	res := p.resources["example:index:Group"]
+
+	primary, secondary, err := res.runTerraformImporter(ctx, "sg", p)
+	require.NoError(t, err)
+	assert.Equal(t, "sg", primary.ID())
+	require.Len(t, secondary, 3)
+
+	suggestions := p.importSuggestions(ctx, urn, secondary)
+	require.Len(t, suggestions, 2)
+	assert.Equal(t, importSuggestion{
+		Type: "example:index:GroupRule",
+		Name: "web-grouprule",
+		ID:   "sg-http",
+	}, suggestions[0])
+	assert.Equal(t, "web-grouprule-1", suggestions[1].Name)
+	assert.Equal(t, "sg-https", suggestions[1].ID)
+
+	assert.Equal(t, "Importing web also found 2 related resources that are not managed by Pulumi. "+
+		"To import them, save the following to a file and run `pulumi import --file <file>`:\n"+
+		`{
+  "resources": [
+    {
+      "type": "example:index:GroupRule",
+      "name": "web-grouprule",
+      "id": "sg-http"
+    },
+    {
+      "type": "example:index:GroupRule",
+      "name": "web-grouprule-1",
+      "id": "sg-https"
+    }
+  ]
+}`, formatImportSuggestions(urn, suggestions))
+}
@@ -134,10 +134,30 @@ type ResourceInfo = info.Resource
 
 type ComputeID = info.ComputeID
 
+// MethodInfo is a resource method implemented in Go.
+type MethodInfo = info.Method
+
+// MethodCall implements a resource method.
+type MethodCall = info.MethodCall
+
+// SelfArg is the name of the argument that carries the resource a method is called on.
+const SelfArg = info.SelfArg
+
+// MethodToken returns the function token of the method name on the resource with the type token tok.
+func MethodToken(tok tokens.Type, name string) tokens.ModuleMember {
+	return info.MethodToken(tok, name)
+}
+
 type PropertyTransform = info.PropertyTransform
 
 type PreCheckCallback = info.PreCheckCallback
 
+// ComponentInfo is a component resource implemented in Go and served by the bridged provider.
+type ComponentInfo = info.Component
+
+// ComponentConstructor serves the Construct requests of a component resource.
+type ComponentConstructor = info.ComponentConstructor
+
 // DataSourceInfo can be used to override a data source's standard name mangling and argument/return information.
 type DataSourceInfo = info.DataSource
 
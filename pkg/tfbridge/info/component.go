@@ -0,0 +1,50 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package info
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	pschema "github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+// Component is a component resource implemented in Go and served by the bridged provider alongside the resources
+// and data sources bridged from Terraform. Components typically compose several bridged resources into a single
+// higher-level abstraction, similar to a Terraform module.
+//
+// Components are registered in [Provider.Components] under their Pulumi token.
+type Component struct {
+	// Schema describes the inputs and outputs of the component. tfgen emits it into the package schema and marks
+	// it as a component resource, so IsComponent does not need to be set.
+	Schema pschema.ResourceSpec
+
+	// Construct registers the component and its children with the engine and returns its outputs. It is invoked
+	// for every Construct request against the component token. Components written with the Pulumi Go SDK can use
+	// tfbridge.ConstructFunc.
+	//
+	// Construct is required.
+	Construct ComponentConstructor
+}
+
+// ComponentConstructor serves the Construct requests of a component resource. engineConn is the connection to the
+// engine that the component registers its children with.
+type ComponentConstructor interface {
+	Construct(
+		ctx context.Context, req *pulumirpc.ConstructRequest, engineConn *grpc.ClientConn,
+	) (*pulumirpc.ConstructResponse, error)
+}
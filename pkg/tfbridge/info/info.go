@@ -76,6 +76,7 @@ type Provider struct {
 	ExtraTypes     map[string]pschema.ComplexTypeSpec // a map of Pulumi token to schema type for extra types.
 	ExtraResources map[string]pschema.ResourceSpec    // a map of Pulumi token to schema type for extra resources.
 	ExtraFunctions map[string]pschema.FunctionSpec    // a map of Pulumi token to schema type for extra functions.
+	Components     map[string]*Component              // a map of Pulumi token to Go-implemented component resources.
 
 	// ExtraResourceHclExamples is a slice of additional HCL examples attached to resources which are converted to the
 	// relevant target language(s)
@@ -96,7 +97,7 @@ type Provider struct {
 	Python                  *Python            // optional overlay information for augmented Python code-generation.
 	Golang                  *Golang            // optional overlay information for augmented Golang code-generation.
 	CSharp                  *CSharp            // optional overlay information for augmented C# code-generation.
-	Java                    *Java              // optional overlay information for augmented C# code-generation.
+	Java                    *Java              // optional overlay information for augmented Java code-generation.
 	TFProviderVersion       string             // the version of the TF provider on which this was based
 	TFProviderLicense       *TFProviderLicense // license that the TF provider is distributed under. Default `MPL 2.0`.
 	TFProviderModuleVersion string             // the Go module version of the provider. Default is unversioned e.g. v1
@@ -346,8 +347,7 @@ type Resource struct {
 	DeprecationMessage  string  // message to use in deprecation warning
 	CSharpName          string  // .NET-specific name
 
-	// Optional hook to run before upgrading the state. TODO[pulumi/pulumi-terraform-bridge#864] this is currently
-	// only supported for Plugin-Framework based providers.
+	// Optional hook to run before upgrading the state.
 	PreStateUpgradeHook PreStateUpgradeHook
 
 	// An experimental way to augment the Check function in the Pulumi life cycle.
@@ -374,11 +374,12 @@ type Resource struct {
 	// ("base64" in the case of RandomBytes). ComputeID customization option supports such
 	// resources. It is called in Create(preview=false) and Read provider methods.
 	//
-	// This option is currently only supported for Plugin Framework based resources.
-	//
 	// To delegate the resource ID to another string field in state, use the helper function
 	// [DelegateIDField].
 	ComputeID ComputeID
+
+	// Methods exposed on the resource, keyed by method name. See [Method].
+	Methods map[string]*Method
 }
 
 type ComputeID = func(ctx context.Context, state resource.PropertyMap) (resource.ID, error)
@@ -414,6 +415,13 @@ type DataSource struct {
 	Fields             map[string]*Schema
 	Docs               *Doc   // overrides for finding and mapping TF docs.
 	DeprecationMessage string // message to use in deprecation warning
+
+	// StreamAttribute opts the data source into StreamInvoke. It is the Terraform name of a top-level list or set
+	// attribute whose elements are sent back one message at a time instead of in a single Invoke response, which
+	// keeps responses small for data sources that list many items.
+	//
+	// Object elements are streamed as is. Any other element is streamed as a single "value" property.
+	StreamAttribute string
 }
 
 // GetTok returns a datasource type token
@@ -784,6 +792,8 @@ type Java struct {
 	Packages     map[string]string `json:"packages,omitempty"`
 	Dependencies map[string]string `json:"dependencies,omitempty"`
 	GradleTest   string            `json:"gradleTest"`
+
+	Overlay *Overlay // optional overlay information for augmented code-generation.
 }
 
 // PreConfigureCallback is a function to invoke prior to calling the TF provider Configure
@@ -0,0 +1,50 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package info
+
+import (
+	"context"
+
+	pschema "github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+)
+
+// SelfArg is the name of the argument that carries the resource a method is called on.
+const SelfArg = "__self__"
+
+// Method is a resource method implemented in Go, such as getKubeconfig() on a cluster resource.
+//
+// Methods are registered in [Resource.Methods] under their name. tfgen emits each method into the package schema as
+// a function with the token returned by [MethodToken] and the bridge serves it through Call.
+type Method struct {
+	// Schema describes the arguments and results of the method. tfgen adds the __self__ argument referencing the
+	// resource, so it does not need to be declared.
+	Schema pschema.FunctionSpec
+
+	// Call implements the method. It is required.
+	Call MethodCall
+}
+
+// MethodCall implements a resource method.
+//
+// self is the state of the resource the method is called on, read from the provider by ID and translated to Pulumi
+// properties in the same way as for Read. args holds the method arguments without __self__.
+type MethodCall = func(ctx context.Context, self, args resource.PropertyMap) (resource.PropertyMap, error)
+
+// MethodToken returns the function token of the method name on the resource with the type token tok.
+func MethodToken(tok tokens.Type, name string) tokens.ModuleMember {
+	return tokens.ModuleMember(string(tok) + "/" + name)
+}
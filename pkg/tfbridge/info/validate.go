@@ -40,6 +40,15 @@ func (p *Provider) Validate(context.Context) error {
 		c.checkResource(tk, tf.Schema(), schema.Fields)
 	}
 
+	ds := p.P.DataSourcesMap()
+	for tk, info := range p.DataSources {
+		tf, ok := ds.GetOk(tk)
+		if !ok || info == nil || info.StreamAttribute == "" {
+			continue
+		}
+		c.checkStreamAttribute(tk, tf.Schema(), info)
+	}
+
 	return c.errorOrNil()
 }
 
@@ -63,6 +72,12 @@ type checkError struct {
 
 func (e checkError) Unwrap() error { return e.err }
 
+// TFToken returns the Terraform token of the resource or data source with the invalid override.
+func (e checkError) TFToken() string { return e.tfToken }
+
+// SchemaPath returns the path of the invalid override within TFToken.
+func (e checkError) SchemaPath() walk.SchemaPath { return e.path }
+
 func (e checkError) Error() string {
 	return fmt.Sprintf("%s: %s: %s", e.tfToken, e.path, e.err.Error())
 }
@@ -83,6 +98,7 @@ func (c *infoCheck) error(path walk.SchemaPath, err error) {
 }
 
 var (
+	errStreamAttributeNotList         = fmt.Errorf("StreamAttribute must name a list or set attribute")
 	errNoCorrespondingField           = fmt.Errorf("overriding non-existent field")
 	errNoElemToOverride               = fmt.Errorf("overriding non-existent elem")
 	errCannotSpecifyFieldsOnListOrSet = fmt.Errorf("cannot specify .Fields on a List[T] or Set[T] type")
@@ -101,6 +117,33 @@ func (c *infoCheck) checkResource(tfToken string, schema shim.SchemaMap, info ma
 	c.checkFields(walk.NewSchemaPath(), schema, info)
 }
 
+func (c *infoCheck) checkStreamAttribute(tfToken string, schema shim.SchemaMap, info *DataSource) {
+	c.finishError = func(e *checkError) {
+		e.tfToken = tfToken
+	}
+	defer func() {
+		c.finishError = nil
+	}()
+	path := walk.NewSchemaPath().GetAttr(info.StreamAttribute)
+	tfs, ok := schema.GetOk(info.StreamAttribute)
+	if !ok {
+		c.error(path, errNoCorrespondingField)
+		return
+	}
+	if tfs.Type() != shim.TypeList && tfs.Type() != shim.TypeSet {
+		c.error(path, errStreamAttributeNotList)
+		return
+	}
+	// Lists flattened by MaxItemsOne are not lists on the Pulumi side.
+	if ps := info.Fields[info.StreamAttribute]; ps != nil && ps.MaxItemsOne != nil {
+		if *ps.MaxItemsOne {
+			c.error(path, errStreamAttributeNotList)
+		}
+	} else if tfs.MaxItems() == 1 {
+		c.error(path, errStreamAttributeNotList)
+	}
+}
+
 func (c *infoCheck) checkProperty(path walk.SchemaPath, tfs shim.Schema, ps *Schema) {
 	// If there is no override, then there were no mistakes.
 	if ps == nil {
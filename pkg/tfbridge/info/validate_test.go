@@ -225,3 +225,79 @@ func withResource(info *Resource, r shim.Resource) *Provider {
 
 	return p
 }
+
+func TestValidateStreamAttribute(t *testing.T) {
+	ds := (&schema.Resource{
+		Schema: schema.SchemaMap{
+			"name": (&schema.Schema{Type: shim.TypeString}).Shim(),
+			"images": (&schema.Schema{
+				Type: shim.TypeList,
+				Elem: (&schema.Resource{
+					Schema: schema.SchemaMap{
+						"id": (&schema.Schema{Type: shim.TypeString}).Shim(),
+					},
+				}).Shim(),
+			}).Shim(),
+			"ids": (&schema.Schema{
+				Type: shim.TypeSet,
+				Elem: (&schema.Schema{Type: shim.TypeString}).Shim(),
+			}).Shim(),
+			"single": (&schema.Schema{
+				Type:     shim.TypeList,
+				MaxItems: 1,
+				Elem:     (&schema.Schema{Type: shim.TypeString}).Shim(),
+			}).Shim(),
+		},
+	}).Shim()
+
+	tests := []struct {
+		name        string
+		info        *DataSource
+		expectedErr error
+	}{
+		{name: "list", info: &DataSource{StreamAttribute: "images"}},
+		{name: "set", info: &DataSource{StreamAttribute: "ids"}},
+		{
+			name: "max items one disabled",
+			info: &DataSource{
+				StreamAttribute: "single",
+				Fields:          map[string]*Schema{"single": {MaxItemsOne: ref(false)}},
+			},
+		},
+		{
+			name:        "missing",
+			info:        &DataSource{StreamAttribute: "missing"},
+			expectedErr: errNoCorrespondingField,
+		},
+		{
+			name:        "scalar",
+			info:        &DataSource{StreamAttribute: "name"},
+			expectedErr: errStreamAttributeNotList,
+		},
+		{
+			name:        "max items one",
+			info:        &DataSource{StreamAttribute: "single"},
+			expectedErr: errStreamAttributeNotList,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			p := &Provider{
+				Name: "test",
+				P: (&schema.Provider{
+					DataSourcesMap: schema.ResourceMap{"test_images": ds},
+				}).Shim(),
+				DataSources: map[string]*DataSource{"test_images": tt.info},
+			}
+			err := p.Validate(context.Background())
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lint checks a [info.Provider] for mistakes that do not prevent the provider from building, such as
+// overrides that have no effect or that conflict with the Terraform schema.
+//
+// Each check is a named [Rule] that can be disabled for the whole provider or for a single Terraform resource or
+// data source. Providers run the linter through the `lint` subcommand of tfgen:
+//
+//	pulumi-tfgen-aws lint --disable secret-override:aws_db_instance --json lint.json
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge/info"
+)
+
+// Severity classifies the issues reported by a rule.
+type Severity string
+
+const (
+	// Errors are mistakes that change the behavior of the provider.
+	Error Severity = "error"
+	// Warnings are likely mistakes, or overrides that have no effect.
+	Warning Severity = "warning"
+)
+
+// Rule is a named check of a provider.
+type Rule struct {
+	// Name identifies the rule in reports and in Options.Disable, such as "duplicate-token".
+	Name string
+
+	// A one line description of the mistakes reported by the rule.
+	Description string
+
+	Severity Severity
+
+	// Check reports the issues of prov. The Rule and Severity of reported issues are filled in by Run.
+	Check func(ctx context.Context, prov *info.Provider, report func(Issue))
+}
+
+// Issue is a mistake found by a rule.
+type Issue struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+
+	// The Terraform token of the resource or data source the issue is about, if any.
+	Token string `json:"token,omitempty"`
+
+	// The path of the field the issue is about within Token, if any.
+	Path string `json:"path,omitempty"`
+
+	Message string `json:"message"`
+}
+
+func (i Issue) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s[%s] ", i.Severity, i.Rule)
+	if i.Token != "" {
+		fmt.Fprintf(&b, "%s: ", i.Token)
+	}
+	if i.Path != "" {
+		fmt.Fprintf(&b, "%s: ", i.Path)
+	}
+	b.WriteString(i.Message)
+	return b.String()
+}
+
+// Report is the machine-readable result of Run.
+type Report struct {
+	Issues []Issue `json:"issues"`
+}
+
+// HasErrors returns true if any of the issues has Error severity.
+func (r *Report) HasErrors() bool {
+	for _, i := range r.Issues {
+		if i.Severity == Error {
+			return true
+		}
+	}
+	return false
+}
+
+// JSON returns the report as indented JSON.
+func (r *Report) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "    ")
+}
+
+// Options configure Run.
+type Options struct {
+	// Rules to disable. An entry is either the name of a rule, which disables it for the whole provider, or
+	// "<rule>:<token>", which disables it for a single Terraform resource or data source.
+	Disable []string
+
+	// Rules to run in addition to DefaultRules.
+	Rules []Rule
+}
+
+// DefaultRules are the rules run by Run.
+func DefaultRules() []Rule {
+	return []Rule{
+		schemaOverrides,
+		duplicateToken,
+		aliasToken,
+		secretOverride,
+		defaultType,
+	}
+}
+
+// Run checks prov with DefaultRules and opts.Rules. Issues are sorted by token, path and rule.
+func Run(ctx context.Context, prov info.Provider, opts Options) (*Report, error) {
+	rules := append(DefaultRules(), opts.Rules...)
+
+	known := map[string]bool{}
+	for _, r := range rules {
+		if known[r.Name] {
+			return nil, fmt.Errorf("duplicate lint rule %q", r.Name)
+		}
+		known[r.Name] = true
+	}
+
+	disabled := map[string]bool{}
+	for _, d := range opts.Disable {
+		name, _, _ := strings.Cut(d, ":")
+		if !known[name] {
+			return nil, fmt.Errorf("cannot disable unknown lint rule %q", name)
+		}
+		disabled[d] = true
+	}
+
+	report := &Report{Issues: []Issue{}}
+	for _, r := range rules {
+		if disabled[r.Name] {
+			continue
+		}
+		r := r
+		r.Check(ctx, &prov, func(i Issue) {
+			if i.Token != "" && disabled[r.Name+":"+i.Token] {
+				return
+			}
+			i.Rule, i.Severity = r.Name, r.Severity
+			report.Issues = append(report.Issues, i)
+		})
+	}
+
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		a, b := report.Issues[i], report.Issues[j]
+		if a.Token != b.Token {
+			return a.Token < b.Token
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Rule < b.Rule
+	})
+	return report, nil
+}
@@ -0,0 +1,261 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge/info"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+func ref[T any](t T) *T { return &t }
+
+func testProvider() info.Provider {
+	res := (&schema.Resource{
+		Schema: schema.SchemaMap{
+			"name":     (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+			"password": (&schema.Schema{Type: shim.TypeString, Optional: true, Sensitive: true}).Shim(),
+			"port":     (&schema.Schema{Type: shim.TypeInt, Optional: true}).Shim(),
+			"tags": (&schema.Schema{
+				Type: shim.TypeList,
+				Elem: (&schema.Resource{
+					Schema: schema.SchemaMap{
+						"key": (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+					},
+				}).Shim(),
+				Optional: true,
+			}).Shim(),
+		},
+	}).Shim()
+
+	return info.Provider{
+		Name: "test",
+		P: (&schema.Provider{
+			Schema: schema.SchemaMap{
+				"region": (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+			},
+			ResourcesMap: schema.ResourceMap{
+				"test_a": res,
+				"test_b": res,
+				"test_c": res,
+			},
+		}).Shim(),
+		Resources: map[string]*info.Resource{
+			"test_a": {Tok: "test:index:A"},
+			"test_b": {Tok: "test:index:B"},
+			"test_c": {Tok: "test:index:C"},
+		},
+	}
+}
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		edit     func(p *info.Provider)
+		expected []Issue
+	}{
+		{
+			name:     "clean",
+			edit:     func(p *info.Provider) {},
+			expected: []Issue{},
+		},
+		{
+			name: "schema-overrides",
+			edit: func(p *info.Provider) {
+				p.Resources["test_a"].Fields = map[string]*info.Schema{"missing": {Name: "m"}}
+			},
+			expected: []Issue{{
+				Rule:     "schema-overrides",
+				Severity: Error,
+				Token:    "test_a",
+				Path:     "missing",
+				Message:  "overriding non-existent field",
+			}},
+		},
+		{
+			name: "duplicate-token",
+			edit: func(p *info.Provider) {
+				p.Resources["test_b"].Tok = "test:index:A"
+			},
+			expected: []Issue{
+				{
+					Rule:     "duplicate-token",
+					Severity: Error,
+					Token:    "test_a",
+					Message:  "test:index:A is also mapped from test_b",
+				},
+				{
+					Rule:     "duplicate-token",
+					Severity: Error,
+					Token:    "test_b",
+					Message:  "test:index:A is also mapped from test_a",
+				},
+			},
+		},
+		{
+			name: "alias-token",
+			edit: func(p *info.Provider) {
+				p.Resources["test_a"].Aliases = []info.Alias{
+					{Type: ref("test:index:B")},
+					{Type: ref("test:index:Old")},
+				}
+			},
+			expected: []Issue{{
+				Rule:     "alias-token",
+				Severity: Error,
+				Token:    "test_a",
+				Message:  "alias test:index:B is still the token of test_b",
+			}},
+		},
+		{
+			name: "secret-override",
+			edit: func(p *info.Provider) {
+				p.Resources["test_a"].Fields = map[string]*info.Schema{
+					"password": {Secret: ref(false)},
+					"tags": {Elem: &info.Schema{Fields: map[string]*info.Schema{
+						"key": {Secret: ref(false)},
+					}}},
+				}
+			},
+			expected: []Issue{
+				{
+					Rule:     "secret-override",
+					Severity: Warning,
+					Token:    "test_a",
+					Path:     "password",
+					Message:  "Secret exposes a field that is sensitive in the Terraform schema",
+				},
+				{
+					Rule:     "secret-override",
+					Severity: Warning,
+					Token:    "test_a",
+					Path:     "tags.$.key",
+					Message:  "Secret has no effect: the field is not sensitive in the Terraform schema",
+				},
+			},
+		},
+		{
+			name: "default-type",
+			edit: func(p *info.Provider) {
+				p.Resources["test_a"].Fields = map[string]*info.Schema{
+					"name": {Default: &info.Default{Config: "region"}},
+					"port": {Default: &info.Default{Config: "region", Value: "80"}},
+					"tags": {Default: &info.Default{EnvVars: []string{"TAGS"}}},
+				}
+				p.Resources["test_b"].Fields = map[string]*info.Schema{
+					"name": {Default: &info.Default{Config: "zone"}},
+				}
+			},
+			expected: []Issue{
+				{
+					Rule:     "default-type",
+					Severity: Error,
+					Token:    "test_a",
+					Path:     "port",
+					Message:  `Default.Value "80" does not have type Int`,
+				},
+				{
+					Rule:     "default-type",
+					Severity: Error,
+					Token:    "test_a",
+					Path:     "port",
+					Message:  `Default.Config "region" has type String but the field has type Int`,
+				},
+				{
+					Rule:     "default-type",
+					Severity: Error,
+					Token:    "test_a",
+					Path:     "tags",
+					Message:  "Default.EnvVars cannot set a field of type List",
+				},
+				{
+					Rule:     "default-type",
+					Severity: Error,
+					Token:    "test_b",
+					Path:     "name",
+					Message:  `Default.Config "zone" is not a configuration variable of the provider`,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			p := testProvider()
+			tt.edit(&p)
+			report, err := Run(context.Background(), p, Options{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, report.Issues)
+		})
+	}
+}
+
+func TestDisable(t *testing.T) {
+	p := testProvider()
+	p.Resources["test_b"].Tok = "test:index:A"
+	p.Resources["test_c"].Fields = map[string]*info.Schema{"name": {Secret: ref(true)}}
+
+	report, err := Run(context.Background(), p, Options{Disable: []string{"duplicate-token:test_a"}})
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 2)
+	assert.Equal(t, "test_b", report.Issues[0].Token)
+	assert.Equal(t, "error[duplicate-token] test_b: test:index:A is also mapped from test_a",
+		report.Issues[0].String())
+	assert.Equal(t, "warning[secret-override] test_c: name: Secret marks a field that is not sensitive "+
+		"in the Terraform schema; check that the field holds secret values", report.Issues[1].String())
+	assert.True(t, report.HasErrors())
+
+	report, err = Run(context.Background(), p, Options{Disable: []string{"duplicate-token"}})
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 1)
+	assert.False(t, report.HasErrors())
+
+	p.Resources["test_a"].Fields = map[string]*info.Schema{"missing": {Name: "m"}}
+	report, err = Run(context.Background(), p, Options{
+		Disable: []string{"duplicate-token", "schema-overrides:test_a"},
+	})
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 1)
+	assert.Equal(t, "secret-override", report.Issues[0].Rule)
+
+	_, err = Run(context.Background(), p, Options{Disable: []string{"no-such-rule"}})
+	assert.ErrorContains(t, err, `unknown lint rule "no-such-rule"`)
+}
+
+func TestCustomRule(t *testing.T) {
+	rule := Rule{
+		Name:     "has-name",
+		Severity: Warning,
+		Check: func(_ context.Context, prov *info.Provider, report func(Issue)) {
+			if prov.DisplayName == "" {
+				report(Issue{Message: "DisplayName is empty"})
+			}
+		},
+	}
+
+	report, err := Run(context.Background(), testProvider(), Options{Rules: []Rule{rule}})
+	require.NoError(t, err)
+	assert.Equal(t, []Issue{{Rule: "has-name", Severity: Warning, Message: "DisplayName is empty"}}, report.Issues)
+
+	_, err = Run(context.Background(), testProvider(), Options{Rules: []Rule{{Name: "duplicate-token"}}})
+	assert.ErrorContains(t, err, `duplicate lint rule "duplicate-token"`)
+}
@@ -0,0 +1,296 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge/info"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/util"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/walk"
+)
+
+var schemaOverrides = Rule{
+	Name:        "schema-overrides",
+	Description: "Fields, Elem and MaxItemsOne overrides must match the Terraform schema",
+	Severity:    Error,
+	Check: func(ctx context.Context, prov *info.Provider, report func(Issue)) {
+		err := prov.Validate(ctx)
+		if err == nil {
+			return
+		}
+		errs := []error{err}
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			errs = joined.Unwrap()
+		}
+		for _, err := range errs {
+			// Validate locates its errors, so that they can be disabled per token like other issues.
+			var located interface {
+				TFToken() string
+				SchemaPath() walk.SchemaPath
+			}
+			if errors.As(err, &located) {
+				report(Issue{
+					Token:   located.TFToken(),
+					Path:    located.SchemaPath().MustEncodeSchemaPath(),
+					Message: errors.Unwrap(err).Error(),
+				})
+				continue
+			}
+			report(Issue{Message: err.Error()})
+		}
+	},
+}
+
+var duplicateToken = Rule{
+	Name:        "duplicate-token",
+	Description: "Each Pulumi token must be mapped to a single Terraform resource or data source",
+	Severity:    Error,
+	Check: func(_ context.Context, prov *info.Provider, report func(Issue)) {
+		resources := map[string][]string{}
+		for tfToken, r := range prov.Resources {
+			if r != nil && r.Tok != "" {
+				resources[string(r.Tok)] = append(resources[string(r.Tok)], tfToken)
+			}
+		}
+		dataSources := map[string][]string{}
+		for tfToken, d := range prov.DataSources {
+			if d != nil && d.Tok != "" {
+				dataSources[string(d.Tok)] = append(dataSources[string(d.Tok)], tfToken)
+			}
+		}
+
+		for _, byToken := range []map[string][]string{resources, dataSources} {
+			for tok, tfTokens := range byToken {
+				if len(tfTokens) < 2 {
+					continue
+				}
+				sort.Strings(tfTokens)
+				for _, tfToken := range tfTokens {
+					report(Issue{
+						Token:   tfToken,
+						Message: fmt.Sprintf("%s is also mapped from %s", tok, strings.Join(others(tfTokens, tfToken), ", ")),
+					})
+				}
+			}
+		}
+	},
+}
+
+var aliasToken = Rule{
+	Name:        "alias-token",
+	Description: "Aliases must not point to the token of a resource of the provider",
+	Severity:    Error,
+	Check: func(_ context.Context, prov *info.Provider, report func(Issue)) {
+		byToken := map[string]string{}
+		for tfToken, r := range prov.Resources {
+			if r != nil && r.Tok != "" {
+				byToken[string(r.Tok)] = tfToken
+			}
+		}
+		for tfToken, r := range prov.Resources {
+			if r == nil {
+				continue
+			}
+			for _, alias := range r.Aliases {
+				if alias.Type == nil {
+					continue
+				}
+				switch owner, ok := byToken[*alias.Type]; {
+				case !ok:
+				case owner == tfToken:
+					report(Issue{Token: tfToken, Message: fmt.Sprintf("alias %s is the token of the resource", *alias.Type)})
+				default:
+					report(Issue{
+						Token:   tfToken,
+						Message: fmt.Sprintf("alias %s is still the token of %s", *alias.Type, owner),
+					})
+				}
+			}
+		}
+	},
+}
+
+var secretOverride = Rule{
+	Name:        "secret-override",
+	Description: "Secret overrides should only mark fields that are not sensitive in Terraform as secret",
+	Severity:    Warning,
+	Check: func(_ context.Context, prov *info.Provider, report func(Issue)) {
+		walkProvider(prov, func(tfToken string, path walk.SchemaPath, tfs shim.Schema, ps *info.Schema) {
+			if ps.Secret == nil {
+				return
+			}
+			var msg string
+			switch {
+			case *ps.Secret && tfs.Sensitive():
+				msg = "Secret has no effect: the field is sensitive in the Terraform schema"
+			case *ps.Secret:
+				msg = "Secret marks a field that is not sensitive in the Terraform schema; " +
+					"check that the field holds secret values"
+			case tfs.Sensitive():
+				msg = "Secret exposes a field that is sensitive in the Terraform schema"
+			default:
+				msg = "Secret has no effect: the field is not sensitive in the Terraform schema"
+			}
+			report(Issue{Token: tfToken, Path: path.MustEncodeSchemaPath(), Message: msg})
+		})
+	},
+}
+
+var defaultType = Rule{
+	Name:        "default-type",
+	Description: "Defaults must produce values of the type of the field",
+	Severity:    Error,
+	Check: func(_ context.Context, prov *info.Provider, report func(Issue)) {
+		walkProvider(prov, func(tfToken string, path walk.SchemaPath, tfs shim.Schema, ps *info.Schema) {
+			d := ps.Default
+			if d == nil {
+				return
+			}
+			issue := func(format string, args ...interface{}) {
+				report(Issue{Token: tfToken, Path: path.MustEncodeSchemaPath(), Message: fmt.Sprintf(format, args...)})
+			}
+
+			scalar := isScalar(tfs.Type())
+			if len(d.EnvVars) > 0 && !scalar {
+				issue("Default.EnvVars cannot set a field of type %v", tfs.Type())
+			}
+			if d.Value != nil && !valueHasType(d.Value, tfs.Type()) {
+				issue("Default.Value %#v does not have type %v", d.Value, tfs.Type())
+			}
+			if d.Config != "" {
+				switch configType, ok := lookupConfig(prov, d.Config); {
+				case !ok:
+					issue("Default.Config %q is not a configuration variable of the provider", d.Config)
+				case configType != tfs.Type():
+					issue("Default.Config %q has type %v but the field has type %v", d.Config, configType, tfs.Type())
+				}
+			}
+		})
+	},
+}
+
+// walkProvider calls visit for every field of every resource and data source that has a SchemaInfo override.
+func walkProvider(prov *info.Provider, visit func(string, walk.SchemaPath, shim.Schema, *info.Schema)) {
+	resources, dataSources := prov.P.ResourcesMap(), prov.P.DataSourcesMap()
+	for _, tfToken := range sortedKeys(prov.Resources) {
+		if tf, ok := resources.GetOk(tfToken); ok && prov.Resources[tfToken] != nil {
+			walkFields(walk.NewSchemaPath(), tf.Schema(), prov.Resources[tfToken].Fields,
+				func(path walk.SchemaPath, tfs shim.Schema, ps *info.Schema) { visit(tfToken, path, tfs, ps) })
+		}
+	}
+	for _, tfToken := range sortedKeys(prov.DataSources) {
+		if tf, ok := dataSources.GetOk(tfToken); ok && prov.DataSources[tfToken] != nil {
+			walkFields(walk.NewSchemaPath(), tf.Schema(), prov.DataSources[tfToken].Fields,
+				func(path walk.SchemaPath, tfs shim.Schema, ps *info.Schema) { visit(tfToken, path, tfs, ps) })
+		}
+	}
+}
+
+// walkFields visits the overrides that match the Terraform schema. Mismatched overrides are reported by
+// schemaOverrides.
+func walkFields(
+	path walk.SchemaPath, sch shim.SchemaMap, fields map[string]*info.Schema,
+	visit func(walk.SchemaPath, shim.Schema, *info.Schema),
+) {
+	for _, key := range sortedKeys(fields) {
+		tfs, ok := sch.GetOk(key)
+		if ok && fields[key] != nil {
+			walkField(path.GetAttr(key), tfs, fields[key], visit)
+		}
+	}
+}
+
+func walkField(
+	path walk.SchemaPath, tfs shim.Schema, ps *info.Schema, visit func(walk.SchemaPath, shim.Schema, *info.Schema),
+) {
+	visit(path, tfs, ps)
+
+	if obj, ok := util.CastToTypeObject(tfs); ok {
+		walkFields(path, obj, ps.Fields, visit)
+		return
+	}
+	if ps.Elem == nil {
+		return
+	}
+	switch elem := tfs.Elem().(type) {
+	case shim.Schema:
+		walkField(path.Element(), elem, ps.Elem, visit)
+	case shim.Resource:
+		walkFields(path.Element(), elem.Schema(), ps.Elem.Fields, visit)
+	}
+}
+
+// lookupConfig returns the type of the provider configuration variable with the given Pulumi name.
+func lookupConfig(prov *info.Provider, name string) (shim.ValueType, bool) {
+	if extra, ok := prov.ExtraConfig[name]; ok && extra != nil && extra.Schema != nil {
+		return extra.Schema.Type(), true
+	}
+	sch := prov.P.Schema()
+	tfs, ok := sch.GetOk(tfbridge.PulumiToTerraformName(name, sch, prov.Config))
+	if !ok {
+		return shim.TypeInvalid, false
+	}
+	return tfs.Type(), true
+}
+
+func isScalar(t shim.ValueType) bool {
+	switch t {
+	case shim.TypeBool, shim.TypeInt, shim.TypeFloat, shim.TypeString:
+		return true
+	default:
+		return false
+	}
+}
+
+// valueHasType returns true if v, a Default.Value, can be assigned to a field of type t.
+func valueHasType(v interface{}, t shim.ValueType) bool {
+	switch v.(type) {
+	case bool:
+		return t == shim.TypeBool
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return t == shim.TypeInt || t == shim.TypeFloat
+	case string:
+		return t == shim.TypeString
+	default:
+		// Structs, slices and maps are not supported as a Default.Value.
+		return false
+	}
+}
+
+func others(all []string, except string) []string {
+	var result []string
+	for _, s := range all {
+		if s != except {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -27,8 +27,24 @@ import (
 )
 
 // Main launches the tfbridge plugin for a given package pkg and provider prov.
+//
+// prov.P may be a provider launched from a prebuilt binary with tfplugin5.StartProvider, in which case the provider
+// process is stopped before Main exits.
 func Main(pkg string, version string, prov ProviderInfo, pulumiSchema []byte) {
 	ctx := context.Background()
+	stopProvider := func() {
+		if closer, ok := prov.P.(io.Closer); ok {
+			contract.IgnoreError(closer.Close())
+		}
+	}
+	exit := func(code int) {
+		stopProvider()
+		os.Exit(code)
+	}
+	exitError := func(msg string) {
+		stopProvider()
+		cmdutil.ExitError(msg)
+	}
 
 	// Look for a request to dump the provider info to stdout.
 	flags := flag.NewFlagSet("tf-provider-flags", flag.ContinueOnError)
@@ -42,6 +58,14 @@ func Main(pkg string, version string, prov ProviderInfo, pulumiSchema []byte) {
 
 	dumpInfo := flags.Bool("get-provider-info", false, "dump provider info as JSON to stdout")
 	providerVersion := flags.Bool("version", false, "get built provider version")
+	migrateState := flags.String("migrate-tfstate", "",
+		"migrate the resources of a Terraform state file and write them to stdout")
+	migrateFormat := flags.String("migrate-format", "import",
+		"format of the migrated resources: import for `pulumi import --file`, checkpoint for `pulumi stack import`")
+	migrateStack := flags.String("migrate-stack", "", "stack the migrated checkpoint is written for")
+	migrateProject := flags.String("migrate-project", "", "project the migrated checkpoint is written for")
+	migrateProviderID := flags.String("migrate-provider-id", "",
+		"ID of the default provider the migrated checkpoint attaches resources to; random if empty")
 
 	err := flags.Parse(os.Args[1:])
 	contract.IgnoreError(err)
@@ -51,26 +75,40 @@ func Main(pkg string, version string, prov ProviderInfo, pulumiSchema []byte) {
 		flags.SetOutput(defaultOutput)
 		err := flags.Parse(os.Args[1:])
 		if err != nil {
-			cmdutil.ExitError(err.Error())
+			exitError(err.Error())
 		}
 	}
 
 	if *dumpInfo {
 		if err := json.NewEncoder(os.Stdout).Encode(MarshalProviderInfo(&prov)); err != nil {
-			cmdutil.ExitError(err.Error())
+			exitError(err.Error())
 		}
-		os.Exit(0)
+		exit(0)
 	}
 
 	if *providerVersion {
 		fmt.Println(version)
-		os.Exit(0)
+		exit(0)
+	}
+
+	if *migrateState != "" {
+		err := MigrateTerraformStateFile(ctx, prov, nil, *migrateState, *migrateFormat, CheckpointOptions{
+			Stack:      *migrateStack,
+			Project:    *migrateProject,
+			ProviderID: *migrateProviderID,
+		}, os.Stdout, os.Stderr)
+		if err != nil {
+			exitError(err.Error())
+		}
+		exit(0)
 	}
 
 	// Initialize Terraform logging.
 	prov.P.InitLogging(ctx)
 
 	if err := Serve(pkg, version, prov, pulumiSchema); err != nil {
-		cmdutil.ExitError(err.Error())
+		exitError(err.Error())
 	}
+
+	stopProvider()
 }
@@ -0,0 +1,93 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+)
+
+// ReadResourceFunc reads the state of the resource with the given URN and ID. It returns nil if the resource does
+// not exist.
+type ReadResourceFunc = func(ctx context.Context, urn resource.URN, id resource.ID) (resource.PropertyMap, error)
+
+// CallResourceMethod serves a Call request by dispatching it to the matching method in ResourceInfo.Methods.
+//
+// The __self__ argument is resolved to the state of the resource through read. While the resource ID is unknown,
+// as is the case during previews of new resources, all declared outputs of the method are returned as unknown.
+//
+// Internal. The signature of this function can change between major releases. Exposed to share the implementation
+// between SDKv2 and Plugin Framework based providers.
+func CallResourceMethod(
+	ctx context.Context,
+	resources map[string]*ResourceInfo,
+	tok tokens.ModuleMember,
+	args resource.PropertyMap,
+	read ReadResourceFunc,
+) (resource.PropertyMap, error) {
+	method, ok := findResourceMethod(resources, tok)
+	if !ok {
+		return nil, errors.Errorf("unrecognized resource method (Call): %s", tok)
+	}
+
+	self, ok := args[SelfArg]
+	if ok && self.IsSecret() {
+		self = self.SecretValue().Element
+	}
+	if !ok || !self.IsResourceReference() {
+		return nil, fmt.Errorf("calling %s: expected %s to be a resource reference", tok, SelfArg)
+	}
+	ref := self.ResourceReferenceValue()
+
+	if !ref.ID.IsString() || ref.ID.StringValue() == "" {
+		outputs := resource.PropertyMap{}
+		if method.Schema.Outputs != nil {
+			for k := range method.Schema.Outputs.Properties {
+				outputs[resource.PropertyKey(k)] = resource.MakeComputed(resource.NewStringProperty(""))
+			}
+		}
+		return outputs, nil
+	}
+
+	state, err := read(ctx, ref.URN, resource.ID(ref.ID.StringValue()))
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", ref.URN)
+	}
+	if state == nil {
+		return nil, fmt.Errorf("calling %s: resource %s does not exist", tok, ref.URN)
+	}
+
+	methodArgs := args.Copy()
+	delete(methodArgs, SelfArg)
+	return method.Call(ctx, state, methodArgs)
+}
+
+func findResourceMethod(resources map[string]*ResourceInfo, tok tokens.ModuleMember) (*MethodInfo, bool) {
+	for _, res := range resources {
+		if res == nil {
+			continue
+		}
+		for name, method := range res.Methods {
+			if method != nil && method.Call != nil && MethodToken(res.Tok, name) == tok {
+				return method, true
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,163 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pschema "github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+
+	shimv2 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2"
+)
+
+func TestCallResourceMethod(t *testing.T) {
+	ctx := context.Background()
+	urn := resource.URN("urn:pulumi:test::test::test:index/cluster:Cluster::c")
+	resources := map[string]*ResourceInfo{
+		"test_cluster": {
+			Tok: "test:index/cluster:Cluster",
+			Methods: map[string]*MethodInfo{
+				"getKubeconfig": {
+					Schema: pschema.FunctionSpec{
+						Outputs: &pschema.ObjectTypeSpec{
+							Properties: map[string]pschema.PropertySpec{"kubeconfig": {}},
+						},
+					},
+					Call: func(_ context.Context, self, args resource.PropertyMap) (resource.PropertyMap, error) {
+						assert.NotContains(t, args, SelfArg)
+						return resource.PropertyMap{
+							"kubeconfig": resource.NewStringProperty(
+								self["endpoint"].StringValue() + "#" + args["user"].StringValue()),
+						}, nil
+					},
+				},
+			},
+		},
+	}
+	read := func(_ context.Context, u resource.URN, id resource.ID) (resource.PropertyMap, error) {
+		assert.Equal(t, urn, u)
+		if id == "gone" {
+			return nil, nil
+		}
+		return resource.PropertyMap{"endpoint": resource.NewStringProperty("https://" + string(id))}, nil
+	}
+	args := func(id resource.PropertyValue) resource.PropertyMap {
+		return resource.PropertyMap{
+			SelfArg: resource.NewResourceReferenceProperty(resource.ResourceReference{URN: urn, ID: id}),
+			"user":  resource.NewStringProperty("admin"),
+		}
+	}
+
+	t.Run("known", func(t *testing.T) {
+		ret, err := CallResourceMethod(ctx, resources, "test:index/cluster:Cluster/getKubeconfig",
+			args(resource.NewStringProperty("c1")), read)
+		require.NoError(t, err)
+		assert.Equal(t, resource.NewStringProperty("https://c1#admin"), ret["kubeconfig"])
+	})
+
+	t.Run("unknown id", func(t *testing.T) {
+		ret, err := CallResourceMethod(ctx, resources, "test:index/cluster:Cluster/getKubeconfig",
+			args(resource.MakeComputed(resource.NewStringProperty(""))), read)
+		require.NoError(t, err)
+		assert.True(t, ret["kubeconfig"].IsComputed())
+	})
+
+	t.Run("gone", func(t *testing.T) {
+		_, err := CallResourceMethod(ctx, resources, "test:index/cluster:Cluster/getKubeconfig",
+			args(resource.NewStringProperty("gone")), read)
+		assert.ErrorContains(t, err, "does not exist")
+	})
+
+	t.Run("unknown method", func(t *testing.T) {
+		_, err := CallResourceMethod(ctx, resources, "test:index/cluster:Cluster/getOther",
+			args(resource.NewStringProperty("c1")), read)
+		assert.ErrorContains(t, err, "unrecognized resource method")
+	})
+
+	t.Run("missing self", func(t *testing.T) {
+		_, err := CallResourceMethod(ctx, resources, "test:index/cluster:Cluster/getKubeconfig",
+			resource.PropertyMap{}, read)
+		assert.ErrorContains(t, err, "expected __self__ to be a resource reference")
+	})
+}
+
+func TestCallRefreshesWithoutImporter(t *testing.T) {
+	tfProvider := &schema.Provider{
+		ResourcesMap: map[string]*schema.Resource{
+			"test_cluster": {
+				Schema: map[string]*schema.Schema{
+					"endpoint": {Type: schema.TypeString, Computed: true},
+				},
+				ReadContext: func(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+					return diag.FromErr(d.Set("endpoint", "https://"+d.Id()))
+				},
+				Importer: &schema.ResourceImporter{
+					StateContext: func(
+						context.Context, *schema.ResourceData, interface{},
+					) ([]*schema.ResourceData, error) {
+						t.Error("the importer should not run for method calls")
+						return nil, nil
+					},
+				},
+			},
+		},
+	}
+
+	shimProv := shimv2.NewProvider(tfProvider)
+	p := &Provider{
+		tf: shimProv,
+		info: ProviderInfo{
+			P: shimProv,
+			Resources: map[string]*ResourceInfo{
+				"test_cluster": {
+					Tok: "test:index/cluster:Cluster",
+					Methods: map[string]*MethodInfo{
+						"getEndpoint": {
+							Call: func(_ context.Context, self, _ resource.PropertyMap) (resource.PropertyMap, error) {
+								return resource.PropertyMap{"endpoint": self["endpoint"]}, nil
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	p.initResourceMaps()
+
+	urn := resource.URN("urn:pulumi:test::test::test:index/cluster:Cluster::c")
+	args, err := plugin.MarshalProperties(resource.PropertyMap{
+		SelfArg: resource.NewResourceReferenceProperty(resource.ResourceReference{
+			URN: urn,
+			ID:  resource.NewStringProperty("c1"),
+		}),
+	}, plugin.MarshalOptions{KeepResources: true})
+	require.NoError(t, err)
+
+	resp, err := p.Call(context.Background(), &pulumirpc.CallRequest{
+		Tok:  "test:index/cluster:Cluster/getEndpoint",
+		Args: args,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://c1", resp.GetReturn().GetFields()["endpoint"].GetStringValue())
+}
@@ -23,7 +23,6 @@ import (
 	"time"
 	"unicode"
 
-	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/golang/glog"
@@ -48,6 +47,7 @@ import (
 
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge/info"
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/cancel"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/logging"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/metadata"
 	"github.com/pulumi/pulumi-terraform-bridge/x/muxer"
@@ -69,6 +69,7 @@ type Provider struct {
 	supportsSecrets bool                               // true if the engine supports secret property values
 	pulumiSchema    []byte                             // the JSON-encoded Pulumi schema.
 	memStats        memStatCollector
+	cancellation    cancel.Scope // cancels in-flight operations when the engine calls Cancel.
 }
 
 // MuxProvider defines an interface which must be implemented by providers
@@ -82,19 +83,32 @@ type Resource struct {
 	TFName string        // the Terraform resource name.
 }
 
+// computeID returns the identity of the resource, using ResourceInfo.ComputeID when set and the ID recorded in the
+// Terraform state otherwise.
+func (res *Resource) computeID(
+	ctx context.Context, state shim.InstanceState, props resource.PropertyMap,
+) (string, error) {
+	if res.Schema == nil || res.Schema.ComputeID == nil {
+		return state.ID(), nil
+	}
+	id, err := res.Schema.ComputeID(ctx, props)
+	return string(id), err
+}
+
 // runTerraformImporter runs the Terraform Importer defined on the Resource for the given
 // resource ID, and returns a replacement input map if any resources are matched. A nil map
 // with no error should be interpreted by the caller as meaning the resource does not exist,
-// but there were no errors in determining this.
+// but there were no errors in determining this. Any other states returned by the importer are
+// returned as secondary states.
 func (res *Resource) runTerraformImporter(
 	ctx context.Context, id string, provider *Provider,
-) (shim.InstanceState, error) {
+) (shim.InstanceState, []shim.InstanceState, error) {
 	contract.Assertf(res.TF.Importer() != nil, "res.TF.Importer() != nil")
 
 	// Run the importer defined in the Terraform resource schema
 	states, err := res.TF.Importer()(res.TFName, id, provider.tf.Meta(ctx))
 	if err != nil {
-		return nil, errors.Wrapf(err, "importing %s", id)
+		return nil, nil, errors.Wrapf(err, "importing %s", id)
 	}
 
 	// No resources were returned. There are a few different ways this can happen - principally
@@ -109,7 +123,7 @@ func (res *Resource) runTerraformImporter(
 	// We consider the case in which multiple results are returned from the importer, but none
 	// match the ID expected to be an error, and this is handled later in this function.
 	if len(states) < 1 {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	// A Terraform importer can return multiple ResourceData instances for different resources. For
@@ -126,32 +140,40 @@ func (res *Resource) runTerraformImporter(
 	//
 	// The Type can be identified by looking at the ephemeral data attached to the InstanceState, since
 	// it is not stored in all cases - only for import.
-	var candidates []shim.InstanceState
-	for _, state := range states {
+	var candidates []int
+	for i, state := range states {
 		if state.Type() == res.TFName {
-			candidates = append(candidates, state)
+			candidates = append(candidates, i)
 		}
 	}
 
-	var primaryInstanceState shim.InstanceState
+	primary := -1
 	if len(candidates) == 1 {
 		// Take the only result.
-		primaryInstanceState = candidates[0]
+		primary = candidates[0]
 	} else {
 		// Search for a resource with a matching ID. If one exists, take it.
-		for _, result := range candidates {
-			if result.ID() == id {
-				primaryInstanceState = result
+		for _, i := range candidates {
+			if states[i].ID() == id {
+				primary = i
 				break
 			}
 		}
 	}
 
 	// No resources were matched - error out
-	if primaryInstanceState == nil {
-		return nil, errors.Errorf("importer for %s returned no matching resources", id)
+	if primary == -1 {
+		return nil, nil, errors.Errorf("importer for %s returned no matching resources", id)
 	}
-	return primaryInstanceState, nil
+
+	// The remaining states belong to other resources, such as the security group rules above.
+	var secondaryInstanceStates []shim.InstanceState
+	for i, state := range states {
+		if i != primary {
+			secondaryInstanceStates = append(secondaryInstanceStates, state)
+		}
+	}
+	return states[primary], secondaryInstanceStates, nil
 }
 
 // DataSource wraps both the Terraform data source (resource) type info plus the overlay resource info.
@@ -1017,6 +1039,8 @@ func (p *Provider) Diff(ctx context.Context, req *pulumirpc.DiffRequest) (*pulum
 // must be blank.)  If this call fails, the resource must not have been created (i.e., it is "transactional").
 func (p *Provider) Create(ctx context.Context, req *pulumirpc.CreateRequest) (*pulumirpc.CreateResponse, error) {
 	ctx = p.loggingContext(ctx, resource.URN(req.GetUrn()))
+	ctx, done := p.cancellation.Context(ctx)
+	defer done()
 	urn := resource.URN(req.GetUrn())
 	t := urn.Type()
 	res, has := p.resources[t]
@@ -1110,17 +1134,29 @@ func (p *Provider) Create(ctx context.Context, req *pulumirpc.CreateRequest) (*p
 		reasons = append(reasons, errors.Wrapf(err, "marshalling %s", urn).Error())
 	}
 
+	id := newstate.ID()
+	if !req.GetPreview() && props != nil {
+		computedID, err := res.computeID(ctx, newstate, props)
+		if err != nil {
+			reasons = append(reasons, errors.Wrapf(err, "computing the ID of %s", urn).Error())
+		} else {
+			id = computedID
+		}
+	}
+
 	if len(reasons) != 0 {
-		return nil, initializationError(newstate.ID(), mprops, reasons)
+		return nil, initializationError(id, mprops, reasons)
 	}
 
-	return &pulumirpc.CreateResponse{Id: newstate.ID(), Properties: mprops}, nil
+	return &pulumirpc.CreateResponse{Id: id, Properties: mprops}, nil
 }
 
 // Read the current live state associated with a resource.  Enough state must be include in the inputs to uniquely
 // identify the resource; this is typically just the resource ID, but may also include some properties.
 func (p *Provider) Read(ctx context.Context, req *pulumirpc.ReadRequest) (*pulumirpc.ReadResponse, error) {
 	ctx = p.loggingContext(ctx, resource.URN(req.GetUrn()))
+	ctx, done := p.cancellation.Context(ctx)
+	defer done()
 	urn := resource.URN(req.GetUrn())
 	t := urn.Type()
 	res, has := p.resources[t]
@@ -1155,7 +1191,8 @@ func (p *Provider) Read(ctx context.Context, req *pulumirpc.ReadRequest) (*pulum
 	if !isRefresh && res.TF.Importer() != nil {
 		glog.V(9).Infof("%s has TF Importer", res.TFName)
 
-		state, err = res.runTerraformImporter(ctx, id, p)
+		var secondaryStates []shim.InstanceState
+		state, secondaryStates, err = res.runTerraformImporter(ctx, id, p)
 		if err != nil {
 			// Pass through any error running the importer
 			return nil, err
@@ -1165,32 +1202,21 @@ func (p *Provider) Read(ctx context.Context, req *pulumirpc.ReadRequest) (*pulum
 			// resource ID set to indicate this.
 			return &pulumirpc.ReadResponse{}, nil
 		}
+		p.suggestSecondaryImports(ctx, urn, secondaryStates)
 	}
 
-	config, assets, err := MakeTerraformConfig(ctx, p, oldInputs, res.TF.Schema(), res.Schema.Fields)
-	if err != nil {
-		return nil, errors.Wrapf(err, "preparing %s's new property state", urn)
-	}
-
-	newstate, err := p.tf.Refresh(ctx, res.TFName, state, config)
+	newstate, props, err := p.refresh(ctx, urn, res, state, oldInputs)
 	if err != nil {
-		return nil, errors.Wrapf(err, "refreshing %s", urn)
+		return nil, err
 	}
 
 	// Store the ID and properties in the output.  The ID *should* be the same as the input ID, but in the case
 	// that the resource no longer exists, we will simply return the empty string and an empty property map.
 	if newstate != nil {
-		props, err := MakeTerraformResult(ctx, p.tf, newstate, res.TF.Schema(), res.Schema.Fields, assets, p.supportsSecrets)
+		// Compute the ID from the transformed outputs, as Create does.
+		newID, err := res.computeID(ctx, newstate, props)
 		if err != nil {
-			return nil, err
-		}
-
-		if res.Schema.TransformOutputs != nil {
-			var err error
-			props, err = res.Schema.TransformOutputs(ctx, props)
-			if err != nil {
-				return nil, err
-			}
+			return nil, errors.Wrapf(err, "computing the ID of %s", urn)
 		}
 
 		mprops, err := plugin.MarshalProperties(props, plugin.MarshalOptions{
@@ -1213,17 +1239,50 @@ func (p *Provider) Read(ctx context.Context, req *pulumirpc.ReadRequest) (*pulum
 			return nil, err
 		}
 
-		return &pulumirpc.ReadResponse{Id: newstate.ID(), Properties: mprops, Inputs: minputs}, nil
+		return &pulumirpc.ReadResponse{Id: newID, Properties: mprops, Inputs: minputs}, nil
 	}
 
 	// The resource is gone.
 	return &pulumirpc.ReadResponse{}, nil
 }
 
+// refresh reads the current state of a resource from the provider and returns it along with its transformed outputs.
+// The returned state is nil if the resource is gone.
+func (p *Provider) refresh(ctx context.Context, urn resource.URN, res Resource, state shim.InstanceState,
+	oldInputs resource.PropertyMap,
+) (shim.InstanceState, resource.PropertyMap, error) {
+	config, assets, err := MakeTerraformConfig(ctx, p, oldInputs, res.TF.Schema(), res.Schema.Fields)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "preparing %s's new property state", urn)
+	}
+
+	newstate, err := p.tf.Refresh(ctx, res.TFName, state, config)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "refreshing %s", urn)
+	}
+	if newstate == nil {
+		return nil, nil, nil
+	}
+
+	props, err := MakeTerraformResult(ctx, p.tf, newstate, res.TF.Schema(), res.Schema.Fields, assets, p.supportsSecrets)
+	if err != nil {
+		return nil, nil, err
+	}
+	if res.Schema.TransformOutputs != nil {
+		props, err = res.Schema.TransformOutputs(ctx, props)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return newstate, props, nil
+}
+
 // Update updates an existing resource with new values.  Only those values in the provided property bag are updated
 // to new values.  The resource ID is returned and may be different if the resource had to be recreated.
 func (p *Provider) Update(ctx context.Context, req *pulumirpc.UpdateRequest) (*pulumirpc.UpdateResponse, error) {
 	ctx = p.loggingContext(ctx, resource.URN(req.GetUrn()))
+	ctx, done := p.cancellation.Context(ctx)
+	defer done()
 	urn := resource.URN(req.GetUrn())
 	t := urn.Type()
 	res, has := p.resources[t]
@@ -1357,6 +1416,8 @@ func (p *Provider) Update(ctx context.Context, req *pulumirpc.UpdateRequest) (*p
 // Delete tears down an existing resource with the given ID.  If it fails, the resource is assumed to still exist.
 func (p *Provider) Delete(ctx context.Context, req *pulumirpc.DeleteRequest) (*pbempty.Empty, error) {
 	ctx = p.loggingContext(ctx, resource.URN(req.GetUrn()))
+	ctx, done := p.cancellation.Context(ctx)
+	defer done()
 	urn := resource.URN(req.GetUrn())
 	t := urn.Type()
 	res, has := p.resources[t]
@@ -1391,18 +1452,73 @@ func (p *Provider) Delete(ctx context.Context, req *pulumirpc.DeleteRequest) (*p
 }
 
 // Construct creates a new instance of the provided component resource and returns its state.
-func (p *Provider) Construct(context.Context, *pulumirpc.ConstructRequest) (*pulumirpc.ConstructResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "Construct is not yet implemented")
+//
+// Only components registered in [ProviderInfo.Components] can be constructed.
+func (p *Provider) Construct(ctx context.Context,
+	req *pulumirpc.ConstructRequest,
+) (*pulumirpc.ConstructResponse, error) {
+	return ConstructComponentResource(ctx, p.host, p.info.Components, req)
 }
 
 // Call dynamically executes a method in the provider associated with a component resource.
+//
+// Only methods registered in [ResourceInfo.Methods] can be called.
 func (p *Provider) Call(ctx context.Context, req *pulumirpc.CallRequest) (*pulumirpc.CallResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "Call is not yet implemented")
+	ctx = p.loggingContext(ctx, "")
+	ctx, done := p.cancellation.Context(ctx)
+	defer done()
+	tok := tokens.ModuleMember(req.GetTok())
+	label := fmt.Sprintf("%s.Call(%s)", p.label(), tok)
+	glog.V(9).Infof("%s executing", label)
+
+	args, err := plugin.UnmarshalProperties(req.GetArgs(), plugin.MarshalOptions{
+		Label:         label + ".args",
+		KeepUnknowns:  true,
+		KeepResources: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ret, err := CallResourceMethod(ctx, p.info.Resources, tok, args, p.readResourceState)
+	if err != nil {
+		return nil, err
+	}
+
+	mret, err := plugin.MarshalProperties(ret, plugin.MarshalOptions{
+		Label:         label + ".returns",
+		KeepUnknowns:  true,
+		KeepSecrets:   true,
+		KeepResources: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pulumirpc.CallResponse{Return: mret}, nil
+}
+
+// readResourceState refreshes the state of a resource from its ID. Unlike a Pulumi import, it does not run the
+// Terraform importer, which may report secondary resources.
+func (p *Provider) readResourceState(ctx context.Context, urn resource.URN, id resource.ID) (resource.PropertyMap,
+	error) {
+	res, has := p.resources[urn.Type()]
+	if !has {
+		return nil, errors.Errorf("unrecognized resource type (Call): %s", urn.Type())
+	}
+	label := fmt.Sprintf("%s.Call(%s).self", p.label(), urn)
+	state, err := UnmarshalTerraformState(ctx, res, string(id), nil, label)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unmarshaling %s's instance state", urn)
+	}
+	_, props, err := p.refresh(ctx, urn, res, state, resource.PropertyMap{})
+	return props, err
 }
 
 // Invoke dynamically executes a built-in function in the provider.
 func (p *Provider) Invoke(ctx context.Context, req *pulumirpc.InvokeRequest) (*pulumirpc.InvokeResponse, error) {
 	ctx = p.loggingContext(ctx, "")
+	ctx, done := p.cancellation.Context(ctx)
+	defer done()
 	tok := tokens.ModuleMember(req.GetTok())
 	ds, has := p.dataSources[tok]
 	if !has {
@@ -1418,12 +1534,38 @@ func (p *Provider) Invoke(ctx context.Context, req *pulumirpc.InvokeRequest) (*p
 	label := fmt.Sprintf("%s.Invoke(%s)", p.label(), tok)
 	glog.V(9).Infof("%s executing", label)
 
+	props, failures, err := p.invoke(ctx, tok, ds, req.GetArgs(), label)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret *pbstruct.Struct
+	if len(failures) == 0 {
+		ret, err = plugin.MarshalProperties(
+			props,
+			plugin.MarshalOptions{Label: fmt.Sprintf("%s.returns", label)})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &pulumirpc.InvokeResponse{
+		Return:   ret,
+		Failures: failures,
+	}, nil
+}
+
+// invoke reads the data source ds with the given arguments. If the arguments fail verification, the failures are
+// returned instead of a result.
+func (p *Provider) invoke(
+	ctx context.Context, tok tokens.ModuleMember, ds DataSource, rawArgs *pbstruct.Struct, label string,
+) (resource.PropertyMap, []*pulumirpc.CheckFailure, error) {
 	// Unmarshal the arguments.
-	args, err := plugin.UnmarshalProperties(req.GetArgs(), plugin.MarshalOptions{
+	args, err := plugin.UnmarshalProperties(rawArgs, plugin.MarshalOptions{
 		Label: fmt.Sprintf("%s.args", label), KeepUnknowns: true, SkipNulls: true,
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// First, create the inputs.
@@ -1436,7 +1578,7 @@ func (p *Provider) Invoke(ctx context.Context, req *pulumirpc.InvokeRequest) (*p
 		ds.TF.Schema(),
 		ds.Schema.Fields)
 	if err != nil {
-		return nil, errors.Wrapf(err, "couldn't prepare resource %v input state", tfname)
+		return nil, nil, errors.Wrapf(err, "couldn't prepare resource %v input state", tfname)
 	}
 
 	// Next, ensure the inputs are valid before actually performing the invoaction.
@@ -1444,7 +1586,7 @@ func (p *Provider) Invoke(ctx context.Context, req *pulumirpc.InvokeRequest) (*p
 	warns, errs := p.tf.ValidateDataSource(ctx, tfname, rescfg)
 	for _, warn := range warns {
 		if err = p.host.Log(ctx, diag.Warning, "", fmt.Sprintf("%v verification warning: %v", tok, warn)); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
@@ -1455,50 +1597,73 @@ func (p *Provider) Invoke(ctx context.Context, req *pulumirpc.InvokeRequest) (*p
 			Reason: err.Error(),
 		})
 	}
+	if len(failures) != 0 {
+		return nil, failures, nil
+	}
 
 	// If there are no failures in verification, go ahead and perform the invocation.
-	var ret *pbstruct.Struct
-	if len(failures) == 0 {
-		diff, err := p.tf.ReadDataDiff(ctx, tfname, rescfg)
-		if err != nil {
-			return nil, errors.Wrapf(err, "reading data source diff for %s", tok)
-		}
-
-		invoke, err := p.tf.ReadDataApply(ctx, tfname, diff)
-		if err != nil {
-			return nil, errors.Wrapf(err, "invoking %s", tok)
-		}
-
-		// Add the special "id" attribute if it wasn't listed in the schema
-		props, err := MakeTerraformResult(ctx, p.tf, invoke, ds.TF.Schema(), ds.Schema.Fields, nil, p.supportsSecrets)
-		if err != nil {
-			return nil, err
-		}
-		if _, has := props["id"]; !has && invoke != nil {
-			props["id"] = resource.NewStringProperty(invoke.ID())
-		}
+	diff, err := p.tf.ReadDataDiff(ctx, tfname, rescfg)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "reading data source diff for %s", tok)
+	}
 
-		ret, err = plugin.MarshalProperties(
-			props,
-			plugin.MarshalOptions{Label: fmt.Sprintf("%s.returns", label)})
-		if err != nil {
-			return nil, err
-		}
+	invoke, err := p.tf.ReadDataApply(ctx, tfname, diff)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "invoking %s", tok)
 	}
 
-	return &pulumirpc.InvokeResponse{
-		Return:   ret,
-		Failures: failures,
-	}, nil
+	// Add the special "id" attribute if it wasn't listed in the schema
+	props, err := MakeTerraformResult(ctx, p.tf, invoke, ds.TF.Schema(), ds.Schema.Fields, nil, p.supportsSecrets)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, has := props["id"]; !has && invoke != nil {
+		props["id"] = resource.NewStringProperty(invoke.ID())
+	}
+	return props, nil, nil
 }
 
 // StreamInvoke dynamically executes a built-in function in the provider. The result is streamed
 // back as a series of messages.
+//
+// Only data sources that set [DataSourceInfo.StreamAttribute] can be streamed. Each element of that attribute is sent
+// as a separate message.
 func (p *Provider) StreamInvoke(
 	req *pulumirpc.InvokeRequest, server pulumirpc.ResourceProvider_StreamInvokeServer,
 ) error {
+	ctx := p.loggingContext(server.Context(), "")
+	ctx, done := p.cancellation.Context(ctx)
+	defer done()
 	tok := tokens.ModuleMember(req.GetTok())
-	return errors.Errorf("unrecognized data function (StreamInvoke): %s", tok)
+	ds, has := p.dataSources[tok]
+	if !has || ds.Schema == nil || ds.Schema.StreamAttribute == "" {
+		return errors.Errorf("unrecognized data function (StreamInvoke): %s", tok)
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "sdkv2.StreamInvoke",
+		opentracing.Tag{Key: "token", Value: string(tok)},
+	)
+	defer span.Finish()
+	p.memStats.collectMemStats(ctx, span)
+
+	label := fmt.Sprintf("%s.StreamInvoke(%s)", p.label(), tok)
+	glog.V(9).Infof("%s executing", label)
+
+	props, failures, err := p.invoke(ctx, tok, ds, req.GetArgs(), label)
+	if err != nil {
+		return err
+	}
+	if len(failures) != 0 {
+		return server.Send(&pulumirpc.InvokeResponse{Failures: failures})
+	}
+
+	return StreamDataSourceResult(props, ds.TF.Schema(), ds.Schema, func(item resource.PropertyMap) error {
+		mitem, err := plugin.MarshalProperties(item, plugin.MarshalOptions{Label: fmt.Sprintf("%s.item", label)})
+		if err != nil {
+			return err
+		}
+		return server.Send(&pulumirpc.InvokeResponse{Return: mitem})
+	})
 }
 
 // GetPluginInfo implements an RPC call that returns the version of this plugin.
@@ -1515,8 +1680,13 @@ func (p *Provider) GetPluginInfo(ctx context.Context, req *pbempty.Empty) (*pulu
 	}, nil
 }
 
-// Cancel requests that the provider cancel all ongoing RPCs. For TF, this is a no-op.
+// Cancel requests that the provider cancel all ongoing RPCs. The contexts of in-flight and subsequent resource
+// operations and invokes are canceled, and the underlying Terraform provider is asked to stop.
 func (p *Provider) Cancel(ctx context.Context, req *pbempty.Empty) (*pbempty.Empty, error) {
+	p.cancellation.Cancel()
+	if err := p.tf.Stop(ctx); err != nil {
+		return nil, errors.Wrap(err, "stopping the Terraform provider")
+	}
 	return &pbempty.Empty{}, nil
 }
 
@@ -2404,6 +2404,270 @@ func TestTransformFromState(t *testing.T) {
 	})
 }
 
+func TestPreStateUpgradeHook(t *testing.T) {
+	provider := func(t *testing.T) *Provider {
+		p := testprovider.AssertProvider(func(data *schema.ResourceData) {
+			// GetRawState is not available during deletes.
+			if raw := data.GetRawState(); !raw.IsNull() {
+				assert.Equal(t, "UPGRADED", raw.AsValueMap()["string_property_value"].AsString())
+			}
+			testprovider.MustSet(data, "string_property_value", "SET")
+		})
+		var called bool
+		t.Cleanup(func() { assert.True(t, called, "PreStateUpgradeHook was not called") })
+		return &Provider{
+			tf:     shimv2.NewProvider(p),
+			config: shimv2.NewSchemaMap(p.Schema),
+			resources: map[tokens.Type]Resource{
+				"Echo": {
+					TF:     shimv2.NewResource(p.ResourcesMap["echo"]),
+					TFName: "echo",
+					Schema: &ResourceInfo{
+						Tok: "Echo",
+						PreStateUpgradeHook: func(args PreStateUpgradeHookArgs) (int64, resource.PropertyMap, error) {
+							assert.Equal(t, int64(1), args.ResourceSchemaVersion)
+							assert.Equal(t, int64(1), args.PriorStateSchemaVersion)
+							assert.Equal(t, "OLD", args.PriorState["stringPropertyValue"].StringValue())
+							p := args.PriorState.Copy()
+							p["stringPropertyValue"] = resource.NewStringProperty("UPGRADED")
+							called = true
+							return 1, p, nil
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("Update", func(t *testing.T) {
+		testutils.Replay(t, provider(t), `
+		{
+		  "method": "/pulumirpc.ResourceProvider/Update",
+		  "request": {
+		    "id": "0",
+		    "urn": "urn:pulumi:dev::teststack::Echo::exres",
+		    "olds": {
+		      "stringPropertyValue": "OLD"
+		    },
+		    "news": {
+		      "stringPropertyValue": "NEW"
+		    }
+		  },
+		  "response": {
+		    "properties": {
+		      "id": "*",
+		      "stringPropertyValue": "SET",
+		      "__meta": "*"
+		    }
+		  }
+		}`)
+	})
+
+	t.Run("Diff", func(t *testing.T) {
+		testutils.Replay(t, provider(t), `
+		{
+		  "method": "/pulumirpc.ResourceProvider/Diff",
+		  "request": {
+		    "id": "0",
+		    "urn": "urn:pulumi:dev::teststack::Echo::exres",
+		    "olds": {
+		      "stringPropertyValue": "OLD"
+		    },
+		    "news": {
+		      "stringPropertyValue": "UPGRADED"
+		    }
+		  },
+		  "response": {
+		    "changes": "DIFF_NONE",
+		    "hasDetailedDiff": true
+		  }
+		}`)
+	})
+
+	t.Run("Read (Refresh)", func(t *testing.T) {
+		testutils.Replay(t, provider(t), `
+		{
+		  "method": "/pulumirpc.ResourceProvider/Read",
+		  "request": {
+		    "id": "0",
+		    "urn": "urn:pulumi:dev::teststack::Echo::exres",
+		    "properties": {
+		      "stringPropertyValue": "OLD"
+		    }
+		  },
+		  "response": {
+		    "id": "0",
+		    "inputs": "*",
+		    "properties": {
+		      "id": "*",
+		      "stringPropertyValue": "SET",
+		      "__meta": "*"
+		    }
+		  }
+		}`)
+	})
+}
+
+func TestComputeID(t *testing.T) {
+	provider := func(t *testing.T) *Provider {
+		p := testprovider.AssertProvider(func(data *schema.ResourceData) {
+			assert.Equal(t, "0", data.Id())
+			testprovider.MustSet(data, "string_property_value", "SET")
+		})
+		return &Provider{
+			tf:     shimv2.NewProvider(p),
+			config: shimv2.NewSchemaMap(p.Schema),
+			resources: map[tokens.Type]Resource{
+				"Echo": {
+					TF:     shimv2.NewResource(p.ResourcesMap["echo"]),
+					TFName: "echo",
+					Schema: &ResourceInfo{
+						Tok:       "Echo",
+						ComputeID: DelegateIDField("stringPropertyValue", "echo", "https://example.com"),
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		testutils.Replay(t, provider(t), `
+		{
+		  "method": "/pulumirpc.ResourceProvider/Create",
+		  "request": {
+		    "urn": "urn:pulumi:dev::teststack::Echo::exres",
+		    "properties": {
+		      "__defaults": [],
+		      "stringPropertyValue": "NEW"
+		    }
+		  },
+		  "response": {
+		    "id": "SET",
+		    "properties": {
+		      "id": "0",
+		      "stringPropertyValue": "SET",
+		      "__meta": "*"
+		    }
+		  }
+		}`)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		testutils.Replay(t, provider(t), `
+		{
+		  "method": "/pulumirpc.ResourceProvider/Update",
+		  "request": {
+		    "id": "SET",
+		    "urn": "urn:pulumi:dev::teststack::Echo::exres",
+		    "olds": {
+		      "id": "0",
+		      "stringPropertyValue": "OLD"
+		    },
+		    "news": {
+		      "stringPropertyValue": "NEW"
+		    }
+		  },
+		  "response": {
+		    "properties": {
+		      "id": "0",
+		      "stringPropertyValue": "SET",
+		      "__meta": "*"
+		    }
+		  }
+		}`)
+	})
+
+	t.Run("Read (Refresh)", func(t *testing.T) {
+		testutils.Replay(t, provider(t), `
+		{
+		  "method": "/pulumirpc.ResourceProvider/Read",
+		  "request": {
+		    "id": "SET",
+		    "urn": "urn:pulumi:dev::teststack::Echo::exres",
+		    "properties": {
+		      "id": "0",
+		      "stringPropertyValue": "OLD"
+		    }
+		  },
+		  "response": {
+		    "id": "SET",
+		    "inputs": "*",
+		    "properties": {
+		      "id": "0",
+		      "stringPropertyValue": "SET",
+		      "__meta": "*"
+		    }
+		  }
+		}`)
+	})
+}
+
+// ComputeID sees the outputs after TransformOutputs in both Create and Read.
+func TestComputeIDAfterTransformOutputs(t *testing.T) {
+	provider := func(t *testing.T) *Provider {
+		p := testprovider.AssertProvider(func(data *schema.ResourceData) {
+			testprovider.MustSet(data, "string_property_value", "SET")
+		})
+		return &Provider{
+			tf:     shimv2.NewProvider(p),
+			config: shimv2.NewSchemaMap(p.Schema),
+			resources: map[tokens.Type]Resource{
+				"Echo": {
+					TF:     shimv2.NewResource(p.ResourcesMap["echo"]),
+					TFName: "echo",
+					Schema: &ResourceInfo{
+						Tok:       "Echo",
+						ComputeID: DelegateIDField("stringPropertyValue", "echo", "https://example.com"),
+						TransformOutputs: func(_ context.Context, pm resource.PropertyMap) (resource.PropertyMap, error) {
+							pm = pm.Copy()
+							pm["stringPropertyValue"] = resource.NewStringProperty("TRANSFORMED")
+							return pm, nil
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		testutils.Replay(t, provider(t), `
+		{
+		  "method": "/pulumirpc.ResourceProvider/Create",
+		  "request": {
+		    "urn": "urn:pulumi:dev::teststack::Echo::exres",
+		    "properties": {
+		      "__defaults": [],
+		      "stringPropertyValue": "NEW"
+		    }
+		  },
+		  "response": {
+		    "id": "TRANSFORMED",
+		    "properties": "*"
+		  }
+		}`)
+	})
+
+	t.Run("Read (Refresh)", func(t *testing.T) {
+		testutils.Replay(t, provider(t), `
+		{
+		  "method": "/pulumirpc.ResourceProvider/Read",
+		  "request": {
+		    "id": "TRANSFORMED",
+		    "urn": "urn:pulumi:dev::teststack::Echo::exres",
+		    "properties": {
+		      "id": "0",
+		      "stringPropertyValue": "OLD"
+		    }
+		  },
+		  "response": {
+		    "id": "TRANSFORMED",
+		    "inputs": "*",
+		    "properties": "*"
+		  }
+		}`)
+	})
+}
+
 // This emulates the situation where we migrate from a state without maxItemsOne
 // which would make the property a list
 // into a state with maxItemsOne, which would flatten the type.
@@ -0,0 +1,92 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+)
+
+// RestoreAssets replaces the outputs of properties with a SchemaInfo.Asset translation by the assets or archives the
+// user passed as inputs, which Terraform only knows as file paths or contents. This mirrors what the AssetTable does
+// for SDKv2 based providers.
+//
+// Internal. The signature of this function can change between major releases. Exposed to share the implementation
+// between SDKv2 and Plugin Framework based providers.
+func RestoreAssets(
+	schemaMap shim.SchemaMap,
+	schemaInfos map[string]*SchemaInfo,
+	inputs, outputs resource.PropertyMap,
+) resource.PropertyMap {
+	if !hasAssetInfo(schemaInfos) {
+		return outputs
+	}
+	ra := &assetRestorer{schemaMap, schemaInfos, resource.NewObjectProperty(inputs)}
+	return ra.restore(make(resource.PropertyPath, 0), resource.NewObjectProperty(outputs)).ObjectValue()
+}
+
+type assetRestorer struct {
+	schemaMap   shim.SchemaMap
+	schemaInfos map[string]*SchemaInfo
+	inputs      resource.PropertyValue
+}
+
+func (ra *assetRestorer) restore(path resource.PropertyPath, value resource.PropertyValue) resource.PropertyValue {
+	if len(path) > 0 && ra.isAsset(path) {
+		if input, ok := path.Get(ra.inputs); ok && (input.IsAsset() || input.IsArchive()) {
+			return input
+		}
+		return value
+	}
+
+	switch {
+	case value.IsArray():
+		av := value.ArrayValue()
+		tvs := make([]resource.PropertyValue, 0, len(av))
+		for i, v := range av {
+			tvs = append(tvs, ra.restore(append(path, i), v))
+		}
+		return resource.NewArrayProperty(tvs)
+	case value.IsObject():
+		pm := make(resource.PropertyMap)
+		for k, v := range value.ObjectValue() {
+			pm[k] = ra.restore(append(path, string(k)), v)
+		}
+		return resource.NewObjectProperty(pm)
+	case value.IsSecret():
+		return resource.MakeSecret(ra.restore(path, value.SecretValue().Element))
+	default:
+		return value
+	}
+}
+
+func (ra *assetRestorer) isAsset(path resource.PropertyPath) bool {
+	schemaPath := PropertyPathToSchemaPath(path, ra.schemaMap, ra.schemaInfos)
+	_, info, err := LookupSchemas(schemaPath, ra.schemaMap, ra.schemaInfos)
+	return err == nil && info != nil && info.Asset != nil
+}
+
+func hasAssetInfo(infos map[string]*SchemaInfo) bool {
+	for _, info := range infos {
+		for info != nil {
+			if info.Asset != nil || hasAssetInfo(info.Fields) {
+				return true
+			}
+			info = info.Elem
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+func TestRestoreAssets(t *testing.T) {
+	schemaMap := schema.SchemaMap{
+		"content": (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+		"name":    (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+		"files": (&schema.Schema{
+			Type:     shim.TypeList,
+			Optional: true,
+			Elem: (&schema.Resource{
+				Schema: schema.SchemaMap{
+					"source": (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+				},
+			}).Shim(),
+		}).Shim(),
+	}
+
+	infos := map[string]*SchemaInfo{
+		"content": {Asset: &AssetTranslation{Kind: BytesAsset}},
+		"files": {
+			Elem: &SchemaInfo{
+				Fields: map[string]*SchemaInfo{
+					"source": {Asset: &AssetTranslation{Kind: FileAsset}},
+				},
+			},
+		},
+	}
+
+	asset, err := resource.NewTextAsset("hello")
+	require.NoError(t, err)
+	fileAsset, err := resource.NewPathAsset("./main.go")
+	require.NoError(t, err)
+
+	inputs := resource.PropertyMap{
+		"content": resource.NewAssetProperty(asset),
+		"name":    resource.NewStringProperty("n"),
+		"files": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.NewObjectProperty(resource.PropertyMap{
+				"source": resource.NewAssetProperty(fileAsset),
+			}),
+		}),
+	}
+
+	outputs := resource.PropertyMap{
+		"content": resource.NewStringProperty("hello"),
+		"name":    resource.NewStringProperty("n"),
+		"files": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.NewObjectProperty(resource.PropertyMap{
+				"source": resource.NewStringProperty("/tmp/file"),
+			}),
+		}),
+	}
+
+	t.Run("restores assets", func(t *testing.T) {
+		assert.Equal(t, inputs, RestoreAssets(schemaMap, infos, inputs, outputs))
+	})
+
+	t.Run("keeps outputs without asset inputs", func(t *testing.T) {
+		assert.Equal(t, outputs, RestoreAssets(schemaMap, infos, resource.PropertyMap{}, outputs))
+	})
+
+	t.Run("ignores fields without asset info", func(t *testing.T) {
+		assert.Equal(t, outputs, RestoreAssets(schemaMap, nil, inputs, outputs))
+	})
+}
@@ -352,6 +352,15 @@ func (ctx *conversionContext) makeTerraformInput(
 	tfs shim.Schema,
 	ps *SchemaInfo,
 ) (interface{}, error) {
+	// Terraform has no notion of secrets: the engine keeps track of which values are secret, so convert the plain
+	// values underneath.
+	for old.IsSecret() {
+		old = old.SecretValue().Element
+	}
+	for v.IsSecret() {
+		v = v.SecretValue().Element
+	}
+
 	// For TypeList or TypeSet with MaxItems==1, we will have projected as a scalar
 	// nested value, and need to wrap it into a single-element array before passing to
 	// Terraform.
@@ -1301,6 +1310,18 @@ func makeTerraformStateWithOpts(
 		meta = map[string]interface{}{"schema_version": strconv.Itoa(res.TF.SchemaVersion())}
 	}
 
+	m, meta, err := applyPreStateUpgradeHook(res, m, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resources with a custom ComputeID have a Pulumi ID that differs from the Terraform ID kept in the state.
+	if res.Schema != nil && res.Schema.ComputeID != nil {
+		if tfID, ok := m["id"]; ok && tfID.IsString() {
+			id = tfID.StringValue()
+		}
+	}
+
 	// Turn the resource properties into a map. For the most part, this is a straight
 	// Mappable, but we use MapReplace because we use float64s and Terraform uses
 	// ints, to represent numbers.
@@ -1314,6 +1335,45 @@ func makeTerraformStateWithOpts(
 	return res.TF.InstanceState(id, inputs, meta)
 }
 
+// applyPreStateUpgradeHook runs the PreStateUpgradeHook of the resource, if any, and records the schema version it
+// returns in the state metadata so that Terraform upgrades the state from that version.
+func applyPreStateUpgradeHook(
+	res Resource, m resource.PropertyMap, meta map[string]interface{},
+) (resource.PropertyMap, map[string]interface{}, error) {
+	if res.Schema == nil || res.Schema.PreStateUpgradeHook == nil {
+		return m, meta, nil
+	}
+
+	var priorVersion int64
+	if v, ok := meta["schema_version"]; ok {
+		s, isString := v.(string)
+		if !isString {
+			return nil, nil, fmt.Errorf("PreStateUpgradeHook failed: unexpected schema_version %v", v)
+		}
+		parsed, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("PreStateUpgradeHook failed to parse schema version: %w", err)
+		}
+		priorVersion = parsed
+	}
+
+	version, props, err := res.Schema.PreStateUpgradeHook(PreStateUpgradeHookArgs{
+		ResourceSchemaVersion:   int64(res.TF.SchemaVersion()),
+		PriorState:              m.Copy(),
+		PriorStateSchemaVersion: priorVersion,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("PreStateUpgradeHook failed: %w", err)
+	}
+
+	newMeta := make(map[string]interface{}, len(meta)+1)
+	for k, v := range meta {
+		newMeta[k] = v
+	}
+	newMeta["schema_version"] = strconv.FormatInt(version, 10)
+	return props, newMeta, nil
+}
+
 // MakeTerraformState converts a Pulumi property bag into its Terraform equivalent.  This requires
 // flattening everything and serializing individual properties as strings.  This is a little awkward, but it's how
 // Terraform represents resource properties (schemas are simply sugar on top).
@@ -97,3 +97,38 @@ func (ss *schemaSecrets) markSchemaSecretsTransform(
 
 	return value
 }
+
+// MarkInputSecrets wraps with resource.MakeSecret every value of outputs whose counterpart in inputs is secret.
+// Terraform does not track secrets, so the values converted from the inputs of a resource lose the secret markers
+// that the engine sent along with them; this restores them by walking both values in parallel.
+func MarkInputSecrets(outputs, inputs resource.PropertyValue) resource.PropertyValue {
+	switch {
+	case outputs.IsSecret():
+		// short-circuit instead of marking nested secrets
+		return outputs
+	case inputs.IsSecret():
+		return resource.MakeSecret(outputs)
+	case outputs.IsArray() && inputs.IsArray():
+		ins := inputs.ArrayValue()
+		outs := make([]resource.PropertyValue, len(outputs.ArrayValue()))
+		for i, v := range outputs.ArrayValue() {
+			if i < len(ins) {
+				v = MarkInputSecrets(v, ins[i])
+			}
+			outs[i] = v
+		}
+		return resource.NewArrayProperty(outs)
+	case outputs.IsObject() && inputs.IsObject():
+		ins := inputs.ObjectValue()
+		outs := make(resource.PropertyMap, len(outputs.ObjectValue()))
+		for k, v := range outputs.ObjectValue() {
+			if in, ok := ins[k]; ok {
+				v = MarkInputSecrets(v, in)
+			}
+			outs[k] = v
+		}
+		return resource.NewObjectProperty(outs)
+	default:
+		return outputs
+	}
+}
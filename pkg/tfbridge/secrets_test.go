@@ -124,3 +124,47 @@ func TestMarkSchemaSecrets(t *testing.T) {
 		})
 	}
 }
+
+func TestMarkInputSecrets(t *testing.T) {
+	secret := resource.MakeSecret
+	str := resource.NewStringProperty
+
+	inputs := resource.NewObjectProperty(resource.PropertyMap{
+		"password": secret(str("hunter2")),
+		"tags": resource.NewArrayProperty([]resource.PropertyValue{
+			str("a"), secret(str("b")),
+		}),
+		"nested": resource.NewObjectProperty(resource.PropertyMap{
+			"token": secret(str("t")),
+			"name":  str("n"),
+		}),
+		"plain": str("p"),
+	})
+	outputs := resource.NewObjectProperty(resource.PropertyMap{
+		"password": str("hunter2"),
+		"tags": resource.NewArrayProperty([]resource.PropertyValue{
+			str("a"), str("b"),
+		}),
+		"nested": resource.NewObjectProperty(resource.PropertyMap{
+			"token": str("t"),
+			"name":  str("n"),
+		}),
+		"plain":    str("p"),
+		"computed": secret(str("c")),
+	})
+
+	expect := resource.NewObjectProperty(resource.PropertyMap{
+		"password": secret(str("hunter2")),
+		"tags": resource.NewArrayProperty([]resource.PropertyValue{
+			str("a"), secret(str("b")),
+		}),
+		"nested": resource.NewObjectProperty(resource.PropertyMap{
+			"token": secret(str("t")),
+			"name":  str("n"),
+		}),
+		"plain":    str("p"),
+		"computed": secret(str("c")),
+	})
+
+	assert.Equal(t, expect, MarkInputSecrets(outputs, inputs))
+}
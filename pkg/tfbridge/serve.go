@@ -19,6 +19,8 @@ import (
 
 	"github.com/pulumi/pulumi/pkg/v3/resource/provider"
 	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/recording"
 )
 
 // Serve fires up a Pulumi resource provider listening to inbound gRPC traffic,
@@ -26,6 +28,7 @@ import (
 func Serve(module, version string, info ProviderInfo, pulumiSchema []byte) error {
 	// Create a new resource provider server and listen for and serve incoming connections.
 	return provider.Main(module, func(host *provider.HostClient) (pulumirpc.ResourceProviderServer, error) {
-		return NewProvider(context.TODO(), host, module, version, info.P, info, pulumiSchema), nil
+		p := NewProvider(context.TODO(), host, module, version, info.P, info, pulumiSchema)
+		return recording.FromEnv(p), nil
 	})
 }
@@ -0,0 +1,71 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"fmt"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+)
+
+// StreamValueKey is the property that holds non-object elements streamed by StreamInvoke.
+const StreamValueKey resource.PropertyKey = "value"
+
+// StreamDataSourceResult passes the elements of the [DataSourceInfo.StreamAttribute] of a data source result to
+// onNext, one at a time. Object elements are passed as is, other elements are passed under [StreamValueKey].
+//
+// Internal. The signature of this function can change between major releases. Exposed to share the implementation
+// between SDKv2 and Plugin Framework based providers.
+func StreamDataSourceResult(
+	result resource.PropertyMap,
+	schemaMap shim.SchemaMap,
+	info *DataSourceInfo,
+	onNext func(resource.PropertyMap) error,
+) error {
+	key := resource.PropertyKey(TerraformToPulumiNameV2(info.StreamAttribute, schemaMap, info.Fields))
+	v, ok := result[key]
+	if !ok || v.IsNull() {
+		return nil
+	}
+
+	secret := v.IsSecret()
+	if secret {
+		v = v.SecretValue().Element
+	}
+	if !v.IsArray() {
+		return fmt.Errorf("cannot stream %q: expected a list, got %s", key, v.TypeString())
+	}
+
+	for _, elem := range v.ArrayValue() {
+		var item resource.PropertyMap
+		if elem.IsObject() {
+			item = elem.ObjectValue()
+		} else {
+			item = resource.PropertyMap{StreamValueKey: elem}
+		}
+		if secret {
+			item = item.Copy()
+			for k, v := range item {
+				item[k] = resource.MakeSecret(v)
+			}
+		}
+		if err := onNext(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	schemav2 "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+
+	shimv2 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2"
+)
+
+func TestStreamInvoke(t *testing.T) {
+	tfProvider := &schemav2.Provider{
+		DataSourcesMap: map[string]*schemav2.Resource{
+			"test_images": {
+				Schema: map[string]*schemav2.Schema{
+					"prefix": {Type: schemav2.TypeString, Required: true},
+					"images": {
+						Type:     schemav2.TypeList,
+						Computed: true,
+						Elem: &schemav2.Resource{Schema: map[string]*schemav2.Schema{
+							"image_id": {Type: schemav2.TypeString, Computed: true},
+						}},
+					},
+					"ids": {
+						Type:     schemav2.TypeList,
+						Computed: true,
+						Elem:     &schemav2.Schema{Type: schemav2.TypeString},
+					},
+				},
+				ReadContext: func(_ context.Context, rd *schemav2.ResourceData, _ interface{}) diag.Diagnostics {
+					prefix := rd.Get("prefix").(string)
+					rd.SetId(prefix)
+					images := []interface{}{}
+					ids := []interface{}{}
+					for _, id := range []string{"a", "b", "c"} {
+						images = append(images, map[string]interface{}{"image_id": prefix + id})
+						ids = append(ids, prefix+id)
+					}
+					if err := rd.Set("images", images); err != nil {
+						return diag.FromErr(err)
+					}
+					return diag.FromErr(rd.Set("ids", ids))
+				},
+			},
+		},
+	}
+
+	newProvider := func(streamAttribute string) *Provider {
+		shimProvider := shimv2.NewProvider(tfProvider)
+		p := &Provider{
+			tf:     shimProvider,
+			config: shimv2.NewSchemaMap(tfProvider.Schema),
+			info: ProviderInfo{
+				P: shimProvider,
+				DataSources: map[string]*DataSourceInfo{
+					"test_images": {Tok: "test:index:getImages", StreamAttribute: streamAttribute},
+				},
+			},
+		}
+		p.initResourceMaps()
+		return p
+	}
+
+	streamInvoke := func(p *Provider) ([]*pulumirpc.InvokeResponse, error) {
+		server := &streamInvokeServer{ctx: context.Background()}
+		err := p.StreamInvoke(&pulumirpc.InvokeRequest{
+			Tok: "test:index:getImages",
+			Args: &structpb.Struct{Fields: map[string]*structpb.Value{
+				"prefix": structpb.NewStringValue("img-"),
+			}},
+		}, server)
+		return server.sent, err
+	}
+
+	t.Run("objects", func(t *testing.T) {
+		sent, err := streamInvoke(newProvider("images"))
+		require.NoError(t, err)
+		require.Len(t, sent, 3)
+		for i, id := range []string{"img-a", "img-b", "img-c"} {
+			assert.Equal(t, id, sent[i].GetReturn().GetFields()["imageId"].GetStringValue())
+		}
+	})
+
+	t.Run("scalars", func(t *testing.T) {
+		sent, err := streamInvoke(newProvider("ids"))
+		require.NoError(t, err)
+		require.Len(t, sent, 3)
+		for i, id := range []string{"img-a", "img-b", "img-c"} {
+			assert.Equal(t, id, sent[i].GetReturn().GetFields()["value"].GetStringValue())
+		}
+	})
+
+	t.Run("not streamable", func(t *testing.T) {
+		_, err := streamInvoke(newProvider(""))
+		assert.ErrorContains(t, err, "unrecognized data function (StreamInvoke)")
+	})
+}
+
+type streamInvokeServer struct {
+	grpc.ServerStream
+
+	ctx  context.Context
+	sent []*pulumirpc.InvokeResponse
+}
+
+func (s *streamInvokeServer) Context() context.Context { return s.ctx }
+
+func (s *streamInvokeServer) Send(resp *pulumirpc.InvokeResponse) error {
+	s.sent = append(s.sent, resp)
+	return nil
+}
@@ -0,0 +1,485 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/hashicorp/go-uuid"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+)
+
+// TerraformStateMigration holds the managed resources of a Terraform state file translated to Pulumi resources by
+// MigrateTerraformState. It can be written as a `pulumi import --file` document with WriteImportFile, or as a
+// deployment for `pulumi stack import` with WriteCheckpoint.
+type TerraformStateMigration struct {
+	// Resources are the migrated resource instances, in the order of the state file.
+	Resources []MigratedResource
+	// Skipped are the resource instances of this provider that could not be migrated.
+	Skipped []SkippedTerraformResource
+
+	pkg     tokens.Package // the Pulumi package of the provider, which names the type of the default provider.
+	version string
+}
+
+// MigratedResource is a Terraform resource instance translated to a Pulumi resource.
+type MigratedResource struct {
+	Address string      // the Terraform address of the instance, such as module.net.aws_vpc.main[0].
+	Type    tokens.Type // the Pulumi type of the resource.
+	Name    string      // the logical name of the resource, derived from the Terraform address.
+	ID      resource.ID // the Pulumi ID of the resource.
+	Inputs  resource.PropertyMap
+	Outputs resource.PropertyMap
+}
+
+// SkippedTerraformResource is a Terraform resource instance that MigrateTerraformState could not migrate.
+type SkippedTerraformResource struct {
+	Address string
+	Reason  string
+}
+
+// tfState is the subset of the version 4 Terraform state format read by MigrateTerraformState.
+type tfState struct {
+	Version   int `json:"version"`
+	Resources []struct {
+		Module    string `json:"module"`
+		Mode      string `json:"mode"`
+		Type      string `json:"type"`
+		Name      string `json:"name"`
+		Instances []struct {
+			IndexKey       interface{}            `json:"index_key"`
+			Status         string                 `json:"status"`
+			Deposed        string                 `json:"deposed"`
+			SchemaVersion  int                    `json:"schema_version"`
+			Attributes     map[string]interface{} `json:"attributes"`
+			AttributesFlat map[string]string      `json:"attributes_flat"`
+		} `json:"instances"`
+	} `json:"resources"`
+}
+
+// TerraformInstanceMigrator translates the attributes of an instance of the Terraform resource tfType, recorded at
+// schemaVersion, into a Pulumi resource. The Address and Name of the result are set by the caller.
+type TerraformInstanceMigrator func(
+	ctx context.Context, tfType string, info *ResourceInfo, schemaVersion int, attributes map[string]interface{},
+) (*MigratedResource, error)
+
+// MigrateTerraformState translates the managed resources of a Terraform state file in the version 4 JSON format into
+// Pulumi resources, using the mappings of prov.
+//
+// States recorded at an older schema version are upgraded when prov.P implements shim.StateUpgrader. The provider is
+// not configured and no cloud API is called, so state upgraders that need a configured provider fail. Instances that
+// cannot be migrated, such as tainted instances or instances of resources that prov does not map, are reported in
+// Skipped rather than failing the migration. Resources managed by other providers and data sources are ignored.
+func MigrateTerraformState(
+	ctx context.Context, prov ProviderInfo, tfstate io.Reader,
+) (*TerraformStateMigration, error) {
+	return MigrateTerraformStateWith(ctx, prov, tfstate, nil)
+}
+
+// MigrateTerraformStateWith is like MigrateTerraformState, but translates each resource instance with migrate. A nil
+// migrate translates them with prov.P, as MigrateTerraformState does. Providers whose prov.P only holds a schema,
+// such as Plugin Framework providers, pass a migrate which upgrades the state with the provider itself.
+func MigrateTerraformStateWith(
+	ctx context.Context, prov ProviderInfo, tfstate io.Reader, migrate TerraformInstanceMigrator,
+) (*TerraformStateMigration, error) {
+	if migrate == nil {
+		migrate = func(
+			ctx context.Context, tfType string, info *ResourceInfo, version int, attributes map[string]interface{},
+		) (*MigratedResource, error) {
+			tfRes, _ := prov.P.ResourcesMap().GetOk(tfType)
+			res := &Resource{Schema: info, TF: tfRes, TFName: tfType}
+			return migrateTerraformInstance(ctx, prov.P, res, version, attributes)
+		}
+	}
+
+	var state tfState
+	if err := json.NewDecoder(tfstate).Decode(&state); err != nil {
+		return nil, fmt.Errorf("reading Terraform state: %w", err)
+	}
+	if state.Version != 4 {
+		return nil, fmt.Errorf("unsupported Terraform state version %d, expected 4", state.Version)
+	}
+
+	m := &TerraformStateMigration{pkg: providerPackage(prov), version: prov.Version}
+	names := map[string]int{}
+	for _, r := range state.Resources {
+		if r.Mode != "managed" {
+			continue
+		}
+		if _, ok := prov.P.ResourcesMap().GetOk(r.Type); !ok {
+			continue
+		}
+
+		for _, inst := range r.Instances {
+			address := tfStateAddress(r.Module, r.Type, r.Name, inst.IndexKey)
+			skip := func(reason string, args ...interface{}) {
+				m.Skipped = append(m.Skipped, SkippedTerraformResource{
+					Address: address,
+					Reason:  fmt.Sprintf(reason, args...),
+				})
+			}
+
+			info, ok := prov.Resources[r.Type]
+			if !ok || info.Tok == "" {
+				skip("resource %s is not mapped by the provider", r.Type)
+				continue
+			}
+			switch {
+			case inst.Deposed != "":
+				skip("instance is deposed")
+				continue
+			case inst.Status == "tainted":
+				skip("instance is tainted")
+				continue
+			case inst.Attributes == nil && inst.AttributesFlat != nil:
+				skip("state uses the flatmap format of Terraform 0.11; refresh it with a newer Terraform first")
+				continue
+			}
+
+			migrated, err := migrate(ctx, r.Type, info, inst.SchemaVersion, inst.Attributes)
+			if err != nil {
+				skip("%v", err)
+				continue
+			}
+			migrated.Address = address
+			migrated.Name = uniqueName(names, tfStateLogicalName(r.Module, r.Name, inst.IndexKey))
+			m.Resources = append(m.Resources, *migrated)
+		}
+	}
+	return m, nil
+}
+
+// providerPackage returns the Pulumi package of prov, taken from the tokens of its resources. The package is not always
+// prov.Name, the name of the Terraform provider: azurerm is published as the azure package, for example.
+func providerPackage(prov ProviderInfo) tokens.Package {
+	names := make([]string, 0, len(prov.Resources))
+	for name := range prov.Resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if info := prov.Resources[name]; info != nil && info.Tok != "" {
+			return info.Tok.Module().Package()
+		}
+	}
+	return tokens.Package(prov.Name)
+}
+
+// migrateTerraformInstance converts the attributes of a resource instance recorded at the given schema version into
+// Pulumi inputs and outputs, in the same way Read does for the state returned by Terraform.
+func migrateTerraformInstance(
+	ctx context.Context, p shim.Provider, res *Resource, version int, attributes map[string]interface{},
+) (*MigratedResource, error) {
+	var state shim.InstanceState
+	if upgrader, ok := p.(shim.StateUpgrader); ok {
+		upgraded, err := upgrader.UpgradeState(ctx, res.TFName, version, attributes)
+		if err != nil {
+			return nil, fmt.Errorf("upgrading state from schema version %d: %w", version, err)
+		}
+		state = upgraded
+	} else {
+		if current := res.TF.SchemaVersion(); version != current {
+			return nil, fmt.Errorf("state has schema version %d, but the provider cannot upgrade it to %d",
+				version, current)
+		}
+		var meta map[string]interface{}
+		if version > 0 {
+			meta = map[string]interface{}{"schema_version": strconv.Itoa(version)}
+		}
+		id, _ := attributes["id"].(string)
+		s, err := res.TF.InstanceState(id, attributes, meta)
+		if err != nil {
+			return nil, err
+		}
+		state = s
+	}
+	if state == nil || state.ID() == "" {
+		return nil, fmt.Errorf("state has no ID")
+	}
+
+	props, err := MakeTerraformResult(ctx, p, state, res.TF.Schema(), res.Schema.Fields, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	if res.Schema.TransformOutputs != nil {
+		props, err = res.Schema.TransformOutputs(ctx, props)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Compute the ID from the transformed outputs, as Read does.
+	id, err := res.computeID(ctx, state, props)
+	if err != nil {
+		return nil, fmt.Errorf("computing the ID: %w", err)
+	}
+	inputs, err := ExtractInputsFromOutputs(nil, props, res.TF.Schema(), res.Schema.Fields, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MigratedResource{
+		Type:    res.Schema.Tok,
+		ID:      resource.ID(id),
+		Inputs:  inputs,
+		Outputs: props,
+	}, nil
+}
+
+// MigrateTerraformStateFile migrates the Terraform state file at path with MigrateTerraformStateWith and writes the
+// result to stdout in the given format, either import or checkpoint. Skipped instances are reported to stderr. It
+// implements the -migrate-tfstate flag of the provider binary.
+func MigrateTerraformStateFile(
+	ctx context.Context, prov ProviderInfo, migrate TerraformInstanceMigrator, path, format string,
+	opts CheckpointOptions, stdout, stderr io.Writer,
+) error {
+	if format != "import" && format != "checkpoint" {
+		return fmt.Errorf("unknown migration format %q, expected import or checkpoint", format)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer contract.IgnoreClose(f)
+
+	m, err := MigrateTerraformStateWith(ctx, prov, f, migrate)
+	if err != nil {
+		return fmt.Errorf("migrating %s: %w", path, err)
+	}
+	for _, s := range m.Skipped {
+		fmt.Fprintf(stderr, "warning: skipped %s: %s\n", s.Address, s.Reason)
+	}
+
+	if format == "checkpoint" {
+		return m.WriteCheckpoint(stdout, opts)
+	}
+	return m.WriteImportFile(stdout)
+}
+
+// tfStateModulePattern matches one step of a module path such as module.network or module.zone["us-east-1a"].
+var tfStateModulePattern = regexp.MustCompile(`module\.([^.\[]+)(?:\[("[^"]*"|[0-9]+)\])?`)
+
+// tfStateIndex formats an instance key the way Terraform does in addresses.
+func tfStateIndex(key interface{}) string {
+	switch key := key.(type) {
+	case nil:
+		return ""
+	case string:
+		return "[" + strconv.Quote(key) + "]"
+	case float64:
+		return "[" + strconv.FormatFloat(key, 'f', -1, 64) + "]"
+	default:
+		return fmt.Sprintf("[%v]", key)
+	}
+}
+
+func tfStateAddress(module, typ, name string, key interface{}) string {
+	address := typ + "." + name + tfStateIndex(key)
+	if module != "" {
+		address = module + "." + address
+	}
+	return address
+}
+
+// tfStateLogicalName derives a Pulumi logical name from a Terraform address by joining the module names, the resource
+// name and the instance keys with dashes: module.net["a"].aws_subnet.main[0] becomes net-a-main-0.
+func tfStateLogicalName(module, name string, key interface{}) string {
+	var parts []string
+	for _, m := range tfStateModulePattern.FindAllStringSubmatch(module, -1) {
+		parts = append(parts, m[1])
+		if m[2] != "" {
+			parts = append(parts, strings.Trim(m[2], `"`))
+		}
+	}
+	parts = append(parts, name)
+	if index := tfStateIndex(key); index != "" {
+		parts = append(parts, strings.Trim(index, `[]"`))
+	}
+	return strings.Join(parts, "-")
+}
+
+// uniqueName returns name, suffixed with a counter if it was returned before.
+func uniqueName(seen map[string]int, name string) string {
+	n := seen[name]
+	seen[name]++
+	if n == 0 {
+		return name
+	}
+	return uniqueName(seen, fmt.Sprintf("%s-%d", name, n+1))
+}
+
+// WriteImportFile writes the migrated resources as a document for `pulumi import --file`, which reads each resource
+// from the cloud and generates the program that manages it.
+func (m *TerraformStateMigration) WriteImportFile(w io.Writer) error {
+	type importSpec struct {
+		Type    tokens.Type `json:"type"`
+		Name    string      `json:"name"`
+		ID      resource.ID `json:"id"`
+		Version string      `json:"version,omitempty"`
+	}
+
+	version := ""
+	if v, err := semver.ParseTolerant(m.version); err == nil {
+		version = v.String()
+	}
+	specs := make([]importSpec, len(m.Resources))
+	for i, r := range m.Resources {
+		specs[i] = importSpec{Type: r.Type, Name: r.Name, ID: r.ID, Version: version}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Resources []importSpec `json:"resources"`
+	}{specs})
+}
+
+// CheckpointOptions configures TerraformStateMigration.WriteCheckpoint.
+type CheckpointOptions struct {
+	Stack   string // the name of the stack the deployment is imported into; required.
+	Project string // the name of the project of the stack; required.
+
+	// The ID of the default provider the resources are attached to. A random ID is generated when empty.
+	ProviderID string
+	// The time recorded in the manifest of the deployment. The current time is used when zero.
+	Time time.Time
+}
+
+// WriteCheckpoint writes the migrated resources, along with the stack resource and the default provider they belong
+// to, as a deployment for `pulumi stack import`. Secret outputs are written in plaintext and encrypted by the secrets
+// provider of the stack on import.
+func (m *TerraformStateMigration) WriteCheckpoint(w io.Writer, opts CheckpointOptions) error {
+	if opts.Stack == "" || opts.Project == "" {
+		return fmt.Errorf("a stack and a project are required to write a checkpoint")
+	}
+	if opts.ProviderID == "" {
+		id, err := uuid.GenerateUUID()
+		if err != nil {
+			return err
+		}
+		opts.ProviderID = id
+	}
+	if opts.Time.IsZero() {
+		opts.Time = time.Now()
+	}
+	stack, project := tokens.QName(opts.Stack), tokens.PackageName(opts.Project)
+
+	stackURN := resource.DefaultRootStackURN(stack, project)
+	resources := []apitype.ResourceV3{{URN: stackURN, Type: resource.RootStackType}}
+
+	providerType := tokens.Type("pulumi:providers:" + string(m.pkg))
+	providerName := "default"
+	providerProps := map[string]interface{}{}
+	if v, err := semver.ParseTolerant(m.version); err == nil {
+		providerName += fmt.Sprintf("_%d_%d_%d", v.Major, v.Minor, v.Patch)
+		for _, pre := range v.Pre {
+			providerName += "_" + pre.String()
+		}
+		for _, build := range v.Build {
+			providerName += "_" + build
+		}
+		providerProps["version"] = v.String()
+	}
+	providerURN := resource.NewURN(stack, project, "", providerType, providerName)
+	resources = append(resources, apitype.ResourceV3{
+		URN:     providerURN,
+		Custom:  true,
+		ID:      resource.ID(opts.ProviderID),
+		Type:    providerType,
+		Inputs:  providerProps,
+		Outputs: providerProps,
+	})
+	providerRef := string(providerURN) + "::" + opts.ProviderID
+
+	for _, r := range m.Resources {
+		resources = append(resources, apitype.ResourceV3{
+			URN:      resource.NewURN(stack, project, "", r.Type, r.Name),
+			Custom:   true,
+			ID:       r.ID,
+			Type:     r.Type,
+			Inputs:   serializeCheckpointProperties(r.Inputs),
+			Outputs:  serializeCheckpointProperties(r.Outputs),
+			Parent:   stackURN,
+			Provider: providerRef,
+		})
+	}
+
+	deployment, err := json.Marshal(apitype.DeploymentV3{
+		Manifest:  apitype.ManifestV1{Time: opts.Time},
+		Resources: resources,
+	})
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(apitype.UntypedDeployment{
+		Version:    apitype.DeploymentSchemaVersionCurrent,
+		Deployment: deployment,
+	})
+}
+
+// serializeCheckpointProperties serializes properties the way the engine does in checkpoints, with secrets in
+// plaintext.
+func serializeCheckpointProperties(props resource.PropertyMap) map[string]interface{} {
+	dst := make(map[string]interface{}, len(props))
+	for _, k := range props.StableKeys() {
+		dst[string(k)] = serializeCheckpointValue(props[k])
+	}
+	return dst
+}
+
+func serializeCheckpointValue(v resource.PropertyValue) interface{} {
+	switch {
+	case v.IsNull() || v.IsComputed() || v.IsOutput():
+		return nil
+	case v.IsArray():
+		arr := make([]interface{}, len(v.ArrayValue()))
+		for i, e := range v.ArrayValue() {
+			arr[i] = serializeCheckpointValue(e)
+		}
+		return arr
+	case v.IsObject():
+		return serializeCheckpointProperties(v.ObjectValue())
+	case v.IsAsset():
+		return v.AssetValue().Serialize()
+	case v.IsArchive():
+		return v.ArchiveValue().Serialize()
+	case v.IsSecret():
+		plaintext, err := json.Marshal(serializeCheckpointValue(v.SecretValue().Element))
+		if err != nil {
+			// The value was just serialized from a property value, so this should never happen.
+			plaintext = []byte("null")
+		}
+		return apitype.SecretV1{Sig: resource.SecretSig, Plaintext: string(plaintext)}
+	default:
+		return v.V
+	}
+}
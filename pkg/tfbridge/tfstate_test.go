@@ -0,0 +1,193 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	schemav2 "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	shimv2 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2"
+)
+
+const testTerraformState = `{
+  "version": 4,
+  "terraform_version": "1.7.0",
+  "resources": [
+    {
+      "mode": "managed", "type": "test_bucket", "name": "main",
+      "instances": [{
+        "schema_version": 1,
+        "attributes": {"id": "b-1", "name": "main", "size": 3, "tags": {"team": "infra"}, "secret_key": "s3cr3t"}
+      }]
+    },
+    {
+      "module": "module.env[\"prod\"]", "mode": "managed", "type": "test_bucket", "name": "logs", "each": "list",
+      "instances": [{"index_key": 0, "schema_version": 0, "attributes": {"id": "b-2", "bucket_name": "logs"}}]
+    },
+    {
+      "mode": "managed", "type": "test_bucket", "name": "broken",
+      "instances": [{"status": "tainted", "schema_version": 1, "attributes": {"id": "b-3", "name": "broken"}}]
+    },
+    {
+      "mode": "managed", "type": "test_unmapped", "name": "x",
+      "instances": [{"schema_version": 0, "attributes": {"id": "u-1"}}]
+    },
+    {
+      "mode": "data", "type": "test_bucket", "name": "lookup",
+      "instances": [{"schema_version": 0, "attributes": {"id": "b-1", "name": "main"}}]
+    },
+    {
+      "mode": "managed", "type": "other_thing", "name": "y",
+      "instances": [{"schema_version": 0, "attributes": {"id": "o-1"}}]
+    }
+  ]
+}`
+
+func testTerraformStateProvider(planResourceChange bool) ProviderInfo {
+	bucketV0 := &schemav2.Resource{Schema: map[string]*schemav2.Schema{
+		"bucket_name": {Type: schemav2.TypeString, Required: true},
+	}}
+	p := &schemav2.Provider{ResourcesMap: map[string]*schemav2.Resource{
+		"test_bucket": {
+			SchemaVersion: 1,
+			Schema: map[string]*schemav2.Schema{
+				"name":       {Type: schemav2.TypeString, Required: true},
+				"size":       {Type: schemav2.TypeInt, Optional: true},
+				"tags":       {Type: schemav2.TypeMap, Optional: true, Elem: &schemav2.Schema{Type: schemav2.TypeString}},
+				"secret_key": {Type: schemav2.TypeString, Computed: true, Sensitive: true},
+			},
+			StateUpgraders: []schemav2.StateUpgrader{{
+				Version: 0,
+				Type:    bucketV0.CoreConfigSchema().ImpliedType(),
+				Upgrade: func(_ context.Context, raw map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+					raw["name"] = raw["bucket_name"]
+					delete(raw, "bucket_name")
+					return raw, nil
+				},
+			}},
+		},
+		"test_unmapped": {Schema: map[string]*schemav2.Schema{
+			"name": {Type: schemav2.TypeString, Optional: true},
+		}},
+	}}
+	return ProviderInfo{
+		Name:    "test",
+		Version: "1.2.3",
+		P: shimv2.NewProvider(p, shimv2.WithPlanResourceChange(func(string) bool {
+			return planResourceChange
+		})),
+		Resources: map[string]*ResourceInfo{
+			"test_bucket": {Tok: "test:index/bucket:Bucket"},
+		},
+	}
+}
+
+func TestMigrateTerraformState(t *testing.T) {
+	ctx := context.Background()
+
+	for name, planResourceChange := range map[string]bool{
+		"default":              false,
+		"plan resource change": true,
+	} {
+		planResourceChange := planResourceChange
+		t.Run(name, func(t *testing.T) {
+			m, err := MigrateTerraformState(ctx, testTerraformStateProvider(planResourceChange),
+				strings.NewReader(testTerraformState))
+			require.NoError(t, err)
+
+			assert.Equal(t, []SkippedTerraformResource{
+				{Address: "test_bucket.broken", Reason: "instance is tainted"},
+				{Address: "test_unmapped.x", Reason: "resource test_unmapped is not mapped by the provider"},
+			}, m.Skipped)
+
+			require.Len(t, m.Resources, 2)
+			main, logs := m.Resources[0], m.Resources[1]
+
+			assert.Equal(t, "test_bucket.main", main.Address)
+			assert.Equal(t, "main", main.Name)
+			assert.Equal(t, resource.ID("b-1"), main.ID)
+			assert.Equal(t, resource.NewNumberProperty(3), main.Outputs["size"])
+			assert.True(t, main.Outputs["secretKey"].IsSecret())
+			assert.Equal(t, resource.NewStringProperty("infra"), main.Inputs["tags"].ObjectValue()["team"])
+			assert.NotContains(t, main.Inputs, resource.PropertyKey("secretKey"))
+
+			// The second instance was recorded at schema version 0 and is upgraded by the state upgrader.
+			assert.Equal(t, `module.env["prod"].test_bucket.logs[0]`, logs.Address)
+			assert.Equal(t, "env-prod-logs-0", logs.Name)
+			assert.Equal(t, resource.NewStringProperty("logs"), logs.Inputs["name"])
+			assert.Equal(t, resource.NewStringProperty(`{"schema_version":"1"}`), logs.Outputs[metaKey])
+		})
+	}
+}
+
+func TestMigrateTerraformStateOutputs(t *testing.T) {
+	// The default provider is named after the Pulumi package, which may differ from the name of the Terraform
+	// provider, as azure does from azurerm.
+	prov := testTerraformStateProvider(false)
+	prov.Name = "testtf"
+	m, err := MigrateTerraformState(context.Background(), prov, strings.NewReader(testTerraformState))
+	require.NoError(t, err)
+
+	t.Run("import", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, m.WriteImportFile(&buf))
+		assert.JSONEq(t, `{"resources": [
+			{"type": "test:index/bucket:Bucket", "name": "main", "id": "b-1", "version": "1.2.3"},
+			{"type": "test:index/bucket:Bucket", "name": "env-prod-logs-0", "id": "b-2", "version": "1.2.3"}
+		]}`, buf.String())
+	})
+
+	t.Run("checkpoint", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.Error(t, m.WriteCheckpoint(&buf, CheckpointOptions{}))
+		require.NoError(t, m.WriteCheckpoint(&buf, CheckpointOptions{
+			Stack:      "dev",
+			Project:    "proj",
+			ProviderID: "provider-id",
+			Time:       time.Unix(0, 0),
+		}))
+
+		var untyped apitype.UntypedDeployment
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &untyped))
+		assert.Equal(t, apitype.DeploymentSchemaVersionCurrent, untyped.Version)
+		var deployment apitype.DeploymentV3
+		require.NoError(t, json.Unmarshal(untyped.Deployment, &deployment))
+
+		require.Len(t, deployment.Resources, 4)
+		stack, provider, main := deployment.Resources[0], deployment.Resources[1], deployment.Resources[2]
+		assert.Equal(t, resource.URN("urn:pulumi:dev::proj::pulumi:pulumi:Stack::proj-dev"), stack.URN)
+		assert.Equal(t, resource.URN("urn:pulumi:dev::proj::pulumi:providers:test::default_1_2_3"), provider.URN)
+		assert.Equal(t, map[string]interface{}{"version": "1.2.3"}, provider.Inputs)
+
+		assert.Equal(t, resource.URN("urn:pulumi:dev::proj::test:index/bucket:Bucket::main"), main.URN)
+		assert.Equal(t, stack.URN, main.Parent)
+		assert.Equal(t, string(provider.URN)+"::provider-id", main.Provider)
+		assert.Equal(t, resource.ID("b-1"), main.ID)
+		assert.Equal(t, map[string]interface{}{
+			"4dabf18193072939515e22adb298388d": "1b47061264138c4ac30d75fd1eb44270",
+			"plaintext":                        `"s3cr3t"`,
+		}, main.Outputs["secretKey"])
+	})
+}
@@ -126,6 +126,9 @@ type entityDocs struct {
 
 	// Import is the import details for the resource
 	Import string
+
+	// findings are reported by --docs-report.
+	findings docsFindings
 }
 
 func (ed *entityDocs) ensure() {
@@ -278,9 +281,9 @@ func getDocsForResource(g *Generator, source DocsSource, kind DocKind,
 	var err error
 	switch kind {
 	case ResourceDocs:
-		docFile, err = source.getResource(rawname, docInfo)
+		docFile, err = source.GetResource(rawname, docInfo)
 	case DataSourceDocs:
-		docFile, err = source.getDatasource(rawname, docInfo)
+		docFile, err = source.GetDatasource(rawname, docInfo)
 	default:
 		panic("unknown docs kind")
 	}
@@ -297,7 +300,6 @@ func getDocsForResource(g *Generator, source DocsSource, kind DocKind,
 	}
 
 	if docFile == nil {
-		entitiesMissingDocs++
 		msg := fmt.Sprintf("could not find docs for %v %v. Override the Docs property in the %v mapping. See "+
 			"type tfbridge.DocInfo for details.", kind, formatEntityName(rawname), kind)
 
@@ -313,7 +315,7 @@ func getDocsForResource(g *Generator, source DocsSource, kind DocKind,
 		// this function who do not expect docs not being found to return an error, and the cost of doing the idiomatic
 		// thing (returning an error) was too high.
 		g.warn(msg)
-		return entityDocs{}, nil
+		return entityDocs{findings: docsFindings{Missing: true}}, nil
 	}
 
 	markdownBytes, markdownFileName := docFile.Content, docFile.FileName
@@ -724,7 +726,7 @@ func (p *tfMarkdownParser) parseSection(h2Section []string) error {
 	switch header {
 	case "Timeout", "Timeouts", "User Project Override", "User Project Overrides":
 		p.sink.debug("Ignoring doc section [%v] for [%v]", header, p.rawname)
-		ignoredDocHeaders[header]++
+		p.ret.findings.IgnoredSections = append(p.ret.findings.IgnoredSections, header)
 		return nil
 	case "Example Usage":
 		sectionKind = sectionExampleUsage
@@ -895,7 +897,7 @@ func parseArgReferenceSection(subsection []string, ret *entityDocs) {
 		if nested != "" {
 			// We found this line within a nested field. We should record it as such.
 			if ret.Arguments[nested] == nil {
-				totalArgumentsFromDocs++
+				ret.findings.ArgumentsFromDocs++
 			}
 			ret.Arguments[nested.join(name)] = &argumentDocs{desc}
 		} else {
@@ -903,7 +905,7 @@ func parseArgReferenceSection(subsection []string, ret *entityDocs) {
 				return
 			}
 			ret.Arguments[docsPath(name)] = &argumentDocs{description: desc}
-			totalArgumentsFromDocs++
+			ret.findings.ArgumentsFromDocs++
 		}
 	}
 
@@ -1275,6 +1277,7 @@ func (g *Generator) convertExamples(docs string, path examplePath) string {
 			Token:       path.Token(),
 			ExamplePath: path.String(),
 		}) {
+			g.docsMetrics.recordSkippedExample(path, docs)
 			return ""
 		}
 	}
@@ -1471,6 +1474,7 @@ func (g *Generator) convertExamplesInner(
 						// block, and any surrounding text.
 						stripSection = true
 						stripSectionHeader = tfBlock.headerStart
+						g.docsMetrics.recordUnconvertedSection(path, docs, tfBlock.headerStart)
 					} else {
 						// append any headers and following text first
 						if hasHeader {
@@ -1572,6 +1576,12 @@ func (g *Generator) convert(
 		g.coverageTracker.languageConversionPanic(e, languageName, fmt.Sprintf("%v", v))
 	}()
 
+	// Examples are converted concurrently with --parallel, but the PCL package schemas held by g.packageCache are
+	// not safe for concurrent use, so only one conversion runs at a time. Parsing the docs, looking up the examples
+	// cache and rendering the converted examples still run in parallel.
+	g.convertMu.Lock()
+	defer g.convertMu.Unlock()
+
 	files, diags, err = convert.Convert(convert.Options{
 		Loader:                   newLoader(g.pluginHost),
 		Root:                     input,
@@ -1843,6 +1853,8 @@ func genLanguageToSlice(input Language) []string {
 		return []string{convert.LanguageCSharp}
 	case Golang:
 		return []string{convert.LanguageGo}
+	case Java:
+		return []string{convert.LanguageJava}
 	case PCL:
 		return []string{convert.LanguagePulumi}
 	case Schema:
@@ -1866,6 +1878,7 @@ func cleanupDoc(
 ) (entityDocs, bool) {
 	elidedDoc := false
 	newargs := make(map[docsPath]*argumentDocs, len(doc.Arguments))
+	findings := doc.findings
 
 	for k, v := range doc.Arguments {
 		if k.nested() {
@@ -1875,12 +1888,11 @@ func cleanupDoc(
 		}
 		cleanedText, elided := reformatText(infoCtx, v.description, footerLinks)
 		if elided {
+			findings.ElidedArguments = append(findings.ElidedArguments, string(k))
 			if k.nested() {
-				elidedNestedArguments++
 				g.warn("Found <elided> in docs for nested argument [%v] in [%v]. The argument's description will be "+
 					"dropped in the Pulumi provider.", k, name)
 			} else {
-				elidedArguments++
 				g.warn("Found <elided> in docs for argument [%v] in [%v]. The argument's description will be dropped in "+
 					"the Pulumi provider.", k, name)
 			}
@@ -1933,11 +1945,14 @@ func cleanupDoc(
 		}
 	}
 
+	sort.Strings(findings.ElidedArguments)
+
 	return entityDocs{
 		Description: cleanupText,
 		Arguments:   newargs,
 		Attributes:  newattrs,
 		Import:      doc.Import,
+		findings:    findings,
 	}, elidedDoc
 }
 
@@ -1469,7 +1469,7 @@ func TestErrorNilDocs(t *testing.T) {
 
 type mockSource map[string]string
 
-func (m mockSource) getResource(rawname string, info *tfbridge.DocInfo) (*DocFile, error) {
+func (m mockSource) GetResource(rawname string, info *tfbridge.DocInfo) (*DocFile, error) {
 	f, ok := m[rawname]
 	if !ok {
 		return nil, nil
@@ -1479,7 +1479,7 @@ func (m mockSource) getResource(rawname string, info *tfbridge.DocInfo) (*DocFil
 		FileName: rawname + ".md",
 	}, nil
 }
-func (m mockSource) getDatasource(rawname string, info *tfbridge.DocInfo) (*DocFile, error) {
+func (m mockSource) GetDatasource(rawname string, info *tfbridge.DocInfo) (*DocFile, error) {
 	return nil, nil
 }
 
@@ -0,0 +1,101 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfgen/internal/testprovider"
+)
+
+func TestDumpShimCommand(t *testing.T) {
+	info := testprovider.ProviderMiniRandom()
+	out := filepath.Join(t.TempDir(), "shim.json")
+
+	cmd := newTFGenCmd(info.Name, info.Version, info, func(GeneratorOptions) error {
+		t.Fatal("dump-shim must not generate an SDK")
+		return nil
+	})
+	cmd.SetArgs([]string{"dump-shim", "--out", out})
+	require.NoError(t, cmd.Execute())
+
+	sink := diag.DefaultSink(io.Discard, io.Discard, diag.FormatOptions{Color: colors.Never})
+	expected, err := GenerateSchema(info, sink)
+	require.NoError(t, err)
+
+	// Generate the schema again without the compiled-in provider.
+	info.P = nil
+	g, err := NewGenerator(GeneratorOptions{
+		Package:      info.Name,
+		Version:      info.Version,
+		Language:     Schema,
+		ProviderInfo: info,
+		Root:         afero.NewMemMapFs(),
+		Sink:         sink,
+		ShimSnapshot: out,
+	})
+	require.NoError(t, err)
+	pack, err := g.gatherPackage()
+	require.NoError(t, err)
+	actual, err := genPulumiSchema(pack, g.pkg, g.version, g.info)
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestSubcommandsReadShimSnapshot(t *testing.T) {
+	info := testprovider.ProviderMiniRandom()
+	dir := t.TempDir()
+	noGenerate := func(GeneratorOptions) error {
+		t.Fatal("subcommands must not generate an SDK")
+		return nil
+	}
+	run := func(info tfbridge.ProviderInfo, args ...string) {
+		cmd := newTFGenCmd(info.Name, info.Version, info, noGenerate)
+		cmd.SetArgs(args)
+		require.NoError(t, cmd.Execute())
+	}
+
+	shim := filepath.Join(dir, "shim.json")
+	run(info, "dump-shim", "--out", shim)
+	expected := filepath.Join(dir, "expected.json")
+	run(info, "mapping", "--out", expected)
+
+	// Without the compiled-in provider, the subcommands read the schema from the snapshot.
+	info.P = nil
+	actual := filepath.Join(dir, "actual.json")
+	run(info, "mapping", "--shim-snapshot", shim, "--out", actual)
+	run(info, "lint", "--shim-snapshot", shim)
+	redumped := filepath.Join(dir, "redumped.json")
+	run(info, "dump-shim", "--shim-snapshot", shim, "--out", redumped)
+
+	for expected, actual := range map[string]string{expected: actual, shim: redumped} {
+		expectedBytes, err := os.ReadFile(expected)
+		require.NoError(t, err)
+		actualBytes, err := os.ReadFile(actual)
+		require.NoError(t, err)
+		assert.Equal(t, string(expectedBytes), string(actualBytes))
+	}
+}
@@ -16,8 +16,8 @@
 // when using the convert_cli.go method that interacts with an external process. Typically provider
 // builds first generate the schema, and then generate concrete language SDKs for Python, TypeScript
 // and so on; these processes start from scratch as they do not easily decompose into passes, and
-// naively they perform the conversion work multiple times. Using a file-based cache speeds up the
-// process by avoiding repeat conversion.
+// naively they perform the conversion work multiple times. Using a cache speeds up the process by avoiding repeat
+// conversion. The cache is stored by an ExamplesCache, which may be shared between machines.
 package tfgen
 
 import (
@@ -28,7 +28,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"path/filepath"
+	"path"
 	"strings"
 
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
@@ -41,7 +41,8 @@ const (
 
 type examplesCache struct {
 	enabled             bool
-	dir                 string
+	backend             ExamplesCache
+	warn                func(format string, args ...interface{})
 	ProviderName        string                       `json:"providerName"`
 	PulumiVersion       string                       `json:"pulumiVersion"`
 	SoftwareVersions    map[string]string            `json:"softwareVersions"`
@@ -52,39 +53,41 @@ type examplesCache struct {
 }
 
 func (g *Generator) getOrCreateExamplesCache() *examplesCache {
-	if g.examplesCache == nil {
-		g.examplesCache = newExamplesCache(&g.info, "" /* infer from env var */)
-	}
+	// Examples are converted concurrently with --parallel.
+	g.examplesCacheOnce.Do(func() {
+		g.examplesCache = newExamplesCache(&g.info, g.examplesCacheBackend /* infer from env var if nil */, g.warn)
+	})
 
 	return g.examplesCache
 }
 
-func newExamplesCache(info *tfbridge.ProviderInfo, cacheDir string) *examplesCache {
+func newExamplesCache(
+	info *tfbridge.ProviderInfo, backend ExamplesCache, warn func(format string, args ...interface{}),
+) *examplesCache {
 	providerName := info.Name
-	dir := cacheDir
-	enabled := true
-	if dir == "" {
-		dir, enabled = os.LookupEnv(pulumiConvertExamplesCacheDirEnvVar)
-		// If the provider author is debugging schema generation, example cache may get in the way of getting
-		// accurate results - there are some problems at the intersection of using Go workspaces and computing
-		// accurate keys. Disable it.
-		_, convertOnly := os.LookupEnv("PULUMI_CONVERT_ONLY")
-		if convertOnly {
-			enabled = false
-		}
-	}
-	if !enabled {
+	// If the provider author is debugging schema generation, example cache may get in the way of getting
+	// accurate results - there are some problems at the intersection of using Go workspaces and computing
+	// accurate keys. Disable it, including for backends configured by flags.
+	if _, convertOnly := os.LookupEnv("PULUMI_CONVERT_ONLY"); convertOnly {
 		return &examplesCache{}
 	}
-	contract.Assertf(dir != "", `Invalid %s=""`, pulumiConvertExamplesCacheDirEnvVar)
+	if backend == nil {
+		dir, enabled := os.LookupEnv(pulumiConvertExamplesCacheDirEnvVar)
+		if !enabled {
+			return &examplesCache{}
+		}
+		contract.Assertf(dir != "", `Invalid %s=""`, pulumiConvertExamplesCacheDirEnvVar)
+		backend = NewDirExamplesCache(dir)
+	}
 	ec := &examplesCache{
 		enabled:      true,
-		dir:          dir,
+		backend:      backend,
+		warn:         warn,
 		ProviderName: providerName,
 	}
 	ec.computeProviderInfoHash(info)
 	ec.inferToolingVersions()
-	ec.prepareDir()
+	ec.writeMetadata()
 	return ec
 }
 
@@ -92,13 +95,12 @@ func (ec *examplesCache) Lookup(originalHCL, language string) (string, bool) {
 	if !ec.enabled {
 		return "", false
 	}
-	k := ec.exampleKey(originalHCL, language)
-	p := filepath.Join(ec.fulldir(), k)
-	bytes, err := os.ReadFile(p)
+	bytes, ok, err := ec.backend.Get(ec.key(ec.exampleKey(originalHCL, language)))
 	if err != nil {
+		ec.warnf("failed to read from the examples cache: %v", err)
 		return "", false
 	}
-	return string(bytes), true
+	return string(bytes), ok
 }
 
 func (ec *examplesCache) Store(originalHCL, language, result string) {
@@ -109,10 +111,16 @@ func (ec *examplesCache) Store(originalHCL, language, result string) {
 	if strings.HasPrefix(result, "{convertExamples:") {
 		return
 	}
-	k := ec.exampleKey(originalHCL, language)
-	p := filepath.Join(ec.fulldir(), k)
-	err := os.WriteFile(p, []byte(result), 0600)
-	contract.AssertNoErrorf(err, "failed to write to the examples cache")
+	err := ec.backend.Put(ec.key(ec.exampleKey(originalHCL, language)), []byte(result))
+	if err != nil {
+		ec.warnf("failed to write to the examples cache: %v", err)
+	}
+}
+
+func (ec *examplesCache) warnf(format string, args ...interface{}) {
+	if ec.warn != nil {
+		ec.warn(format, args...)
+	}
 }
 
 func (*examplesCache) checksum(bytes []byte) string {
@@ -230,19 +238,18 @@ func (ec *examplesCache) uniqueDirHash() string {
 	return ec.checksum(bytes)
 }
 
-func (ec *examplesCache) fulldir() string {
-	return filepath.Join(ec.dir, ec.uniqueDirHash())
+// key returns the key of name in the backend. Keys are prefixed by a hash of the tooling versions, so that
+// translations computed with different tooling are never mixed up.
+func (ec *examplesCache) key(name string) string {
+	return path.Join(ec.uniqueDirHash(), name)
 }
 
-func (ec *examplesCache) prepareDir() {
-	d := ec.fulldir()
-	err := os.MkdirAll(d, 0755)
-	contract.AssertNoErrorf(err, "failed to prepare dir specified by %q",
-		pulumiConvertExamplesCacheDirEnvVar)
-
+// writeMetadata records the tooling versions next to the cached translations to help debugging cache misses.
+func (ec *examplesCache) writeMetadata() {
 	bytes, err := json.Marshal(ec)
 	contract.AssertNoErrorf(err, "examplesCache should marshal to JSON")
 
-	err = os.WriteFile(filepath.Join(d, "cache.json"), bytes, 0600)
-	contract.AssertNoErrorf(err, "failed to write cache.json")
+	if err := ec.backend.Put(ec.key("cache.json"), bytes); err != nil {
+		ec.warnf("failed to write to the examples cache: %v", err)
+	}
 }
@@ -0,0 +1,332 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/spf13/afero"
+)
+
+// ExamplesCache stores the translations of HCL examples so that they can be reused across tfgen runs.
+//
+// Keys are slash-separated relative paths. The first segment identifies the tooling versions and provider
+// configuration the translations were computed with, so a cache can safely be shared between builds.
+type ExamplesCache interface {
+	// Get returns the value stored under key. It returns false if there is none.
+	Get(key string) ([]byte, bool, error)
+
+	// Put stores value under key.
+	Put(key string, value []byte) error
+}
+
+// OpenExamplesCache opens the examples cache at location:
+//
+//   - http:// and https:// URLs select NewHTTPExamplesCache
+//   - paths ending in .tar.gz or .tgz select NewArchiveExamplesCache
+//   - any other path selects NewDirExamplesCache
+//
+// Close the returned cache if it implements io.Closer.
+func OpenExamplesCache(location string) (ExamplesCache, error) {
+	switch {
+	case strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://"):
+		return NewHTTPExamplesCache(location, nil), nil
+	case strings.HasSuffix(location, ".tar.gz") || strings.HasSuffix(location, ".tgz"):
+		return NewArchiveExamplesCache(location)
+	default:
+		return NewDirExamplesCache(location), nil
+	}
+}
+
+// NewDirExamplesCache returns an ExamplesCache storing every value in a file under dir.
+func NewDirExamplesCache(dir string) ExamplesCache {
+	return &dirExamplesCache{dir: dir}
+}
+
+type dirExamplesCache struct {
+	dir string
+}
+
+func (c *dirExamplesCache) Get(key string) ([]byte, bool, error) {
+	bytes, err := os.ReadFile(filepath.Join(c.dir, filepath.FromSlash(key)))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return bytes, true, nil
+}
+
+func (c *dirExamplesCache) Put(key string, value []byte) error {
+	p := filepath.Join(c.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(p, value, 0600)
+}
+
+// NewArchiveExamplesCache returns an ExamplesCache holding its values in memory. The values are loaded from the
+// .tar.gz archive at path if it exists, and written back to it by Close if any value was added.
+func NewArchiveExamplesCache(path string) (ExamplesCache, error) {
+	c := &archiveExamplesCache{path: path, entries: map[string][]byte{}}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return c, nil
+	}
+	if err := readExamplesArchive(path, func(key string, value []byte) error {
+		c.entries[key] = value
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+type archiveExamplesCache struct {
+	mu       sync.Mutex
+	path     string
+	entries  map[string][]byte
+	modified bool
+}
+
+func (c *archiveExamplesCache) Get(key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, ok := c.entries[key]
+	return value, ok, nil
+}
+
+func (c *archiveExamplesCache) Put(key string, value []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if old, ok := c.entries[key]; ok && bytes.Equal(old, value) {
+		return nil
+	}
+	c.entries[key] = value
+	c.modified = true
+	return nil
+}
+
+// Close writes the archive if any value was added since it was loaded. Caches without a path are kept in memory.
+func (c *archiveExamplesCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.modified || c.path == "" {
+		return nil
+	}
+	if err := writeExamplesArchive(c.path, c.entries); err != nil {
+		return err
+	}
+	c.modified = false
+	return nil
+}
+
+const httpExamplesCacheTimeout = 30 * time.Second
+
+// NewHTTPExamplesCache returns an ExamplesCache reading values with GET <baseURL>/<key> and writing them with PUT
+// <baseURL>/<key>. Missing values must be answered with 404 Not Found. Credentials may be passed as the user info
+// of baseURL or through the transport of client. A nil client defaults to one that gives up on requests after
+// httpExamplesCacheTimeout, so an unresponsive server cannot stall schema generation.
+func NewHTTPExamplesCache(baseURL string, client *http.Client) ExamplesCache {
+	if client == nil {
+		client = &http.Client{Timeout: httpExamplesCacheTimeout}
+	}
+	return &httpExamplesCache{baseURL: strings.TrimSuffix(baseURL, "/"), client: client}
+}
+
+type httpExamplesCache struct {
+	baseURL string
+	client  *http.Client
+}
+
+func (c *httpExamplesCache) Get(key string) ([]byte, bool, error) {
+	resp, err := c.client.Get(c.url(key))
+	if err != nil {
+		return nil, false, err
+	}
+	defer contract.IgnoreClose(resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		bytes, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, false, err
+		}
+		return bytes, true, nil
+	case http.StatusNotFound:
+		return nil, false, nil
+	default:
+		return nil, false, fmt.Errorf("GET %s: %s", c.url(key), resp.Status)
+	}
+}
+
+func (c *httpExamplesCache) Put(key string, value []byte) error {
+	req, err := http.NewRequest(http.MethodPut, c.url(key), bytes.NewReader(value))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer contract.IgnoreClose(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("PUT %s: %s", c.url(key), resp.Status)
+	}
+	return nil
+}
+
+func (c *httpExamplesCache) url(key string) string {
+	return c.baseURL + "/" + key
+}
+
+// ImportExamplesCacheArchive copies every value of the .tar.gz archive at path into cache, for example to pre-warm
+// a local cache with translations exported by another build. It returns the number of copied values.
+func ImportExamplesCacheArchive(cache ExamplesCache, path string) (int, error) {
+	count := 0
+	err := readExamplesArchive(path, func(key string, value []byte) error {
+		count++
+		return cache.Put(key, value)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("importing examples cache archive %s: %w", path, err)
+	}
+	return count, nil
+}
+
+// recordingExamplesCache remembers the keys found in or added to the wrapped cache, so that the translations used
+// by a tfgen run can be exported.
+type recordingExamplesCache struct {
+	ExamplesCache
+
+	mu   sync.Mutex
+	used map[string]struct{}
+}
+
+func newRecordingExamplesCache(cache ExamplesCache) *recordingExamplesCache {
+	return &recordingExamplesCache{ExamplesCache: cache, used: map[string]struct{}{}}
+}
+
+func (c *recordingExamplesCache) Get(key string) ([]byte, bool, error) {
+	value, ok, err := c.ExamplesCache.Get(key)
+	if ok {
+		c.record(key)
+	}
+	return value, ok, err
+}
+
+func (c *recordingExamplesCache) Put(key string, value []byte) error {
+	if err := c.ExamplesCache.Put(key, value); err != nil {
+		return err
+	}
+	c.record(key)
+	return nil
+}
+
+func (c *recordingExamplesCache) record(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.used[key] = struct{}{}
+}
+
+// Close closes the wrapped cache if it implements io.Closer.
+func (c *recordingExamplesCache) Close() error {
+	if closer, ok := c.ExamplesCache.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// export writes the used values to the .tar.gz archive at path.
+func (c *recordingExamplesCache) export(path string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entries := map[string][]byte{}
+	for key := range c.used {
+		value, ok, err := c.ExamplesCache.Get(key)
+		if err != nil {
+			return err
+		}
+		if ok {
+			entries[key] = value
+		}
+	}
+	return writeExamplesArchive(path, entries)
+}
+
+func readExamplesArchive(path string, visit func(key string, value []byte) error) error {
+	archive, err := extractTarball(path)
+	if err != nil {
+		return err
+	}
+	return afero.Walk(archive, "/", func(p string, info fs.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		value, err := afero.ReadFile(archive, p)
+		if err != nil {
+			return err
+		}
+		return visit(strings.TrimPrefix(filepath.ToSlash(p), "/"), value)
+	})
+}
+
+func writeExamplesArchive(archivePath string, entries map[string][]byte) error {
+	keys := make([]string, 0, len(entries))
+	for k := range entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, k := range keys {
+		hdr := &tar.Header{
+			Name:     path.Clean(k),
+			Typeflag: tar.TypeReg,
+			Mode:     0600,
+			Size:     int64(len(entries[k])),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(entries[k]); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(archivePath, buf.Bytes(), 0600)
+}
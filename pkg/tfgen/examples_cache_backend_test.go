@@ -0,0 +1,161 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExamplesCacheBackends(t *testing.T) {
+	t.Parallel()
+
+	backends := map[string]func(t *testing.T) ExamplesCache{
+		"dir": func(t *testing.T) ExamplesCache {
+			return NewDirExamplesCache(t.TempDir())
+		},
+		"archive": func(t *testing.T) ExamplesCache {
+			c, err := NewArchiveExamplesCache(filepath.Join(t.TempDir(), "cache.tar.gz"))
+			require.NoError(t, err)
+			return c
+		},
+		"http": func(t *testing.T) ExamplesCache {
+			server := httptest.NewServer(newTestCacheServer())
+			t.Cleanup(server.Close)
+			return NewHTTPExamplesCache(server.URL+"/cache/", server.Client())
+		},
+	}
+
+	for name, newCache := range backends {
+		newCache := newCache
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c := newCache(t)
+
+			_, ok, err := c.Get("hash/key")
+			require.NoError(t, err)
+			assert.False(t, ok)
+
+			require.NoError(t, c.Put("hash/key", []byte("translated")))
+
+			value, ok, err := c.Get("hash/key")
+			require.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, "translated", string(value))
+		})
+	}
+}
+
+func TestArchiveExamplesCachePersists(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "cache.tgz")
+
+	c, err := OpenExamplesCache(path)
+	require.NoError(t, err)
+	require.NoError(t, c.Put("hash/key", []byte("translated")))
+	require.NoError(t, c.(io.Closer).Close())
+
+	c, err = OpenExamplesCache(path)
+	require.NoError(t, err)
+	value, ok, err := c.Get("hash/key")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "translated", string(value))
+}
+
+func TestHTTPExamplesCacheDefaultTimeout(t *testing.T) {
+	t.Parallel()
+	c := NewHTTPExamplesCache("http://localhost", nil).(*httpExamplesCache)
+	assert.Equal(t, httpExamplesCacheTimeout, c.client.Timeout)
+}
+
+func TestHTTPExamplesCacheErrors(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	c := NewHTTPExamplesCache(server.URL, server.Client())
+	_, _, err := c.Get("hash/key")
+	assert.ErrorContains(t, err, "403 Forbidden")
+	err = c.Put("hash/key", []byte("translated"))
+	assert.ErrorContains(t, err, "403 Forbidden")
+}
+
+func TestExamplesCacheImportExport(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	source := newRecordingExamplesCache(NewDirExamplesCache(filepath.Join(dir, "source")))
+	require.NoError(t, source.Put("hash/used", []byte("a")))
+	require.NoError(t, source.ExamplesCache.Put("hash/unused", []byte("b")))
+	archive := filepath.Join(dir, "export.tar.gz")
+	require.NoError(t, closeExamplesCache(source, archive))
+
+	target := NewDirExamplesCache(filepath.Join(dir, "target"))
+	n, err := ImportExamplesCacheArchive(target, archive)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	value, ok, err := target.Get("hash/used")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "a", string(value))
+
+	_, ok, err = target.Get("hash/unused")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+// newTestCacheServer returns an in-memory HTTP server implementing GET and PUT.
+func newTestCacheServer() http.Handler {
+	var mu sync.Mutex
+	entries := map[string][]byte{}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		key := strings.TrimPrefix(r.URL.Path, "/cache/")
+		switch r.Method {
+		case http.MethodGet:
+			value, ok := entries[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, err := w.Write(value)
+			contract.IgnoreError(err)
+		case http.MethodPut:
+			value, err := io.ReadAll(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			entries[key] = value
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+}
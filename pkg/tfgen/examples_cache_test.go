@@ -52,7 +52,7 @@ func TestExamplesCache(t *testing.T) {
 
 	t.Run("enabled", func(t *testing.T) {
 		dir := t.TempDir()
-		cache := newExamplesCache(exInfo, dir)
+		cache := newExamplesCache(exInfo, NewDirExamplesCache(dir), t.Logf)
 
 		_, ok := cache.Lookup(hcl, "typescript")
 		assert.False(t, ok)
@@ -65,7 +65,7 @@ func TestExamplesCache(t *testing.T) {
 	})
 
 	t.Run("disabled", func(t *testing.T) {
-		cache := newExamplesCache(exInfo, "")
+		cache := newExamplesCache(exInfo, nil, t.Logf)
 		assert.False(t, cache.enabled)
 
 		_, ok := cache.Lookup(hcl, "typescript")
@@ -77,3 +77,10 @@ func TestExamplesCache(t *testing.T) {
 		assert.False(t, ok)
 	})
 }
+
+func TestExamplesCacheConvertOnly(t *testing.T) {
+	t.Setenv("PULUMI_CONVERT_ONLY", "true")
+	exInfo := &tfbridge.ProviderInfo{Name: "ex", Version: "0.0.1"}
+	cache := newExamplesCache(exInfo, NewDirExamplesCache(t.TempDir()), t.Logf)
+	assert.False(t, cache.enabled)
+}
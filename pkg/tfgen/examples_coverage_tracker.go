@@ -29,6 +29,7 @@ package tfgen
 
 import (
 	"strings"
+	"sync"
 
 	"github.com/hashicorp/hcl/v2"
 )
@@ -54,6 +55,8 @@ type CoverageTracker struct {
 	ProviderName     string                        // Name of the provider
 	ProviderVersion  string                        // Version of the provider
 	EncounteredPages map[string]*DocumentationPage // Map linking page IDs to their data
+
+	mu sync.Mutex // Guards notifications, which are sent concurrently when examples are converted in parallel
 }
 
 // A structure encompassing a single page, which contains one or more examples.
@@ -90,7 +93,11 @@ const (
 )
 
 func newCoverageTracker(ProviderName string, ProviderVersion string) *CoverageTracker {
-	return &CoverageTracker{ProviderName, ProviderVersion, make(map[string]*DocumentationPage)}
+	return &CoverageTracker{
+		ProviderName:     ProviderName,
+		ProviderVersion:  ProviderVersion,
+		EncounteredPages: make(map[string]*DocumentationPage),
+	}
 }
 
 // Find example by pageName and raw HCL source.
@@ -116,6 +123,8 @@ func (ct *CoverageTracker) getOrCreateExample(pageName string, hcl string) *Exam
 	if ct == nil {
 		return nil
 	}
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
 	// If the example with this HCL already exists, return it right away.
 	if e := ct.getExample(pageName, hcl); e != nil {
 		return e
@@ -199,6 +208,8 @@ func (ct *CoverageTracker) languageConversionPanic(
 func (ct *CoverageTracker) insertLanguageConversionResult(
 	e *Example, languageName string, newConversionResult LanguageConversionResult,
 ) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
 	if existingConversionResult, ok := e.ConversionResults[languageName]; ok {
 		// Example already has this language conversion attempt. Replace if new one has a
 		// lower severity
@@ -23,11 +23,13 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"unicode"
 	"unicode/utf8"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
+	javagen "github.com/pulumi/pulumi-java/pkg/codegen/java"
 	"github.com/pulumi/pulumi/pkg/v3/codegen"
 	dotnetgen "github.com/pulumi/pulumi/pkg/v3/codegen/dotnet"
 	gogen "github.com/pulumi/pulumi/pkg/v3/codegen/go"
@@ -48,7 +50,6 @@ import (
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/metadata"
-	schemaTools "github.com/pulumi/schema-tools/pkg"
 )
 
 const (
@@ -66,6 +67,7 @@ type Generator struct {
 	providerShim     *inmemoryProvider     // a provider shim to hold the provider schema during example conversion.
 	pluginHost       plugin.Host           // the plugin host for tf2pulumi.
 	packageCache     *pcl.PackageCache     // the package cache for tf2pulumi.
+	convertMu        sync.Mutex            // serializes tf2pulumi, which shares pluginHost and packageCache.
 	infoSource       il.ProviderInfoSource // the provider info source for tf2pulumi.
 	terraformVersion string                // the Terraform version to target for example codegen, if any
 	sink             diag.Sink
@@ -73,16 +75,26 @@ type Generator struct {
 	skipExamples     bool
 	coverageTracker  *CoverageTracker
 	editRules        editRules
+	docsSource       DocsSource // the source of upstream docs, NewGitRepoDocsSource if nil.
+	docsSourceOnce   sync.Once
 
 	convertedCode map[string][]byte
 
-	// Set if we can't find the docs repo and we have already printed a warning
-	// message.
-	noDocsRepo bool
+	// Warns once if we can't find the docs repo.
+	noDocsRepoWarning sync.Once
 
 	cliConverterState *cliConverter
 
-	examplesCache *examplesCache
+	examplesCacheOnce    sync.Once
+	examplesCache        *examplesCache
+	examplesCacheBackend ExamplesCache // the examples cache storage, PULUMI_CONVERT_EXAMPLES_CACHE_DIR if nil.
+
+	manifest *manifest // the manifest of incremental generation, if enabled.
+
+	docsMetrics *docsMetrics
+	docsReport  string // the path of the docs report, if enabled.
+
+	parallel int // the number of resources and data sources processed concurrently.
 }
 
 type Language string
@@ -92,13 +104,14 @@ const (
 	NodeJS Language = "nodejs"
 	Python Language = "python"
 	CSharp Language = "dotnet"
+	Java   Language = "java"
 	Schema Language = "schema"
 	PCL    Language = "pulumi"
 )
 
 func (l Language) shouldConvertExamples() bool {
 	switch l {
-	case Golang, NodeJS, Python, CSharp, Schema, PCL:
+	case Golang, NodeJS, Python, CSharp, Java, Schema, PCL:
 		return true
 	}
 	return false
@@ -182,12 +195,25 @@ func (l Language) emitSDK(pkg *pschema.Package, info tfbridge.ProviderInfo, root
 			return nil, err
 		}
 		return dotnetgen.GeneratePackage(tfgen, pkg, extraFiles)
+	case Java:
+		if psi := info.Java; psi != nil && psi.Overlay != nil {
+			extraFiles, err = getOverlayFiles(psi.Overlay, ".java", root)
+			if err != nil {
+				return nil, err
+			}
+		}
+		// Only clean the sources: hand-written Gradle files next to them must survive when BuildFiles is unset.
+		err = cleanDir(root, "src", nil)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		return javagen.GeneratePackage(tfgen, pkg, extraFiles)
 	default:
 		return nil, errors.Errorf("%v does not support SDK generation", l)
 	}
 }
 
-var AllLanguages = []Language{Golang, NodeJS, Python, CSharp}
+var AllLanguages = []Language{Golang, NodeJS, Python, CSharp, Java}
 
 // pkg is a directory containing one or more modules.
 type pkg struct {
@@ -759,6 +785,35 @@ type GeneratorOptions struct {
 	SkipDocs           bool
 	SkipExamples       bool
 	CoverageTracker    *CoverageTracker
+
+	// DocsSource provides the upstream docs. It defaults to the upstream repository, see NewGitRepoDocsSource.
+	DocsSource DocsSource
+
+	// ExamplesCache stores the translated examples across runs. It defaults to the directory named by the
+	// PULUMI_CONVERT_EXAMPLES_CACHE_DIR environment variable, if set.
+	ExamplesCache ExamplesCache
+
+	// Manifest is the path of the manifest of incremental generation. When set, the parsed docs and converted
+	// examples of the resources and data sources which did not change since the previous run are reused from it.
+	Manifest string
+
+	// Full ignores the entries of Manifest, regenerating every resource and data source.
+	Full bool
+
+	// DocsReport is the path of a JSON report of the documentation quality of each resource and function: missing
+	// descriptions, descriptions taken from attributes, elided arguments and dropped examples.
+	DocsReport string
+
+	// Parallel is the number of resources and data sources whose docs are parsed and examples converted
+	// concurrently. The generated schema is identical to a serial run. Values below 2 process them one at a time.
+	Parallel int
+
+	// ShimSnapshot is the path of a provider schema snapshot written by `tfgen dump-shim`. When set, it replaces
+	// ProviderInfo.P, so that the upstream provider does not need to be compiled in.
+	ShimSnapshot string
+
+	// docsMetrics collects the docs metrics printed by the tfgen command. A fresh one is used if nil.
+	docsMetrics *docsMetrics
 }
 
 // NewGenerator returns a code-generator for the given language runtime and package info.
@@ -769,12 +824,30 @@ func NewGenerator(opts GeneratorOptions) (*Generator, error) {
 
 	// Ensure the language is valid.
 	switch lang {
-	case Golang, NodeJS, Python, CSharp, Schema, PCL:
+	case Golang, NodeJS, Python, CSharp, Java, Schema, PCL:
 		// OK
 	default:
 		return nil, errors.Errorf("unrecognized language runtime: %s", lang)
 	}
 
+	docsMetrics := opts.docsMetrics
+	if docsMetrics == nil {
+		docsMetrics = newDocsMetrics()
+	}
+
+	info, err := withShimSnapshot(info, opts.ShimSnapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	var m *manifest
+	// PCL generation records the converted examples themselves, so they cannot be reused.
+	if opts.Manifest != "" && lang != PCL {
+		if m, err = newManifest(opts.Manifest, opts.Full, &info, lang); err != nil {
+			return nil, err
+		}
+	}
+
 	// If root is nil, default to sdk/<language>/ in the pwd.
 	if root == nil {
 		p, err := os.Getwd()
@@ -838,6 +911,13 @@ func NewGenerator(opts GeneratorOptions) (*Generator, error) {
 		skipExamples:     opts.SkipExamples,
 		coverageTracker:  opts.CoverageTracker,
 		editRules:        getEditRules(info.DocRules),
+		docsSource:       opts.DocsSource,
+
+		examplesCacheBackend: opts.ExamplesCache,
+		manifest:             m,
+		docsMetrics:          docsMetrics,
+		docsReport:           opts.DocsReport,
+		parallel:             opts.Parallel,
 	}, nil
 }
 
@@ -857,6 +937,27 @@ func (g *Generator) provider() shim.Provider {
 	return g.info.P
 }
 
+// getEntityDocs returns the docs of a resource or data source, reusing the docs of the previous run if the
+// manifest of incremental generation is enabled.
+func (g *Generator) getEntityDocs(kind DocKind, rawname string, res shim.Resource,
+	info tfbridge.ResourceOrDataSourceInfo) (entityDocs, error) {
+
+	if g.manifest == nil {
+		return getDocsForResource(g, g.getDocsSource(), kind, rawname, info)
+	}
+	return g.manifest.getDocs(g, kind, rawname, res, info)
+}
+
+func (g *Generator) getDocsSource() DocsSource {
+	// Resources are gathered concurrently with --parallel.
+	g.docsSourceOnce.Do(func() {
+		if g.docsSource == nil {
+			g.docsSource = NewGitRepoDocsSource(g)
+		}
+	})
+	return g.docsSource
+}
+
 type GenerateOptions struct {
 	ModuleFormat string
 }
@@ -901,7 +1002,7 @@ func (g *Generator) Generate() error {
 // API.
 func (g *Generator) UnstableGenerateFromSchema(genSchemaResult *GenerateSchemaResult) error {
 	pulumiPackageSpec := genSchemaResult.PackageSpec
-	schemaStats = schemaTools.CountStats(pulumiPackageSpec)
+	g.docsMetrics.recordSchema(pulumiPackageSpec)
 
 	// Serialize the schema and attach it to the provider shim.
 	var err error
@@ -926,6 +1027,12 @@ func (g *Generator) UnstableGenerateFromSchema(genSchemaResult *GenerateSchemaRe
 		pulumiPackageSpec = g.convertExamplesInSchema(pulumiPackageSpec)
 	}
 
+	if g.docsReport != "" {
+		if err := g.docsMetrics.writeReport(g.docsReport, pulumiPackageSpec); err != nil {
+			return errors.Wrapf(err, "failed to write the docs report")
+		}
+	}
+
 	// Go ahead and let the language generator do its thing. If we're emitting the schema, just go ahead and serialize
 	// it out.
 	var files map[string][]byte
@@ -986,6 +1093,15 @@ func (g *Generator) UnstableGenerateFromSchema(genSchemaResult *GenerateSchemaRe
 		return errors.Wrapf(err, "failed to create project file")
 	}
 
+	// Only record the manifest once generation has succeeded, so that a failed run is not reused by the next one.
+	if g.manifest != nil {
+		if err := g.manifest.write(pulumiPackageSpec, g.docsMetrics); err != nil {
+			return errors.Wrapf(err, "failed to write the tfgen manifest")
+		}
+		g.sink.Infof(diag.Message("", "Reused the docs of %d entities and the examples of %d resources and "+
+			"functions from %s"), g.manifest.reusedDocs, g.manifest.reusedSpecs, g.manifest.path)
+	}
+
 	// Close the plugin host.
 	g.pluginHost.Close()
 
@@ -1133,7 +1249,7 @@ func (g *Generator) gatherResources() (moduleMap, error) {
 	var resourceMappingErrors error
 
 	// For each resource, create its own dedicated type and module export.
-	var reserr error
+	var pending []string
 	seen := make(map[string]bool)
 	for _, r := range stableResources(resources) {
 		info := g.info.Resources[r]
@@ -1152,11 +1268,24 @@ func (g *Generator) gatherResources() (moduleMap, error) {
 			continue
 		}
 		seen[r] = true
+		pending = append(pending, r)
+	}
 
-		res, err := g.gatherResource(r, resources.Get(r), info, false)
-		if err != nil {
+	// Gather the resources on up to g.parallel workers, then add them in order, as a serial run would.
+	schemas := make([]shim.Resource, len(pending))
+	for i, r := range pending {
+		schemas[i] = resources.Get(r)
+	}
+	gathered := make([]*resourceType, len(pending))
+	errs := make([]error, len(pending))
+	forEachParallel(g.parallel, len(pending), func(i int) {
+		gathered[i], errs[i] = g.gatherResource(pending[i], schemas[i], g.info.Resources[pending[i]], false)
+	})
+	var reserr error
+	for i, res := range gathered {
+		if errs[i] != nil {
 			// Keep track of the error, but keep going, so we can expose more at once.
-			reserr = multierror.Append(reserr, err)
+			reserr = multierror.Append(reserr, errs[i])
 		} else {
 			// Add any members returned to the specified module.
 			modules.ensureModule(res.mod).addMember(res)
@@ -1194,6 +1323,37 @@ func (g *Generator) gatherResources() (moduleMap, error) {
 	return modules, nil
 }
 
+// forEachParallel calls f with each index in [0, n) on up to workers goroutines. Callers store results by index to
+// keep them in the order of a serial run.
+func forEachParallel(workers, n int, f func(i int)) {
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			f(i)
+		}
+		return
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				f(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
 // gatherResource returns the module name and one or more module members to represent the given resource.
 func (g *Generator) gatherResource(rawname string,
 	schema shim.Resource, info *tfbridge.ResourceInfo, isProvider bool) (*resourceType, error) {
@@ -1208,13 +1368,13 @@ func (g *Generator) gatherResource(rawname string,
 	// Collect documentation information
 	var entityDocs entityDocs
 	if !isProvider {
-		source := NewGitRepoDocsSource(g)
-		pulumiDocs, err := getDocsForResource(g, source, ResourceDocs, rawname, info)
+		pulumiDocs, err := g.getEntityDocs(ResourceDocs, rawname, schema, info)
 		if err == nil {
 			entityDocs = pulumiDocs
 		} else if !g.checkNoDocsError(err) {
 			return nil, err
 		}
+		g.docsMetrics.recordEntityDocs(resourceToken.String(), "resource", rawname, entityDocs)
 	} else {
 		entityDocs.Description = fmt.Sprintf(
 			"The provider type for the %s package. By default, resources use package-wide configuration\n"+
@@ -1255,7 +1415,7 @@ func (g *Generator) gatherResource(rawname string,
 		// If an input, generate the input property metadata.
 		if input(propschema, propinfo) {
 			if foundInAttributes && !isProvider {
-				argumentDescriptionsFromAttributes++
+				g.docsMetrics.recordDescriptionFromAttributes(resourceToken.String(), key)
 				msg := fmt.Sprintf("Argument desc from attributes: resource, rawname = '%s', property = '%s'", rawname, key)
 				g.debug(msg)
 			}
@@ -1330,7 +1490,7 @@ func (g *Generator) gatherDataSources() (moduleMap, error) {
 	var dataSourceMappingErrors error
 
 	// For each data source, create its own dedicated function and module export.
-	var dserr error
+	var pending []string
 	seen := make(map[string]bool)
 	for _, ds := range stableResources(sources) {
 		dsinfo := g.info.DataSources[ds]
@@ -1349,11 +1509,24 @@ func (g *Generator) gatherDataSources() (moduleMap, error) {
 			continue
 		}
 		seen[ds] = true
+		pending = append(pending, ds)
+	}
 
-		fun, err := g.gatherDataSource(ds, sources.Get(ds), dsinfo)
-		if err != nil {
+	// Gather the data sources on up to g.parallel workers, then add them in order, as a serial run would.
+	schemas := make([]shim.Resource, len(pending))
+	for i, ds := range pending {
+		schemas[i] = sources.Get(ds)
+	}
+	gathered := make([]*resourceFunc, len(pending))
+	errs := make([]error, len(pending))
+	forEachParallel(g.parallel, len(pending), func(i int) {
+		gathered[i], errs[i] = g.gatherDataSource(pending[i], schemas[i], g.info.DataSources[pending[i]])
+	})
+	var dserr error
+	for i, fun := range gathered {
+		if errs[i] != nil {
 			// Keep track of the error, but keep going, so we can expose more at once.
-			dserr = multierror.Append(dserr, err)
+			dserr = multierror.Append(dserr, errs[i])
 		} else {
 			// Add any members returned to the specified module.
 			modules.ensureModule(fun.mod).addMember(fun)
@@ -1399,14 +1572,15 @@ func (g *Generator) gatherDataSource(rawname string,
 	// Generate the name and module for this data source.
 	name, moduleName := dataSourceName(g.info.Name, rawname, info)
 	mod := tokens.NewModuleToken(g.pkg, moduleName)
-	dataSourcePath := paths.NewDataSourcePath(rawname, tokens.NewModuleMemberToken(mod, name))
+	token := tokens.NewModuleMemberToken(mod, name)
+	dataSourcePath := paths.NewDataSourcePath(rawname, token)
 
 	// Collect documentation information for this data source.
-	source := NewGitRepoDocsSource(g)
-	entityDocs, err := getDocsForResource(g, source, DataSourceDocs, rawname, info)
+	entityDocs, err := g.getEntityDocs(DataSourceDocs, rawname, ds, info)
 	if err != nil && !g.checkNoDocsError(err) {
 		return nil, err
 	}
+	g.docsMetrics.recordEntityDocs(token.String(), "function", rawname, entityDocs)
 
 	// Build up the function information.
 	fun := &resourceFunc{
@@ -1432,7 +1606,7 @@ func (g *Generator) gatherDataSource(rawname string,
 		if input(sch, cust) {
 			doc, foundInAttributes := getDescriptionFromParsedDocs(entityDocs, arg)
 			if foundInAttributes {
-				argumentDescriptionsFromAttributes++
+				g.docsMetrics.recordDescriptionFromAttributes(token.String(), arg)
 				msg := fmt.Sprintf("Argument desc taken from attributes: data source, rawname = '%s', property = '%s'",
 					rawname, arg)
 				g.debug(msg)
@@ -1514,6 +1688,10 @@ func (g *Generator) gatherOverlays() (moduleMap, error) {
 		if csharpinfo := g.info.CSharp; csharpinfo != nil {
 			overlay = csharpinfo.Overlay
 		}
+	case Java:
+		if javainfo := g.info.Java; javainfo != nil {
+			overlay = javainfo.Overlay
+		}
 	case Schema, PCL:
 		// N/A
 	default:
@@ -1553,10 +1731,7 @@ func (g *Generator) checkNoDocsError(err error) bool {
 	}
 
 	// If we have already warned, we can just discard the message
-	if !g.noDocsRepo {
-		g.logMissingRepoPath(e)
-	}
-	g.noDocsRepo = true
+	g.noDocsRepoWarning.Do(func() { g.logMissingRepoPath(e) })
 	return true
 }
 
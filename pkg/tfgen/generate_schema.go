@@ -294,6 +294,9 @@ func (g *schemaGenerator) genPackageSpec(pack *pkg) (pschema.PackageSpec, error)
 			switch t := member.(type) {
 			case *resourceType:
 				spec.Resources[string(t.info.Tok)] = g.genResourceType(mod.name, t)
+				if err := genResourceMethods(&spec, t.info); err != nil {
+					return pschema.PackageSpec{}, err
+				}
 			case *resourceFunc:
 				spec.Functions[string(t.info.Tok)] = g.genDatasourceFunc(mod.name, t)
 			case *variable:
@@ -347,6 +350,18 @@ func (g *schemaGenerator) genPackageSpec(pack *pkg) (pschema.PackageSpec, error)
 		spec.Resources[token] = res
 	}
 
+	for token, component := range g.info.Components {
+		if _, defined := spec.Resources[token]; defined {
+			return pschema.PackageSpec{}, fmt.Errorf("failed to define component: %v is already defined", token)
+		}
+		if component == nil || component.Construct == nil {
+			return pschema.PackageSpec{}, fmt.Errorf("failed to define component %v: Construct is required", token)
+		}
+		res := component.Schema
+		res.IsComponent = true
+		spec.Resources[token] = res
+	}
+
 	for token, fun := range g.info.ExtraFunctions {
 		if _, defined := spec.Functions[token]; defined {
 			return pschema.PackageSpec{}, fmt.Errorf("failed to define extra functions: %v is already defined", token)
@@ -759,6 +774,45 @@ func (g *schemaGenerator) genResourceType(mod tokens.Module, res *resourceType)
 	return spec
 }
 
+// genResourceMethods emits the methods of a resource as functions and links them from the resource.
+func genResourceMethods(spec *pschema.PackageSpec, info *tfbridge.ResourceInfo) error {
+	if info == nil || len(info.Methods) == 0 {
+		return nil
+	}
+	tok := string(info.Tok)
+	res := spec.Resources[tok]
+	res.Methods = map[string]string{}
+	for name, method := range info.Methods {
+		if method == nil || method.Call == nil {
+			return fmt.Errorf("failed to define method %s of %s: Call is required", name, tok)
+		}
+		funcTok := string(tfbridge.MethodToken(info.Tok, name))
+		if _, defined := spec.Functions[funcTok]; defined {
+			return fmt.Errorf("failed to define method %s of %s: %v is already defined", name, tok, funcTok)
+		}
+
+		fun := method.Schema
+		inputs := pschema.ObjectTypeSpec{Type: "object"}
+		if fun.Inputs != nil {
+			inputs = *fun.Inputs
+		}
+		properties := map[string]pschema.PropertySpec{
+			tfbridge.SelfArg: {TypeSpec: pschema.TypeSpec{Ref: "#/resources/" + tok}},
+		}
+		for k, v := range inputs.Properties {
+			properties[k] = v
+		}
+		inputs.Properties = properties
+		inputs.Required = append([]string{tfbridge.SelfArg}, inputs.Required...)
+		fun.Inputs = &inputs
+
+		spec.Functions[funcTok] = fun
+		res.Methods[name] = funcTok
+	}
+	spec.Resources[tok] = res
+	return nil
+}
+
 func (g *schemaGenerator) genDatasourceFunc(mod tokens.Module, fun *resourceFunc) pschema.FunctionSpec {
 	var spec pschema.FunctionSpec
 
@@ -1007,19 +1061,61 @@ func (g *Generator) convertExamplesInSchema(spec pschema.PackageSpec) pschema.Pa
 		path := newExamplePathForProviderConfigVariable(name)
 		spec.Config.Variables[name] = g.convertExamplesInPropertySpec(path, variable)
 	}
-	for token, object := range spec.Types {
-		path := newExamplePathForNamedType(token)
+
+	// Convert the types, resources and functions on up to g.parallel workers. The cliConverter records the examples
+	// for FinishConvertingExamples, which converts them in bulk, so it is called serially.
+	workers := g.parallel
+	if cliConverterEnabled() {
+		workers = 1
+	}
+
+	typeTokens := sortedKeys(spec.Types)
+	types := make([]pschema.ComplexTypeSpec, len(typeTokens))
+	forEachParallel(workers, len(typeTokens), func(i int) {
+		object := spec.Types[typeTokens[i]]
+		path := newExamplePathForNamedType(typeTokens[i])
 		object.ObjectTypeSpec = g.convertExamplesInObjectSpec(path, object.ObjectTypeSpec)
-		spec.Types[token] = object
+		types[i] = object
+	})
+	for i, token := range typeTokens {
+		spec.Types[token] = types[i]
 	}
 	spec.Provider = g.convertExamplesInResourceSpec(newExamplePathForProvider(), spec.Provider)
-	for token, resource := range spec.Resources {
+
+	resourceTokens := sortedKeys(spec.Resources)
+	resources := make([]pschema.ResourceSpec, len(resourceTokens))
+	forEachParallel(workers, len(resourceTokens), func(i int) {
+		token, resource := resourceTokens[i], spec.Resources[resourceTokens[i]]
+		if g.manifest != nil {
+			if converted, findings, ok := g.manifest.convertedResource(token, resource); ok {
+				resources[i] = converted
+				g.docsMetrics.recordExampleFindings(token, findings)
+				return
+			}
+		}
 		path := newExamplePathForResource(token)
-		spec.Resources[token] = g.convertExamplesInResourceSpec(path, resource)
-	}
-	for token, function := range spec.Functions {
+		resources[i] = g.convertExamplesInResourceSpec(path, resource)
+	})
+	for i, token := range resourceTokens {
+		spec.Resources[token] = resources[i]
+	}
+
+	functionTokens := sortedKeys(spec.Functions)
+	functions := make([]pschema.FunctionSpec, len(functionTokens))
+	forEachParallel(workers, len(functionTokens), func(i int) {
+		token, function := functionTokens[i], spec.Functions[functionTokens[i]]
+		if g.manifest != nil {
+			if converted, findings, ok := g.manifest.convertedFunction(token, function); ok {
+				functions[i] = converted
+				g.docsMetrics.recordExampleFindings(token, findings)
+				return
+			}
+		}
 		path := newExamplePathForFunction(token)
-		spec.Functions[token] = g.convertExamplesInFunctionSpec(path, function)
+		functions[i] = g.convertExamplesInFunctionSpec(path, function)
+	})
+	for i, token := range functionTokens {
+		spec.Functions[token] = functions[i]
 	}
 
 	if cliConverterEnabled() {
@@ -16,6 +16,7 @@ package tfgen
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"testing"
@@ -33,8 +34,12 @@ import (
 	gogen "github.com/pulumi/pulumi/pkg/v3/codegen/go"
 	tsgen "github.com/pulumi/pulumi/pkg/v3/codegen/nodejs"
 	pygen "github.com/pulumi/pulumi/pkg/v3/codegen/python"
+	pschema "github.com/pulumi/pulumi/pkg/v3/codegen/schema"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	pprovider "github.com/pulumi/pulumi/sdk/v3/go/pulumi/provider"
 )
 
 // TestRegress611 tests against test_data/regress-611-schema.json.
@@ -472,3 +477,82 @@ func TestRegress1626(t *testing.T) {
 	t.Logf("SPEC: %v", s)
 	require.NoError(t, err)
 }
+
+func TestComponents(t *testing.T) {
+	construct := tfbridge.ConstructFunc(func(*pulumi.Context, string, string, pprovider.ConstructInputs,
+		pulumi.ResourceOption) (*pprovider.ConstructResult, error) {
+		return nil, nil
+	})
+	sink := diag.DefaultSink(io.Discard, io.Discard, diag.FormatOptions{Color: colors.Never})
+
+	t.Run("emitted as component", func(t *testing.T) {
+		provider := testprovider.ProviderMiniRandom()
+		provider.Components = map[string]*tfbridge.ComponentInfo{
+			"random:index:Network": {
+				Schema: pschema.ResourceSpec{
+					InputProperties: map[string]pschema.PropertySpec{
+						"cidr": {TypeSpec: pschema.TypeSpec{Type: "string"}},
+					},
+				},
+				Construct: construct,
+			},
+		}
+		spec, err := GenerateSchema(provider, sink)
+		require.NoError(t, err)
+		res, ok := spec.Resources["random:index:Network"]
+		require.True(t, ok)
+		assert.True(t, res.IsComponent)
+		assert.Contains(t, res.InputProperties, "cidr")
+	})
+
+	t.Run("conflicts with a bridged resource", func(t *testing.T) {
+		provider := testprovider.ProviderMiniRandom()
+		provider.Components = map[string]*tfbridge.ComponentInfo{
+			"random:index/randomInteger:RandomInteger": {Construct: construct},
+		}
+		_, err := GenerateSchema(provider, sink)
+		assert.ErrorContains(t, err, "already defined")
+	})
+
+	t.Run("requires Construct", func(t *testing.T) {
+		provider := testprovider.ProviderMiniRandom()
+		provider.Components = map[string]*tfbridge.ComponentInfo{
+			"random:index:Network": {},
+		}
+		_, err := GenerateSchema(provider, sink)
+		assert.ErrorContains(t, err, "Construct is required")
+	})
+}
+
+func TestResourceMethods(t *testing.T) {
+	provider := testprovider.ProviderMiniRandom()
+	provider.Resources["random_integer"].Methods = map[string]*tfbridge.MethodInfo{
+		"getRange": {
+			Schema: pschema.FunctionSpec{
+				Inputs: &pschema.ObjectTypeSpec{
+					Properties: map[string]pschema.PropertySpec{
+						"scale": {TypeSpec: pschema.TypeSpec{Type: "integer"}},
+					},
+				},
+			},
+			Call: func(context.Context, resource.PropertyMap, resource.PropertyMap) (resource.PropertyMap, error) {
+				return nil, nil
+			},
+		},
+	}
+	spec, err := GenerateSchema(provider, diag.DefaultSink(io.Discard, io.Discard, diag.FormatOptions{
+		Color: colors.Never,
+	}))
+	require.NoError(t, err)
+
+	const resTok = "random:index/randomInteger:RandomInteger"
+	const funTok = resTok + "/getRange"
+	assert.Equal(t, map[string]string{"getRange": funTok}, spec.Resources[resTok].Methods)
+
+	fun, ok := spec.Functions[funTok]
+	require.True(t, ok)
+	require.NotNil(t, fun.Inputs)
+	assert.Equal(t, "#/resources/"+resTok, fun.Inputs.Properties["__self__"].Ref)
+	assert.Contains(t, fun.Inputs.Properties, "scale")
+	assert.Equal(t, []string{"__self__"}, fun.Inputs.Required)
+}
@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -487,3 +488,128 @@ func TestGetNestedDescriptionFromParsedDocs(t *testing.T) {
 		})
 	}
 }
+
+func TestJavaLanguage(t *testing.T) {
+	p := (&shimschema.Provider{
+		ResourcesMap: shimschema.ResourceMap{
+			"test_res": (&shimschema.Resource{
+				Schema: shimschema.SchemaMap{
+					"name": (&shimschema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+				},
+			}).Shim(),
+		},
+	}).Shim()
+
+	overlay := "src/main/java/com/example/test/Extra.java"
+	info := tfbridge.ProviderInfo{
+		Name: "test",
+		P:    p,
+		Resources: map[string]*tfbridge.ResourceInfo{
+			"test_res": {Tok: "test:index:Bar"},
+		},
+		Java: &tfbridge.JavaInfo{
+			BasePackage: "com.example",
+			Overlay:     &tfbridge.OverlayInfo{DestFiles: []string{overlay}},
+		},
+	}
+
+	nilSink := diag.DefaultSink(io.Discard, io.Discard, diag.FormatOptions{
+		Color: colors.Never,
+	})
+	r, err := GenerateSchemaWithOptions(GenerateSchemaOptions{
+		DiagnosticsSink: nilSink,
+		ProviderInfo:    info,
+	})
+	require.NoError(t, err)
+	pkg, diags, err := pschema.BindSpec(r.PackageSpec, nil)
+	require.NoError(t, err)
+	require.False(t, diags.HasErrors(), diags)
+
+	root := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(root, overlay, []byte("// overlay"), 0600))
+
+	files, err := Java.emitSDK(pkg, info, root)
+	require.NoError(t, err)
+	assert.Contains(t, files, "src/main/java/com/example/test/Bar.java")
+	assert.Equal(t, "// overlay", string(files[overlay]))
+	assert.Equal(t, []string{"java"}, genLanguageToSlice(Java))
+}
+
+func TestParallelGeneration(t *testing.T) {
+	resources := shimschema.ResourceMap{}
+	dataSources := shimschema.ResourceMap{}
+	info := tfbridge.ProviderInfo{
+		Name:        "test",
+		Resources:   map[string]*tfbridge.ResourceInfo{},
+		DataSources: map[string]*tfbridge.DataSourceInfo{},
+	}
+	docs := &mapDocsSource{}
+	for i := 0; i < 32; i++ {
+		name := fmt.Sprintf("test_thing%d", i)
+		member := fmt.Sprintf("Thing%d", i)
+		mod := fmt.Sprintf("test:index/thing%d:", i)
+		r := (&shimschema.Resource{Schema: shimschema.SchemaMap{
+			"name":  (&shimschema.Schema{Type: shim.TypeString, Required: true}).Shim(),
+			"count": (&shimschema.Schema{Type: shim.TypeInt, Optional: true}).Shim(),
+		}}).Shim()
+		resources[name] = r
+		dataSources[name] = r
+		info.Resources[name] = &tfbridge.ResourceInfo{Tok: tokens.Type(mod + member)}
+		info.DataSources[name] = &tfbridge.DataSourceInfo{Tok: tokens.ModuleMember(mod + "get" + member)}
+		docs.set(name, fmt.Sprintf("# %s\n\nManages thing %d.\n\n## Example Usage\n\n```hcl\n"+
+			"resource \"%s\" \"example\" {\n  name = \"example\"\n}\n```\n\n## Argument Reference\n\n"+
+			"* `name` - (Required) The name of thing %d.\n\n## Timeouts\n\nNone.\n", name, i, name, i))
+	}
+	info.P = (&shimschema.Provider{ResourcesMap: resources, DataSourcesMap: dataSources}).Shim()
+
+	generate := func(t *testing.T, parallel int) (string, string) {
+		root := afero.NewMemMapFs()
+		report := filepath.Join(t.TempDir(), "report.json")
+		g, err := NewGenerator(GeneratorOptions{
+			Package:      "test",
+			Language:     Schema,
+			ProviderInfo: info,
+			Root:         root,
+			Sink:         diag.DefaultSink(io.Discard, io.Discard, diag.FormatOptions{Color: colors.Never}),
+			DocsSource:   docs,
+			DocsReport:   report,
+			Parallel:     parallel,
+		})
+		require.NoError(t, err)
+		require.NoError(t, g.Generate())
+		schema, err := afero.ReadFile(root, "schema.json")
+		require.NoError(t, err)
+		reportBytes, err := os.ReadFile(report)
+		require.NoError(t, err)
+		return string(schema), string(reportBytes)
+	}
+
+	expectedSchema, expectedReport := generate(t, 1)
+	assert.Contains(t, expectedSchema, "The name of thing 31.")
+	// Every resource has an example, so the parallel run converts examples on several workers at once. Run with -race
+	// to check that the workers share the Generator safely.
+	assert.Contains(t, expectedSchema, "```typescript")
+	actualSchema, actualReport := generate(t, 8)
+	assert.Equal(t, expectedSchema, actualSchema)
+	assert.Equal(t, expectedReport, actualReport)
+}
+
+// mapDocsSource serves the same docs for the resource and data source of each name.
+type mapDocsSource struct {
+	docs map[string]string
+}
+
+func (s *mapDocsSource) set(name, doc string) {
+	if s.docs == nil {
+		s.docs = map[string]string{}
+	}
+	s.docs[name] = doc
+}
+
+func (s *mapDocsSource) GetResource(rawname string, _ *tfbridge.DocInfo) (*DocFile, error) {
+	return &DocFile{Content: []byte(s.docs[rawname]), FileName: rawname + ".html.markdown"}, nil
+}
+
+func (s *mapDocsSource) GetDatasource(rawname string, info *tfbridge.DocInfo) (*DocFile, error) {
+	return s.GetResource(rawname, info)
+}
@@ -0,0 +1,67 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge/lint"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfgen/internal/testprovider"
+)
+
+func TestLintCommand(t *testing.T) {
+	info := testprovider.ProviderMiniRandom()
+	secret := true
+	info.Resources["random_integer"].Fields = map[string]*tfbridge.SchemaInfo{
+		"seed": {Secret: &secret},
+	}
+	out := filepath.Join(t.TempDir(), "lint.json")
+
+	lintCmd := func(args ...string) error {
+		cmd := newTFGenCmd(info.Name, info.Version, info, func(GeneratorOptions) error {
+			t.Fatal("lint must not generate an SDK")
+			return nil
+		})
+		cmd.SetArgs(append([]string{"lint"}, args...))
+		return cmd.Execute()
+	}
+
+	require.NoError(t, lintCmd("--json", out))
+
+	bytes, err := os.ReadFile(out)
+	require.NoError(t, err)
+	var report lint.Report
+	require.NoError(t, json.Unmarshal(bytes, &report))
+	assert.Equal(t, []lint.Issue{{
+		Rule:     "secret-override",
+		Severity: lint.Warning,
+		Token:    "random_integer",
+		Path:     "seed",
+		Message: "Secret marks a field that is not sensitive in the Terraform schema; " +
+			"check that the field holds secret values",
+	}}, report.Issues)
+
+	require.NoError(t, lintCmd("--disable", "secret-override:random_integer", "--json", out))
+	bytes, err = os.ReadFile(out)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"issues": []}`, string(bytes))
+}
@@ -15,7 +15,9 @@
 package tfgen
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -25,6 +27,9 @@ import (
 
 	"github.com/golang/glog"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge/lint"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/snapshot"
+	pschema "github.com/pulumi/pulumi/pkg/v3/codegen/schema"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 	"github.com/spf13/afero"
@@ -32,11 +37,14 @@ import (
 )
 
 // Main executes the TFGen process for the given package pkg and provider prov.
+//
+// prov.P may be a provider launched from a prebuilt binary with tfplugin5.StartProvider, in which case the provider
+// process is stopped before Main exits.
 func Main(pkg string, version string, prov tfbridge.ProviderInfo) {
-	err := prov.P.InternalValidate()
-	if err != nil {
+	if err := validateProvider(prov); err != nil {
 		_, fmterr := fmt.Fprintf(os.Stderr, "Internal validation of the provider failed: %v\n", err)
 		contract.IgnoreError(fmterr)
+		stopProvider(prov)
 		os.Exit(-1)
 	}
 
@@ -57,17 +65,112 @@ func Main(pkg string, version string, prov tfbridge.ProviderInfo) {
 	})
 }
 
+// validateProvider runs the internal validation of prov.P. prov.P may be nil when the schema is loaded from a
+// snapshot with --shim-snapshot.
+func validateProvider(prov tfbridge.ProviderInfo) error {
+	if prov.P == nil {
+		return nil
+	}
+	return prov.P.InternalValidate()
+}
+
 // Like Main but allows to customize the generation logic past the parsing of cmd-line arguments.
 func MainWithCustomGenerate(pkg string, version string, prov tfbridge.ProviderInfo,
 	gen func(GeneratorOptions) error) {
 
-	if err := newTFGenCmd(pkg, version, prov, gen).Execute(); err != nil {
+	err := newTFGenCmd(pkg, version, prov, gen).Execute()
+	stopProvider(prov)
+	if err != nil {
 		_, fmterr := fmt.Fprintf(os.Stderr, "An error occurred: %v\n", err)
 		contract.IgnoreError(fmterr)
 		os.Exit(-1)
 	}
 }
 
+// newDocsSource returns the DocsSource selected by the docs flags of the tfgen command, or nil to use the upstream
+// repository.
+func newDocsSource(
+	prov tfbridge.ProviderInfo, dir, archive, registryJSON, schemaJSON string,
+) (DocsSource, error) {
+	var selected int
+	for _, flag := range []string{dir, archive, registryJSON + schemaJSON} {
+		if flag != "" {
+			selected++
+		}
+	}
+	if selected > 1 {
+		return nil, fmt.Errorf("--docs-dir, --docs-archive and --docs-registry-json are mutually exclusive")
+	}
+
+	switch {
+	case dir != "":
+		return NewLocalDocsSource(prov, dir), nil
+	case archive != "":
+		return NewArchiveDocsSource(prov, archive)
+	case registryJSON != "" || schemaJSON != "":
+		return NewRegistryDocsSource(prov, registryJSON, schemaJSON)
+	default:
+		return nil, nil
+	}
+}
+
+// newExamplesCacheFromFlags returns the ExamplesCache selected by the examples cache flags of the tfgen command, or
+// nil to use PULUMI_CONVERT_EXAMPLES_CACHE_DIR.
+func newExamplesCacheFromFlags(location, importPath, exportPath string) (*recordingExamplesCache, error) {
+	if location == "" && importPath == "" && exportPath == "" {
+		return nil, nil
+	}
+
+	var cache ExamplesCache
+	var err error
+	switch {
+	case location != "":
+		cache, err = OpenExamplesCache(location)
+	case os.Getenv(pulumiConvertExamplesCacheDirEnvVar) != "":
+		cache = NewDirExamplesCache(os.Getenv(pulumiConvertExamplesCacheDirEnvVar))
+	default:
+		// Without a persistent cache, keep the imported translations in memory for this run.
+		cache = &archiveExamplesCache{entries: map[string][]byte{}}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if importPath != "" {
+		n, err := ImportExamplesCacheArchive(cache, importPath)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Printf("Imported %d translations into the examples cache from %s.\n", n, importPath)
+	}
+	return newRecordingExamplesCache(cache), nil
+}
+
+// closeExamplesCache exports the translations used by this run to exportPath, if set, and closes the cache.
+func closeExamplesCache(cache *recordingExamplesCache, exportPath string) error {
+	if exportPath != "" {
+		if err := cache.export(exportPath); err != nil {
+			return fmt.Errorf("exporting the examples cache to %s: %w", exportPath, err)
+		}
+	}
+	return cache.Close()
+}
+
+// stopProvider terminates the provider process if prov.P was launched by tfplugin5.StartProvider.
+//
+// tfplugin5 is not imported directly: its generated protobuf types conflict with those of terraform-plugin-go, which
+// Plugin Framework providers link in.
+func stopProvider(prov tfbridge.ProviderInfo) {
+	closer, ok := prov.P.(io.Closer)
+	if !ok {
+		return
+	}
+	if err := closer.Close(); err != nil {
+		_, fmterr := fmt.Fprintf(os.Stderr, "Failed to stop the provider: %v\n", err)
+		contract.IgnoreError(fmterr)
+	}
+}
+
 func newTFGenCmd(pkg string, version string, prov tfbridge.ProviderInfo,
 	gen func(GeneratorOptions) error) *cobra.Command {
 
@@ -82,6 +185,18 @@ func newTFGenCmd(pkg string, version string, prov tfbridge.ProviderInfo,
 	var debug bool
 	var skipDocs bool
 	var skipExamples bool
+	var docsDir string
+	var docsArchive string
+	var docsRegistryJSON string
+	var docsSchemaJSON string
+	var shimSnapshot string
+	var examplesCacheLocation string
+	var examplesCacheImport string
+	var examplesCacheExport string
+	var manifestPath string
+	var docsReport string
+	var parallel int
+	var full bool
 	cmd := &cobra.Command{
 		Use:   os.Args[0] + " <LANGUAGE>",
 		Args:  cmdutil.SpecificArgs([]string{"language"}),
@@ -146,6 +261,17 @@ func newTFGenCmd(pkg string, version string, prov tfbridge.ProviderInfo,
 				root = afero.NewBasePathFs(afero.NewOsFs(), absOutDir)
 			}
 
+			docsSource, err := newDocsSource(prov, docsDir, docsArchive, docsRegistryJSON, docsSchemaJSON)
+			if err != nil {
+				return err
+			}
+
+			examplesCache, err := newExamplesCacheFromFlags(
+				examplesCacheLocation, examplesCacheImport, examplesCacheExport)
+			if err != nil {
+				return err
+			}
+
 			// Creating an item to keep track of example coverage if the
 			// COVERAGE_OUTPUT_DIR env is set
 			var coverageTracker *CoverageTracker
@@ -162,9 +288,25 @@ func newTFGenCmd(pkg string, version string, prov tfbridge.ProviderInfo,
 				SkipDocs:        skipDocs,
 				SkipExamples:    skipExamples,
 				CoverageTracker: coverageTracker,
+				DocsSource:      docsSource,
+				ShimSnapshot:    shimSnapshot,
+				Manifest:        manifestPath,
+				DocsReport:      docsReport,
+				Parallel:        parallel,
+				Full:            full,
+				docsMetrics:     newDocsMetrics(),
+			}
+			if examplesCache != nil {
+				opts.ExamplesCache = examplesCache
 			}
 
-			err := gen(opts)
+			err = gen(opts)
+
+			if examplesCache != nil {
+				if exportErr := closeExamplesCache(examplesCache, examplesCacheExport); err == nil {
+					err = exportErr
+				}
+			}
 
 			// Exporting collected coverage data to the directory specified by COVERAGE_OUTPUT_DIR
 			if coverageTrackingOutputEnabled {
@@ -173,7 +315,7 @@ func newTFGenCmd(pkg string, version string, prov tfbridge.ProviderInfo,
 				fmt.Println("\nAdditional example conversion stats are available by setting COVERAGE_OUTPUT_DIR.")
 			}
 			fmt.Println(coverageTracker.getShortResultSummary())
-			printDocStats()
+			opts.docsMetrics.print()
 
 			return err
 		}),
@@ -203,11 +345,251 @@ func newTFGenCmd(pkg string, version string, prov tfbridge.ProviderInfo,
 	cmd.PersistentFlags().BoolVar(
 		&skipExamples, "skip-examples", false, "Do not convert examples from HCL")
 
+	cmd.PersistentFlags().StringVar(
+		&docsDir, "docs-dir", "", "Read upstream docs from this directory instead of the upstream repository")
+	cmd.PersistentFlags().StringVar(
+		&docsArchive, "docs-archive", "", "Read upstream docs from this .tar.gz bundle instead of the upstream repository")
+	cmd.PersistentFlags().StringVar(
+		&docsRegistryJSON, "docs-registry-json", "",
+		"Read upstream docs from this Terraform Registry provider-docs JSON instead of the upstream repository")
+	cmd.PersistentFlags().StringVar(
+		&docsSchemaJSON, "docs-schema-json", "",
+		"Document entities missing from --docs-registry-json with this `terraform providers schema -json` output")
+
+	cmd.PersistentFlags().StringVar(
+		&shimSnapshot, "shim-snapshot", "",
+		"Read the provider schema from this file written by dump-shim instead of the compiled-in provider")
+
+	cmd.PersistentFlags().StringVar(
+		&examplesCacheLocation, "examples-cache", "",
+		"Cache translated examples in this directory, .tar.gz archive or HTTP URL "+
+			"(defaults to $"+pulumiConvertExamplesCacheDirEnvVar+")")
+	cmd.PersistentFlags().StringVar(
+		&examplesCacheImport, "examples-cache-import", "",
+		"Pre-warm the examples cache with the translations of this .tar.gz archive")
+	cmd.PersistentFlags().StringVar(
+		&examplesCacheExport, "examples-cache-export", "",
+		"Write the translations used by this run to this .tar.gz archive")
+
+	cmd.PersistentFlags().StringVar(
+		&manifestPath, "manifest", "",
+		"Reuse the docs and examples of unchanged resources and data sources recorded in this manifest file")
+	cmd.PersistentFlags().BoolVar(
+		&full, "full", false, "Regenerate every resource and data source, rewriting the --manifest file")
+
+	cmd.PersistentFlags().IntVar(
+		&parallel, "parallel", 1,
+		"Parse the docs and convert the examples of this many resources and data sources concurrently")
+
+	cmd.PersistentFlags().StringVar(
+		&docsReport, "docs-report", "",
+		"Write a JSON report of the missing descriptions and dropped docs of each resource and function to this file")
+
 	cmd.PersistentFlags().StringVar(
 		&overlaysDir, "overlays", "",
 		"Use the target directory for overlays rather than the default of overlays/ (unsupported)")
 	err := cmd.PersistentFlags().MarkHidden("overlays")
 	contract.AssertNoErrorf(err, "err != nil")
 
+	// The subcommands read the persistent flags when they run, after the flags are parsed.
+	loadProvider := func() (tfbridge.ProviderInfo, error) {
+		return withShimSnapshot(prov, shimSnapshot)
+	}
+	cmd.AddCommand(newMappingCmd(loadProvider))
+	cmd.AddCommand(newSchemaDiffCmd())
+	cmd.AddCommand(newLintCmd(loadProvider))
+	cmd.AddCommand(newDumpShimCmd(loadProvider))
+
+	return cmd
+}
+
+// withShimSnapshot returns prov with the provider schema read from the snapshot at path, if path is set.
+func withShimSnapshot(prov tfbridge.ProviderInfo, path string) (tfbridge.ProviderInfo, error) {
+	if path == "" {
+		return prov, nil
+	}
+	p, err := snapshot.ReadFile(path)
+	if err != nil {
+		return prov, err
+	}
+	prov.P = p
+	return prov, nil
+}
+
+func newMappingCmd(loadProvider func() (tfbridge.ProviderInfo, error)) *cobra.Command {
+	var out string
+	cmd := &cobra.Command{
+		Use:   "mapping",
+		Args:  cmdutil.NoArgs,
+		Short: "Write the converter mapping of the provider",
+		Long: "Write the converter mapping of the provider.\n" +
+			"\n" +
+			"The mapping holds the same data the provider plugin returns from GetMapping, which\n" +
+			"`pulumi convert` uses to translate Terraform programs. It is wrapped in a header\n" +
+			"carrying the version of the file layout.\n",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			prov, err := loadProvider()
+			if err != nil {
+				return err
+			}
+			mapping, err := GenerateMapping(prov)
+			if err != nil {
+				return err
+			}
+			bytes, err := json.Marshal(mapping)
+			if err != nil {
+				return err
+			}
+			bytes = append(bytes, '\n')
+			if out == "" {
+				_, err = os.Stdout.Write(bytes)
+				return err
+			}
+			return os.WriteFile(out, bytes, 0600)
+		}),
+	}
+	cmd.Flags().StringVarP(&out, "out", "o", "", "Write the mapping to this file instead of stdout")
+	return cmd
+}
+
+func newDumpShimCmd(loadProvider func() (tfbridge.ProviderInfo, error)) *cobra.Command {
+	var out string
+	cmd := &cobra.Command{
+		Use:   "dump-shim",
+		Args:  cmdutil.NoArgs,
+		Short: "Write a snapshot of the Terraform schema of the provider",
+		Long: "Write a snapshot of the Terraform schema of the provider.\n" +
+			"\n" +
+			"The snapshot holds the resources, data sources and configuration of the provider with\n" +
+			"their schemas, timeouts and deprecations. Passing it to --shim-snapshot generates the\n" +
+			"provider without compiling the upstream provider.\n",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			prov, err := loadProvider()
+			if err != nil {
+				return err
+			}
+			if prov.P == nil {
+				return fmt.Errorf("the provider has no schema to dump")
+			}
+			if out != "" {
+				return snapshot.WriteFile(out, prov.P)
+			}
+			bytes, err := snapshot.Marshal(prov.P)
+			if err != nil {
+				return err
+			}
+			_, err = os.Stdout.Write(bytes)
+			return err
+		}),
+	}
+	cmd.Flags().StringVarP(&out, "out", "o", "", "Write the snapshot to this file instead of stdout")
+	return cmd
+}
+
+func newSchemaDiffCmd() *cobra.Command {
+	var jsonOut string
+	var markdownOut string
+	var failOnBreaking bool
+	cmd := &cobra.Command{
+		Use:   "schema-diff <OLD-SCHEMA> <NEW-SCHEMA>",
+		Args:  cmdutil.ExactArgs(2),
+		Short: "Report the changes between two versions of the Pulumi schema of the provider",
+		Long: "Report the changes between two versions of the Pulumi schema of the provider.\n" +
+			"\n" +
+			"Each change is classified as breaking or not for each SDK language. The report is\n" +
+			"printed as Markdown unless --json or --markdown are given.\n",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			var specs [2]pschema.PackageSpec
+			for i, path := range args {
+				if err := readJSONFile(path, &specs[i]); err != nil {
+					return err
+				}
+			}
+			diff := DiffSchemas(specs[0], specs[1])
+
+			if jsonOut != "" {
+				bytes, err := json.MarshalIndent(diff, "", "    ")
+				if err != nil {
+					return err
+				}
+				if err := os.WriteFile(jsonOut, bytes, 0600); err != nil {
+					return err
+				}
+			}
+			if markdownOut != "" {
+				if err := os.WriteFile(markdownOut, []byte(diff.Markdown()), 0600); err != nil {
+					return err
+				}
+			}
+			if jsonOut == "" && markdownOut == "" {
+				fmt.Print(diff.Markdown())
+			}
+
+			if failOnBreaking && diff.HasBreakingChanges() {
+				return fmt.Errorf("the schema has breaking changes")
+			}
+			return nil
+		}),
+	}
+	cmd.Flags().StringVar(&jsonOut, "json", "", "Write the report as JSON to this file")
+	cmd.Flags().StringVar(&markdownOut, "markdown", "", "Write the report as Markdown to this file")
+	cmd.Flags().BoolVar(&failOnBreaking, "fail-on-breaking", false, "Exit with an error if any change is breaking")
+	return cmd
+}
+
+func newLintCmd(loadProvider func() (tfbridge.ProviderInfo, error)) *cobra.Command {
+	var jsonOut string
+	var disable []string
+	var failOnWarnings bool
+	var listRules bool
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Args:  cmdutil.NoArgs,
+		Short: "Check the provider mapping for likely mistakes",
+		Long: "Check the provider mapping for likely mistakes.\n" +
+			"\n" +
+			"Each issue is reported by a named rule. Rules can be disabled for the whole provider\n" +
+			"with --disable <rule>, or for a single resource or data source with\n" +
+			"--disable <rule>:<terraform token>.\n",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			if listRules {
+				for _, r := range lint.DefaultRules() {
+					fmt.Printf("%s (%s): %s\n", r.Name, r.Severity, r.Description)
+				}
+				return nil
+			}
+
+			prov, err := loadProvider()
+			if err != nil {
+				return err
+			}
+			report, err := lint.Run(cmd.Context(), prov, lint.Options{Disable: disable})
+			if err != nil {
+				return err
+			}
+			for _, issue := range report.Issues {
+				fmt.Println(issue)
+			}
+			if jsonOut != "" {
+				bytes, err := report.JSON()
+				if err != nil {
+					return err
+				}
+				if err := os.WriteFile(jsonOut, bytes, 0600); err != nil {
+					return err
+				}
+			}
+
+			if report.HasErrors() || failOnWarnings && len(report.Issues) > 0 {
+				return fmt.Errorf("found %d lint issues", len(report.Issues))
+			}
+			return nil
+		}),
+	}
+	cmd.Flags().StringVar(&jsonOut, "json", "", "Write the report as JSON to this file")
+	cmd.Flags().StringArrayVar(
+		&disable, "disable", nil, "Disable a rule, or a rule for a single Terraform token as <rule>:<token>")
+	cmd.Flags().BoolVar(&failOnWarnings, "fail-on-warnings", false, "Exit with an error if any warning is reported")
+	cmd.Flags().BoolVar(&listRules, "list-rules", false, "List the available rules and exit")
 	return cmd
 }
@@ -0,0 +1,549 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Incremental generation. Parsing the upstream docs and converting their examples dominates the run time of tfgen
+// for large providers, while most resources are unchanged between two runs. The manifest records a hash of the
+// inputs of every resource and data source together with the outputs derived from them, so that the next run can
+// reuse the outputs of unchanged entities:
+//
+//   - the parsed docs of an entity are reused if its TF schema, its ResourceInfo or DataSourceInfo and the bytes of
+//     every doc file read for it are unchanged;
+//   - the converted schema of a resource or function is reused if its schema before example conversion is unchanged.
+//
+// The whole manifest is discarded when an input shared by all entities changes, such as the target language, the
+// tooling versions or the token mapping, which example conversion depends on. Callbacks such as DocRules can only
+// be compared by presence, so run tfgen with --full after changing their code.
+package tfgen
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"sync"
+
+	pschema "github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+)
+
+// manifestVersion is the version of the manifest file layout.
+const manifestVersion = 2
+
+type manifestFile struct {
+	Version    int    `json:"version"`
+	GlobalHash string `json:"globalHash"`
+
+	// Entities are keyed by DocKind and TF token, such as "resources/aws_s3_bucket".
+	Entities map[string]*manifestEntity `json:"entities,omitempty"`
+
+	// Resources and Functions are keyed by Pulumi token.
+	Resources map[string]*manifestSpec `json:"resources,omitempty"`
+	Functions map[string]*manifestSpec `json:"functions,omitempty"`
+}
+
+type manifestEntity struct {
+	InputHash string            `json:"inputHash"`
+	DocFiles  []manifestDocFile `json:"docFiles,omitempty"`
+	Docs      manifestDocs      `json:"docs"`
+}
+
+// manifestDocFile records a doc file read for an entity. Info is set if the file was looked up with the DocInfo of
+// the entity.
+type manifestDocFile struct {
+	Kind DocKind `json:"kind"`
+	Name string  `json:"name"`
+	Info bool    `json:"info,omitempty"`
+	Hash string  `json:"hash"`
+}
+
+type manifestDocs struct {
+	Description string            `json:"description,omitempty"`
+	Arguments   map[string]string `json:"arguments,omitempty"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+	Import      string            `json:"import,omitempty"`
+	Findings    docsFindings      `json:"findings"`
+}
+
+type manifestSpec struct {
+	InputHash string          `json:"inputHash"`
+	Spec      json.RawMessage `json:"spec"`
+	Examples  exampleFindings `json:"examples"`
+}
+
+// manifest holds the manifest of the previous run, if it can be reused, and the manifest of the current run.
+type manifest struct {
+	path     string
+	previous *manifestFile
+
+	mu   sync.Mutex // guards next and the counters, which are updated by parallel workers.
+	next *manifestFile
+
+	reusedDocs  int
+	reusedSpecs int
+}
+
+// newManifest loads the manifest at path. Unless full is set, the entries of the previous run are reused if it was
+// computed with the same global inputs.
+func newManifest(path string, full bool, info *tfbridge.ProviderInfo, lang Language) (*manifest, error) {
+	globalHash, err := manifestGlobalHash(info, lang)
+	if err != nil {
+		return nil, err
+	}
+	m := &manifest{
+		path: path,
+		next: &manifestFile{
+			Version:    manifestVersion,
+			GlobalHash: globalHash,
+			Entities:   map[string]*manifestEntity{},
+			Resources:  map[string]*manifestSpec{},
+			Functions:  map[string]*manifestSpec{},
+		},
+	}
+	if full {
+		return m, nil
+	}
+
+	bytes, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var previous manifestFile
+	if err := json.Unmarshal(bytes, &previous); err != nil {
+		return nil, fmt.Errorf("failed to read the tfgen manifest %s: %w", path, err)
+	}
+	if previous.Version == manifestVersion && previous.GlobalHash == globalHash {
+		m.previous = &previous
+	}
+	return m, nil
+}
+
+// getDocs returns the docs of an entity, reusing the docs parsed by the previous run if its inputs are unchanged.
+func (m *manifest) getDocs(g *Generator, kind DocKind, rawname string, res shim.Resource,
+	info tfbridge.ResourceOrDataSourceInfo) (entityDocs, error) {
+
+	key := string(kind) + "/" + rawname
+	inputHash := manifestEntityHash(kind, rawname, res, info)
+
+	if m.previous != nil {
+		if prev, ok := m.previous.Entities[key]; ok && prev.InputHash == inputHash &&
+			m.docFilesUnchanged(g.getDocsSource(), prev.DocFiles, info) {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			m.next.Entities[key] = prev
+			m.reusedDocs++
+			return prev.Docs.entityDocs(), nil
+		}
+	}
+
+	source := &recordingDocsSource{DocsSource: g.getDocsSource()}
+	docs, err := getDocsForResource(g, source, kind, rawname, info)
+	if err != nil {
+		return docs, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.next.Entities[key] = &manifestEntity{
+		InputHash: inputHash,
+		DocFiles:  source.files,
+		Docs:      newManifestDocs(docs),
+	}
+	return docs, nil
+}
+
+func (m *manifest) docFilesUnchanged(source DocsSource, files []manifestDocFile,
+	info tfbridge.ResourceOrDataSourceInfo) bool {
+
+	for _, f := range files {
+		var docInfo *tfbridge.DocInfo
+		if f.Info && info != nil {
+			docInfo = info.GetDocs()
+		}
+		var docFile *DocFile
+		var err error
+		switch f.Kind {
+		case ResourceDocs:
+			docFile, err = source.GetResource(f.Name, docInfo)
+		case DataSourceDocs:
+			docFile, err = source.GetDatasource(f.Name, docInfo)
+		}
+		if err != nil || hashDocFile(docFile) != f.Hash {
+			return false
+		}
+	}
+	return true
+}
+
+// convertedResource returns the converted spec of the previous run and the findings of its conversion if the
+// unconverted spec is unchanged, and records the unconverted spec of the current run.
+func (m *manifest) convertedResource(
+	token string, spec pschema.ResourceSpec,
+) (pschema.ResourceSpec, exampleFindings, bool) {
+	var converted pschema.ResourceSpec
+	findings, ok := m.convertedSpec(m.previous.resources(), m.next.Resources, token, spec, &converted)
+	return converted, findings, ok
+}
+
+// convertedFunction is like convertedResource for functions.
+func (m *manifest) convertedFunction(
+	token string, spec pschema.FunctionSpec,
+) (pschema.FunctionSpec, exampleFindings, bool) {
+	var converted pschema.FunctionSpec
+	findings, ok := m.convertedSpec(m.previous.functions(), m.next.Functions, token, spec, &converted)
+	return converted, findings, ok
+}
+
+func (m *manifest) convertedSpec(
+	previous, next map[string]*manifestSpec, token string, spec, converted interface{},
+) (exampleFindings, bool) {
+	inputHash, err := hashJSON(spec)
+	if err != nil {
+		return exampleFindings{}, false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	next[token] = &manifestSpec{InputHash: inputHash}
+
+	prev, ok := previous[token]
+	if !ok || prev.InputHash != inputHash || json.Unmarshal(prev.Spec, converted) != nil {
+		return exampleFindings{}, false
+	}
+	m.reusedSpecs++
+	return prev.Examples, true
+}
+
+// write records the converted specs of the current run and the findings of their conversion, and writes the
+// manifest.
+func (m *manifest) write(spec pschema.PackageSpec, metrics *docsMetrics) error {
+	for token, s := range m.next.Resources {
+		if e, ok := metrics.entities[token]; ok {
+			s.Examples = e.exampleFindings
+		}
+		if r, ok := spec.Resources[token]; ok {
+			if err := s.setSpec(r); err != nil {
+				return err
+			}
+		}
+	}
+	for token, s := range m.next.Functions {
+		if e, ok := metrics.entities[token]; ok {
+			s.Examples = e.exampleFindings
+		}
+		if f, ok := spec.Functions[token]; ok {
+			if err := s.setSpec(f); err != nil {
+				return err
+			}
+		}
+	}
+
+	bytes, err := json.MarshalIndent(m.next, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(m.path, append(bytes, '\n'), 0600)
+}
+
+func (s *manifestSpec) setSpec(spec interface{}) error {
+	bytes, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+	s.Spec = bytes
+	return nil
+}
+
+func (f *manifestFile) resources() map[string]*manifestSpec {
+	if f == nil {
+		return nil
+	}
+	return f.Resources
+}
+
+func (f *manifestFile) functions() map[string]*manifestSpec {
+	if f == nil {
+		return nil
+	}
+	return f.Functions
+}
+
+func newManifestDocs(docs entityDocs) manifestDocs {
+	result := manifestDocs{
+		Description: docs.Description,
+		Attributes:  docs.Attributes,
+		Import:      docs.Import,
+		Findings:    docs.findings,
+	}
+	if len(docs.Arguments) > 0 {
+		result.Arguments = make(map[string]string, len(docs.Arguments))
+		for k, v := range docs.Arguments {
+			result.Arguments[string(k)] = v.description
+		}
+	}
+	return result
+}
+
+func (d manifestDocs) entityDocs() entityDocs {
+	result := entityDocs{
+		Description: d.Description,
+		Attributes:  d.Attributes,
+		Import:      d.Import,
+		findings:    d.Findings,
+	}
+	if len(d.Arguments) > 0 {
+		result.Arguments = make(map[docsPath]*argumentDocs, len(d.Arguments))
+		for k, v := range d.Arguments {
+			result.Arguments[docsPath(k)] = &argumentDocs{description: v}
+		}
+	}
+	return result
+}
+
+// recordingDocsSource records the doc files read for an entity.
+type recordingDocsSource struct {
+	DocsSource
+
+	files []manifestDocFile
+}
+
+func (s *recordingDocsSource) GetResource(rawname string, info *tfbridge.DocInfo) (*DocFile, error) {
+	f, err := s.DocsSource.GetResource(rawname, info)
+	s.record(ResourceDocs, rawname, info, f)
+	return f, err
+}
+
+func (s *recordingDocsSource) GetDatasource(rawname string, info *tfbridge.DocInfo) (*DocFile, error) {
+	f, err := s.DocsSource.GetDatasource(rawname, info)
+	s.record(DataSourceDocs, rawname, info, f)
+	return f, err
+}
+
+func (s *recordingDocsSource) record(kind DocKind, rawname string, info *tfbridge.DocInfo, f *DocFile) {
+	s.files = append(s.files, manifestDocFile{
+		Kind: kind,
+		Name: rawname,
+		Info: info != nil,
+		Hash: hashDocFile(f),
+	})
+}
+
+func hashDocFile(f *DocFile) string {
+	if f == nil {
+		return ""
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00", f.FileName)
+	_, err := h.Write(f.Content)
+	contract.IgnoreError(err)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// manifestEntityHash hashes the inputs of an entity other than its doc files.
+func manifestEntityHash(kind DocKind, rawname string, res shim.Resource,
+	info tfbridge.ResourceOrDataSourceInfo) string {
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00", kind, rawname)
+	if res != nil {
+		fmt.Fprintf(h, "deprecated=%q\x00", res.DeprecationMessage())
+		hashSchemaMap(h, res.Schema(), 0)
+	}
+	hashValue(h, reflect.ValueOf(info), 0)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashSchemaMap writes a stable encoding of the parts of m that tfgen reads to w. Defaults, schema versions and
+// timeouts are left out: Plugin Framework providers do not implement them and panic when asked.
+func hashSchemaMap(w io.Writer, m shim.SchemaMap, depth int) {
+	if m == nil {
+		fmt.Fprint(w, "nil")
+		return
+	}
+	keys := make([]string, 0, m.Len())
+	m.Range(func(key string, _ shim.Schema) bool {
+		keys = append(keys, key)
+		return true
+	})
+	sort.Strings(keys)
+	fmt.Fprint(w, "{")
+	for _, k := range keys {
+		fmt.Fprintf(w, "%q:", k)
+		hashSchema(w, m.Get(k), depth+1)
+		fmt.Fprint(w, ",")
+	}
+	fmt.Fprint(w, "}")
+}
+
+func hashSchema(w io.Writer, s shim.Schema, depth int) {
+	if depth > maxHashDepth {
+		fmt.Fprint(w, "<max depth>")
+		return
+	}
+	fmt.Fprintf(w, "{type=%v,optional=%t,required=%t,computed=%t,forceNew=%t,sensitive=%t,maxItems=%d,minItems=%d,"+
+		"deprecated=%q,description=%q,elem=", s.Type(), s.Optional(), s.Required(), s.Computed(), s.ForceNew(),
+		s.Sensitive(), s.MaxItems(), s.MinItems(), s.Deprecated(), s.Description())
+	switch e := s.Elem().(type) {
+	case shim.Schema:
+		hashSchema(w, e, depth+1)
+	case shim.Resource:
+		hashSchemaMap(w, e.Schema(), depth+1)
+	default:
+		fmt.Fprint(w, "nil")
+	}
+	fmt.Fprint(w, "}")
+}
+
+// manifestGlobalHash hashes the inputs shared by all entities.
+func manifestGlobalHash(info *tfbridge.ProviderInfo, lang Language) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "language=%s\x00", lang)
+	fmt.Fprintf(h, "cliConverter=%v\x00", cliConverterEnabled())
+	if t, ok := os.LookupEnv("PULUMI_CONVERT_ONLY"); ok {
+		fmt.Fprintf(h, "convertOnly=%s\x00", t)
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		fmt.Fprintf(h, "main=%s@%s\x00", bi.Main.Path, bi.Main.Version)
+		for _, dep := range bi.Deps {
+			if dep.Replace != nil {
+				dep = dep.Replace
+			}
+			fmt.Fprintf(h, "dep=%s@%s\x00", dep.Path, dep.Version)
+		}
+	}
+
+	// Hash the provider-wide settings, leaving out the per-entity settings hashed by manifestEntityHash, except
+	// for the tokens of entities, since the examples of any entity may refer to them.
+	// Version changes on every release without affecting docs, MetadataInfo is also written by tfgen itself and
+	// MuxWith holds live providers.
+	global := *info
+	global.P = nil
+	global.Resources = nil
+	global.DataSources = nil
+	global.Version = ""
+	global.MetadataInfo = nil
+	global.MuxWith = nil
+	hashValue(h, reflect.ValueOf(global), 0)
+
+	tokens := map[string]string{}
+	for k, r := range info.Resources {
+		if r != nil {
+			tokens["resources/"+k] = string(r.Tok)
+		}
+	}
+	for k, d := range info.DataSources {
+		if d != nil {
+			tokens["data-sources/"+k] = string(d.Tok)
+		}
+	}
+	hashValue(h, reflect.ValueOf(tokens), 0)
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashJSON(v interface{}) (string, error) {
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(bytes)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// maxHashDepth bounds the depth hashed by hashValue, guarding against cyclic values.
+const maxHashDepth = 64
+
+// hashValue writes a stable encoding of v to w. Map entries are sorted. Functions can not be inspected, so only
+// whether they are set is hashed.
+func hashValue(w io.Writer, v reflect.Value, depth int) {
+	write := func(s string) {
+		_, err := io.WriteString(w, s)
+		contract.IgnoreError(err)
+	}
+	if depth > maxHashDepth {
+		write("<max depth>")
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Invalid:
+		write("nil")
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			write("nil")
+			return
+		}
+		write("&")
+		hashValue(w, v.Elem(), depth+1)
+	case reflect.Struct:
+		write(v.Type().String() + "{")
+		for i := 0; i < v.NumField(); i++ {
+			write(v.Type().Field(i).Name + ":")
+			hashValue(w, v.Field(i), depth+1)
+			write(",")
+		}
+		write("}")
+	case reflect.Map:
+		entries := make([]string, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			var buf bytes.Buffer
+			hashValue(&buf, iter.Key(), depth+1)
+			buf.WriteString(":")
+			hashValue(&buf, iter.Value(), depth+1)
+			entries = append(entries, buf.String())
+		}
+		sort.Strings(entries)
+		write("map[")
+		for _, e := range entries {
+			write(e + ",")
+		}
+		write("]")
+	case reflect.Slice, reflect.Array:
+		write("[")
+		for i := 0; i < v.Len(); i++ {
+			hashValue(w, v.Index(i), depth+1)
+			write(",")
+		}
+		write("]")
+	case reflect.String:
+		write(strconv.Quote(v.String()))
+	case reflect.Bool:
+		write(strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		write(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		write(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		write(strconv.FormatFloat(v.Float(), 'g', -1, 64))
+	case reflect.Func:
+		if v.IsNil() {
+			write("nil")
+		} else {
+			write("func")
+		}
+	default:
+		write(v.Type().String())
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfgen/internal/testprovider"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+type countingDocsSource struct {
+	resources map[string]string
+	reads     int
+}
+
+func (s *countingDocsSource) GetResource(rawname string, _ *tfbridge.DocInfo) (*DocFile, error) {
+	s.reads++
+	content, ok := s.resources[rawname]
+	if !ok {
+		return nil, nil
+	}
+	return &DocFile{Content: []byte(content), FileName: rawname + ".html.markdown"}, nil
+}
+
+func (s *countingDocsSource) GetDatasource(string, *tfbridge.DocInfo) (*DocFile, error) {
+	return nil, nil
+}
+
+func TestIncrementalGeneration(t *testing.T) {
+	manifestPath := filepath.Join(t.TempDir(), "manifest.json")
+	source := &countingDocsSource{resources: map[string]string{
+		"random_integer": "# random_integer\n\nGenerates a random integer.\n\n" +
+			"## Argument Reference\n\n* `min` - (Required) The minimum value.\n",
+	}}
+
+	generate := func(t *testing.T, info tfbridge.ProviderInfo, full bool) (*Generator, string) {
+		root := afero.NewMemMapFs()
+		g, err := NewGenerator(GeneratorOptions{
+			Package:      info.Name,
+			Version:      info.Version,
+			Language:     Schema,
+			ProviderInfo: info,
+			Root:         root,
+			Sink:         diag.DefaultSink(io.Discard, io.Discard, diag.FormatOptions{Color: colors.Never}),
+			DocsSource:   source,
+			Manifest:     manifestPath,
+			Full:         full,
+		})
+		require.NoError(t, err)
+		require.NoError(t, g.Generate())
+		schema, err := afero.ReadFile(root, "schema.json")
+		require.NoError(t, err)
+		return g, string(schema)
+	}
+
+	g, expected := generate(t, testprovider.ProviderMiniRandom(), false)
+	assert.Equal(t, 0, g.manifest.reusedDocs)
+	assert.Equal(t, 0, g.manifest.reusedSpecs)
+	assert.Contains(t, expected, "The minimum value.")
+
+	t.Run("unchanged", func(t *testing.T) {
+		source.reads = 0
+		g, actual := generate(t, testprovider.ProviderMiniRandom(), false)
+		assert.Equal(t, 1, g.manifest.reusedDocs)
+		assert.Equal(t, 1, g.manifest.reusedSpecs)
+		assert.Equal(t, expected, actual)
+		// The doc file is read to check that it did not change, but not parsed again.
+		assert.Equal(t, 1, source.reads)
+	})
+
+	t.Run("overrides changed", func(t *testing.T) {
+		info := testprovider.ProviderMiniRandom()
+		info.Resources["random_integer"].DeprecationMessage = "use random_number"
+		g, actual := generate(t, info, false)
+		assert.Equal(t, 0, g.manifest.reusedDocs)
+		assert.Equal(t, 0, g.manifest.reusedSpecs)
+		assert.Contains(t, actual, "use random_number")
+	})
+
+	t.Run("docs changed", func(t *testing.T) {
+		generate(t, testprovider.ProviderMiniRandom(), false)
+		source.resources["random_integer"] += "* `max` - (Required) The maximum value.\n"
+		g, actual := generate(t, testprovider.ProviderMiniRandom(), false)
+		assert.Equal(t, 0, g.manifest.reusedDocs)
+		assert.Equal(t, 0, g.manifest.reusedSpecs)
+		assert.Contains(t, actual, "The maximum value.")
+	})
+
+	t.Run("full", func(t *testing.T) {
+		g, _ := generate(t, testprovider.ProviderMiniRandom(), true)
+		assert.Equal(t, 0, g.manifest.reusedDocs)
+		assert.Equal(t, 0, g.manifest.reusedSpecs)
+
+		g, _ = generate(t, testprovider.ProviderMiniRandom(), false)
+		assert.Equal(t, 1, g.manifest.reusedDocs)
+	})
+}
+
+func TestIncrementalGenerationFailure(t *testing.T) {
+	manifestPath := filepath.Join(t.TempDir(), "manifest.json")
+	info := testprovider.ProviderMiniRandom()
+	g, err := NewGenerator(GeneratorOptions{
+		Package:      info.Name,
+		Version:      info.Version,
+		Language:     Schema,
+		ProviderInfo: info,
+		Root:         afero.NewReadOnlyFs(afero.NewMemMapFs()),
+		Sink:         diag.DefaultSink(io.Discard, io.Discard, diag.FormatOptions{Color: colors.Never}),
+		DocsSource:   &countingDocsSource{},
+		Manifest:     manifestPath,
+	})
+	require.NoError(t, err)
+	require.Error(t, g.Generate())
+
+	_, err = os.Stat(manifestPath)
+	assert.True(t, os.IsNotExist(err), "the manifest of a failed run must not be written")
+}
+
+type pfLikeResource struct{ shim.Resource }
+
+func (pfLikeResource) SchemaVersion() int              { panic("SchemaVersion is not supported") }
+func (pfLikeResource) Timeouts() *shim.ResourceTimeout { panic("Timeouts is not supported") }
+
+type pfLikeSchema struct{ shim.Schema }
+
+func (pfLikeSchema) Default() interface{} { panic("Default is not supported") }
+
+func TestManifestEntityHash(t *testing.T) {
+	hash := func(description string) string {
+		res := pfLikeResource{(&schema.Resource{
+			Schema: schema.SchemaMap{
+				"id": pfLikeSchema{(&schema.Schema{
+					Type:        shim.TypeString,
+					Computed:    true,
+					Description: description,
+				}).Shim()},
+			},
+		}).Shim()}
+		return manifestEntityHash(ResourceDocs, "test_res", res, &tfbridge.ResourceInfo{})
+	}
+
+	assert.Equal(t, hash("The ID."), hash("The ID."))
+	assert.NotEqual(t, hash("The ID."), hash("The identifier."))
+}
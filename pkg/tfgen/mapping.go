@@ -0,0 +1,51 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+)
+
+// MappingSchemaVersion is the version of the MappingFile layout. It is incremented on incompatible changes.
+const MappingSchemaVersion = 1
+
+// MappingFile is the converter mapping artifact written by `tfgen mapping`.
+type MappingFile struct {
+	// SchemaVersion is the MappingSchemaVersion the file was written with.
+	SchemaVersion int `json:"schemaVersion"`
+
+	// Provider is the name the mapping is registered under, the same name GetMapping reports.
+	Provider string `json:"provider"`
+
+	// Data holds the bytes the provider returns from GetMapping for the "terraform" key, that is the JSON encoding of
+	// a tfbridge.MarshallableProviderInfo.
+	Data json.RawMessage `json:"data"`
+}
+
+// GenerateMapping computes the converter mapping of the provider without running the provider plugin.
+func GenerateMapping(info tfbridge.ProviderInfo) (*MappingFile, error) {
+	data, err := json.Marshal(tfbridge.MarshalProviderInfo(&info))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the mapping of %s: %w", info.Name, err)
+	}
+	return &MappingFile{
+		SchemaVersion: MappingSchemaVersion,
+		Provider:      info.Name,
+		Data:          data,
+	}, nil
+}
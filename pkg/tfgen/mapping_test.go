@@ -0,0 +1,68 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfgen/internal/testprovider"
+)
+
+func TestMappingCommand(t *testing.T) {
+	info := testprovider.ProviderMiniRandom()
+	out := filepath.Join(t.TempDir(), "random.json")
+
+	cmd := newTFGenCmd(info.Name, info.Version, info, func(GeneratorOptions) error {
+		t.Fatal("mapping must not generate an SDK")
+		return nil
+	})
+	cmd.SetArgs([]string{"mapping", "--out", out})
+	require.NoError(t, cmd.Execute())
+
+	bytes, err := os.ReadFile(out)
+	require.NoError(t, err)
+	var mapping MappingFile
+	require.NoError(t, json.Unmarshal(bytes, &mapping))
+	assert.Equal(t, MappingSchemaVersion, mapping.SchemaVersion)
+	assert.Equal(t, "random", mapping.Provider)
+
+	p := tfbridge.NewProvider(context.Background(), nil, info.Name, info.Version, info.P, info, nil)
+	resp, err := p.GetMapping(context.Background(), &pulumirpc.GetMappingRequest{Key: "terraform"})
+	require.NoError(t, err)
+	assert.Equal(t, resp.Provider, mapping.Provider)
+	assert.Equal(t, string(resp.Data), string(mapping.Data))
+}
+
+func TestMappingCommandKeepsLanguageArg(t *testing.T) {
+	info := testprovider.ProviderMiniRandom()
+
+	var language Language
+	cmd := newTFGenCmd(info.Name, info.Version, info, func(opts GeneratorOptions) error {
+		language = opts.Language
+		return nil
+	})
+	cmd.SetArgs([]string{"schema", "--out", t.TempDir()})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, Schema, language)
+}
@@ -15,16 +15,25 @@
 package tfgen
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
 
+	pschema "github.com/pulumi/pulumi/pkg/v3/codegen/schema"
 	schemaTools "github.com/pulumi/schema-tools/pkg"
 )
 
-var (
-	ignoredDocHeaders     = make(map[string]int)
+// docsMetrics holds the statistics of parsing and converting docs for a single Generator. The record methods may be
+// called concurrently.
+type docsMetrics struct {
+	mu sync.Mutex
+
+	ignoredDocHeaders     map[string]int
 	elidedArguments       int
 	elidedNestedArguments int
-	unexpectedSnippets    int
 
 	// Arguments metrics:
 	totalArgumentsFromDocs int
@@ -33,33 +42,267 @@ var (
 	entitiesMissingDocs                int
 
 	schemaStats schemaTools.PulumiSchemaStats
-)
 
-// printDocStats outputs metrics relating to document parsing and conversion
-func printDocStats() {
+	// entities holds the report of each resource and function, keyed by Pulumi token.
+	entities map[string]*docsReportEntity
+}
+
+func newDocsMetrics() *docsMetrics {
+	return &docsMetrics{
+		ignoredDocHeaders: map[string]int{},
+		entities:          map[string]*docsReportEntity{},
+	}
+}
+
+// docsFindings are the findings of parsing the upstream docs of a resource or data source. They are recorded in
+// entityDocs, so that they are kept along with docs reused from the manifest of incremental generation.
+type docsFindings struct {
+	// Missing is set if the upstream docs could not be found.
+	Missing bool `json:"missing,omitempty"`
+	// ArgumentsFromDocs is the number of argument descriptions parsed from the upstream docs.
+	ArgumentsFromDocs int `json:"argumentsFromDocs,omitempty"`
+	// ElidedArguments are the arguments whose descriptions were dropped because they contained an <elided>
+	// reference.
+	ElidedArguments []string `json:"elidedArguments,omitempty"`
+	// IgnoredSections are the headers of the upstream sections which are not supported, such as Timeouts.
+	IgnoredSections []string `json:"ignoredSections,omitempty"`
+}
+
+// exampleFindings are the findings of converting the examples of a resource or function.
+type exampleFindings struct {
+	// UnconvertedSections are the headers of the sections dropped because their examples failed to convert.
+	UnconvertedSections []string `json:"unconvertedSections,omitempty"`
+	// SkippedExamples are the example paths dropped by ProviderInfo.SkipExamples.
+	SkippedExamples []string `json:"skippedExamples,omitempty"`
+}
+
+// docsReportEntity is the entry of a resource or function in the docs report.
+type docsReportEntity struct {
+	Kind   string `json:"kind,omitempty"`
+	TFName string `json:"tfName,omitempty"`
+
+	MissingDocs bool `json:"missingDocs,omitempty"`
+	// MissingDescriptions are the paths of the input properties without a description, such as "settings.tier".
+	MissingDescriptions []string `json:"missingDescriptions,omitempty"`
+	// DescriptionsFromAttributes are the input properties described by an upstream attribute rather than an
+	// argument.
+	DescriptionsFromAttributes []string `json:"descriptionsFromAttributes,omitempty"`
+	ElidedArguments            []string `json:"elidedArguments,omitempty"`
+	IgnoredSections            []string `json:"ignoredSections,omitempty"`
+
+	exampleFindings
+}
+
+// docsReport is the per-entity documentation quality report written by --docs-report.
+type docsReport struct {
+	Resources map[string]*docsReportEntity `json:"resources,omitempty"`
+	Functions map[string]*docsReportEntity `json:"functions,omitempty"`
+	// Other holds the examples findings of types and config, keyed by the token of the example path.
+	Other map[string]*docsReportEntity `json:"other,omitempty"`
+}
+
+// entity returns the report of the entity with the given Pulumi token. m.mu must be held.
+func (m *docsMetrics) entity(token string) *docsReportEntity {
+	e, ok := m.entities[token]
+	if !ok {
+		e = &docsReportEntity{}
+		m.entities[token] = e
+	}
+	return e
+}
+
+// recordEntityDocs records the findings of parsing the docs of the resource or data source rawname, exposed as
+// token.
+func (m *docsMetrics) recordEntityDocs(token, kind, rawname string, docs entityDocs) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f := docs.findings
+	if f.Missing {
+		m.entitiesMissingDocs++
+	}
+	m.totalArgumentsFromDocs += f.ArgumentsFromDocs
+	for _, arg := range f.ElidedArguments {
+		if docsPath(arg).nested() {
+			m.elidedNestedArguments++
+		} else {
+			m.elidedArguments++
+		}
+	}
+	for _, header := range f.IgnoredSections {
+		m.ignoredDocHeaders[header]++
+	}
+
+	e := m.entity(token)
+	e.Kind = kind
+	e.TFName = rawname
+	e.MissingDocs = f.Missing
+	e.ElidedArguments = f.ElidedArguments
+	e.IgnoredSections = f.IgnoredSections
+}
+
+// recordDescriptionFromAttributes records that the input property key of token is described by an upstream
+// attribute.
+func (m *docsMetrics) recordDescriptionFromAttributes(token, key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.argumentDescriptionsFromAttributes++
+	e := m.entity(token)
+	e.DescriptionsFromAttributes = append(e.DescriptionsFromAttributes, key)
+}
+
+// recordSkippedExample records that the examples of docs at path were dropped by ProviderInfo.SkipExamples.
+func (m *docsMetrics) recordSkippedExample(path examplePath, docs string) {
+	if !strings.Contains(docs, "```") {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := m.entity(path.Token())
+	for _, p := range e.SkippedExamples {
+		if p == path.String() {
+			return
+		}
+	}
+	e.SkippedExamples = append(e.SkippedExamples, path.String())
+}
+
+// recordUnconvertedSection records that the section of docs with the header starting at headerStart was dropped,
+// because its examples failed to convert. headerStart is negative if the section has no header.
+func (m *docsMetrics) recordUnconvertedSection(path examplePath, docs string, headerStart int) {
+	section := path.String()
+	if headerStart >= 0 {
+		header := docs[headerStart:]
+		if i := strings.IndexByte(header, '\n'); i >= 0 {
+			header = header[:i]
+		}
+		section += ": " + strings.TrimSpace(strings.TrimLeft(header, "#"))
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := m.entity(path.Token())
+	e.UnconvertedSections = append(e.UnconvertedSections, section)
+}
+
+// recordExampleFindings records the findings of converting the examples of token, reused from the manifest of
+// incremental generation.
+func (m *docsMetrics) recordExampleFindings(token string, findings exampleFindings) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entity(token).exampleFindings = findings
+}
+
+// recordSchema records the statistics of the final schema, including the input properties missing descriptions.
+func (m *docsMetrics) recordSchema(spec pschema.PackageSpec) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.schemaStats = schemaTools.CountStats(spec)
+
+	for token, r := range spec.Resources {
+		m.entity(token).MissingDescriptions = missingDescriptions(spec, r.InputProperties)
+	}
+	for token, f := range spec.Functions {
+		if f.Inputs != nil {
+			m.entity(token).MissingDescriptions = missingDescriptions(spec, f.Inputs.Properties)
+		}
+	}
+}
+
+// missingDescriptions returns the sorted paths of the properties, including the properties of nested object types,
+// which lack a description.
+func missingDescriptions(spec pschema.PackageSpec, props map[string]pschema.PropertySpec) []string {
+	var result []string
+	var visit func(prefix string, props map[string]pschema.PropertySpec, seen map[string]bool)
+	var visitType func(path string, t pschema.TypeSpec, seen map[string]bool)
+	visit = func(prefix string, props map[string]pschema.PropertySpec, seen map[string]bool) {
+		for name, p := range props {
+			path := prefix + name
+			if p.Description == "" {
+				result = append(result, path)
+			}
+			visitType(path, p.TypeSpec, seen)
+		}
+	}
+	visitType = func(path string, t pschema.TypeSpec, seen map[string]bool) {
+		switch {
+		case t.Items != nil:
+			visitType(path, *t.Items, seen)
+		case t.AdditionalProperties != nil:
+			visitType(path, *t.AdditionalProperties, seen)
+		case strings.HasPrefix(t.Ref, "#/types/"):
+			token := strings.TrimPrefix(t.Ref, "#/types/")
+			obj, ok := spec.Types[token]
+			if !ok || seen[token] {
+				return
+			}
+			nested := map[string]bool{token: true}
+			for k := range seen {
+				nested[k] = true
+			}
+			visit(path+".", obj.Properties, nested)
+		}
+	}
+	visit("", props, map[string]bool{})
+	sort.Strings(result)
+	return result
+}
+
+// report returns the report of the resources and functions of spec, with every list sorted.
+func (m *docsMetrics) report(spec pschema.PackageSpec) docsReport {
+	report := docsReport{
+		Resources: map[string]*docsReportEntity{},
+		Functions: map[string]*docsReportEntity{},
+		Other:     map[string]*docsReportEntity{},
+	}
+	for token, e := range m.entities {
+		for _, list := range [][]string{
+			e.DescriptionsFromAttributes, e.ElidedArguments, e.IgnoredSections,
+			e.UnconvertedSections, e.SkippedExamples,
+		} {
+			sort.Strings(list)
+		}
+		if _, ok := spec.Resources[token]; ok {
+			report.Resources[token] = e
+		} else if _, ok := spec.Functions[token]; ok {
+			report.Functions[token] = e
+		} else if len(e.UnconvertedSections) > 0 || len(e.SkippedExamples) > 0 {
+			report.Other[token] = e
+		}
+	}
+	return report
+}
+
+// writeReport writes the docs report of spec as JSON to path.
+func (m *docsMetrics) writeReport(path string, spec pschema.PackageSpec) error {
+	bytes, err := json.MarshalIndent(m.report(spec), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(bytes, '\n'), 0o600)
+}
+
+// print outputs metrics relating to document parsing and conversion
+func (m *docsMetrics) print() {
+	schemaStats := m.schemaStats
+
 	fmt.Println("General metrics:")
 	fmt.Printf("\t%d total resources containing %d total inputs.\n",
 		schemaStats.Resources.TotalResources, schemaStats.Resources.TotalInputProperties)
 	fmt.Printf("\t%d total functions.\n", schemaStats.Functions.TotalFunctions)
-	if entitiesMissingDocs > 0 {
+	if m.entitiesMissingDocs > 0 {
 		fmt.Printf("\t%d entities are missing docs entirely because they could not be found in the upstream provider.\n",
-			entitiesMissingDocs)
-	}
-	if unexpectedSnippets > 0 {
-		fmt.Printf("\t%d entity document sections contained unexpected HCL code snippets. Examples will be converted, "+
-			"but may not display correctly in the registry, e.g. lacking tabs.\n", unexpectedSnippets)
+			m.entitiesMissingDocs)
 	}
 	fmt.Println("")
 
 	fmt.Println("Argument metrics:")
-	fmt.Printf("\t%d argument descriptions were parsed from the upstream docs\n", totalArgumentsFromDocs)
+	fmt.Printf("\t%d argument descriptions were parsed from the upstream docs\n", m.totalArgumentsFromDocs)
 	fmt.Printf("\t%d top-level input property descriptions came from an upstream attribute (as opposed to an argument). "+
-		"Nested arguments are not included in this count.\n", argumentDescriptionsFromAttributes)
-	if elidedArguments > 0 || elidedNestedArguments > 0 {
+		"Nested arguments are not included in this count.\n", m.argumentDescriptionsFromAttributes)
+	if m.elidedArguments > 0 || m.elidedNestedArguments > 0 {
 		fmt.Printf("\t%d arguments contained an <elided> reference and had their descriptions dropped.\n",
-			elidedArguments)
+			m.elidedArguments)
 		fmt.Printf("\t%d nested arguments contained an <elided> reference and had their descriptions dropped.\n",
-			elidedNestedArguments)
+			m.elidedNestedArguments)
 	}
 	fmt.Printf(
 		"\t%d of %d resource inputs (%.2f%%) are missing descriptions in the schema\n",
@@ -0,0 +1,118 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pschema "github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfgen/internal/testprovider"
+)
+
+func TestDocsReport(t *testing.T) {
+	dir := t.TempDir()
+	source := &countingDocsSource{resources: map[string]string{
+		"random_integer": "# random_integer\n\nGenerates a random integer.\n\n" +
+			"## Example Usage\n\n```hcl\nresource \"random_integer\" \"example\" {}\n```\n\n" +
+			"## Argument Reference\n\n" +
+			"* `min` - (Required) The minimum value.\n" +
+			"* `seed` - (Optional) A seed, as in Terraform.\n\n" +
+			"## Attributes Reference\n\n* `max` - The maximum value.\n\n" +
+			"## Timeouts\n\nNone.\n",
+	}}
+
+	generate := func(t *testing.T, manifest string) []byte {
+		info := testprovider.ProviderMiniRandom()
+		info.SkipExamples = func(tfbridge.SkipExamplesArgs) bool { return true }
+		reportPath := filepath.Join(t.TempDir(), "report.json")
+		g, err := NewGenerator(GeneratorOptions{
+			Package:      info.Name,
+			Version:      info.Version,
+			Language:     Schema,
+			ProviderInfo: info,
+			Root:         afero.NewMemMapFs(),
+			Sink:         diag.DefaultSink(io.Discard, io.Discard, diag.FormatOptions{Color: colors.Never}),
+			DocsSource:   source,
+			Manifest:     manifest,
+			DocsReport:   reportPath,
+		})
+		require.NoError(t, err)
+		require.NoError(t, g.Generate())
+		report, err := os.ReadFile(reportPath)
+		require.NoError(t, err)
+		return report
+	}
+
+	expected := generate(t, "")
+	var report docsReport
+	require.NoError(t, json.Unmarshal(expected, &report))
+	assert.Equal(t, &docsReportEntity{
+		Kind:                       "resource",
+		TFName:                     "random_integer",
+		DescriptionsFromAttributes: []string{"max"},
+		ElidedArguments:            []string{"seed"},
+		IgnoredSections:            []string{"Timeouts"},
+		exampleFindings: exampleFindings{
+			SkippedExamples: []string{"#/resources/random:index/randomInteger:RandomInteger"},
+		},
+	}, report.Resources["random:index/randomInteger:RandomInteger"])
+
+	t.Run("deterministic", func(t *testing.T) {
+		assert.Equal(t, string(expected), string(generate(t, "")))
+	})
+
+	t.Run("manifest", func(t *testing.T) {
+		manifest := filepath.Join(dir, "manifest.json")
+		assert.Equal(t, string(expected), string(generate(t, manifest)))
+		// The findings of the reused docs and examples are restored from the manifest.
+		assert.Equal(t, string(expected), string(generate(t, manifest)))
+	})
+}
+
+func TestMissingDescriptions(t *testing.T) {
+	t.Parallel()
+	spec := pschema.PackageSpec{
+		Types: map[string]pschema.ComplexTypeSpec{
+			"test:index:Settings": {ObjectTypeSpec: pschema.ObjectTypeSpec{
+				Properties: map[string]pschema.PropertySpec{
+					"tier": {TypeSpec: pschema.TypeSpec{Type: "string"}},
+					"parent": {
+						Description: "The parent settings.",
+						TypeSpec:    pschema.TypeSpec{Ref: "#/types/test:index:Settings"},
+					},
+				},
+			}},
+		},
+	}
+	props := map[string]pschema.PropertySpec{
+		"name": {Description: "The name.", TypeSpec: pschema.TypeSpec{Type: "string"}},
+		"settings": {TypeSpec: pschema.TypeSpec{
+			Type:  "array",
+			Items: &pschema.TypeSpec{Ref: "#/types/test:index:Settings"},
+		}},
+	}
+	assert.Equal(t, []string{"settings", "settings.tier"}, missingDescriptions(spec, props))
+}
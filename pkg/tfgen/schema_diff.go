@@ -0,0 +1,344 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	pschema "github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+)
+
+// SchemaChangeKind classifies a SchemaChange.
+type SchemaChangeKind string
+
+const (
+	ResourceAdded          SchemaChangeKind = "resource-added"
+	ResourceRemoved        SchemaChangeKind = "resource-removed"
+	FunctionAdded          SchemaChangeKind = "function-added"
+	FunctionRemoved        SchemaChangeKind = "function-removed"
+	TypeAdded              SchemaChangeKind = "type-added"
+	TypeRemoved            SchemaChangeKind = "type-removed"
+	PropertyAdded          SchemaChangeKind = "property-added"
+	PropertyRemoved        SchemaChangeKind = "property-removed"
+	PropertyTypeChanged    SchemaChangeKind = "property-type-changed"
+	PropertyMaxItemsOne    SchemaChangeKind = "property-max-items-one-changed"
+	PropertyBecameRequired SchemaChangeKind = "property-became-required"
+	PropertyBecameOptional SchemaChangeKind = "property-became-optional"
+)
+
+// SchemaChange is a single difference between two versions of a Pulumi package schema.
+type SchemaChange struct {
+	// Path locates the changed element, such as "resources/aws:s3/bucket:Bucket/inputProperties/acl".
+	Path string           `json:"path"`
+	Kind SchemaChangeKind `json:"kind"`
+	// Old and New describe the changed element before and after the change, if applicable.
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+	// Breaks lists the SDK languages in which the change may break programs that compile against the old SDK.
+	Breaks []Language `json:"breaks,omitempty"`
+}
+
+// Breaking returns true if the change breaks the SDK of any language.
+func (c SchemaChange) Breaking() bool { return len(c.Breaks) > 0 }
+
+// SchemaDiff is the report of the changes between two versions of a Pulumi package schema.
+type SchemaDiff struct {
+	Changes []SchemaChange `json:"changes"`
+}
+
+// Breaking returns the changes that break the SDK of lang.
+func (d *SchemaDiff) Breaking(lang Language) []SchemaChange {
+	var breaking []SchemaChange
+	for _, c := range d.Changes {
+		if sliceContains(c.Breaks, lang) {
+			breaking = append(breaking, c)
+		}
+	}
+	return breaking
+}
+
+// HasBreakingChanges returns true if any change breaks the SDK of any language.
+func (d *SchemaDiff) HasBreakingChanges() bool {
+	for _, c := range d.Changes {
+		if c.Breaking() {
+			return true
+		}
+	}
+	return false
+}
+
+// Markdown renders the report for humans, breaking changes first.
+func (d *SchemaDiff) Markdown() string {
+	var breaking, nonBreaking []string
+	for _, c := range d.Changes {
+		line := fmt.Sprintf("- `%s`: %s", c.Path, c.describe())
+		if c.Breaking() {
+			langs := make([]string, len(c.Breaks))
+			for i, l := range c.Breaks {
+				langs[i] = string(l)
+			}
+			breaking = append(breaking, fmt.Sprintf("%s (breaks %s)", line, strings.Join(langs, ", ")))
+		} else {
+			nonBreaking = append(nonBreaking, line)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("# Schema changes\n")
+	if len(d.Changes) == 0 {
+		sb.WriteString("\nNo changes.\n")
+	}
+	if len(breaking) > 0 {
+		fmt.Fprintf(&sb, "\n## Breaking changes\n\n%s\n", strings.Join(breaking, "\n"))
+	}
+	if len(nonBreaking) > 0 {
+		fmt.Fprintf(&sb, "\n## Non-breaking changes\n\n%s\n", strings.Join(nonBreaking, "\n"))
+	}
+	return sb.String()
+}
+
+func (c SchemaChange) describe() string {
+	switch c.Kind {
+	case ResourceAdded:
+		return "resource added"
+	case ResourceRemoved:
+		return "resource removed"
+	case FunctionAdded:
+		return "function added"
+	case FunctionRemoved:
+		return "function removed"
+	case TypeAdded:
+		return "type added"
+	case TypeRemoved:
+		return "type removed"
+	case PropertyAdded:
+		return fmt.Sprintf("property added with type %s", c.New)
+	case PropertyRemoved:
+		return "property removed"
+	case PropertyTypeChanged:
+		return fmt.Sprintf("type changed from %s to %s", c.Old, c.New)
+	case PropertyMaxItemsOne:
+		return fmt.Sprintf("MaxItemsOne changed, type changed from %s to %s", c.Old, c.New)
+	case PropertyBecameRequired:
+		return "property became required"
+	case PropertyBecameOptional:
+		return "property became optional"
+	default:
+		return string(c.Kind)
+	}
+}
+
+// DiffSchemas reports the changes from the old to the new version of a Pulumi package schema.
+//
+// Each change is classified as breaking or not for each of the SDK languages, from the point of view of programs
+// written against the old SDK. Renamed resources, functions and properties are reported as a removal and an addition.
+func DiffSchemas(old, new pschema.PackageSpec) *SchemaDiff {
+	d := &schemaDiffer{}
+
+	d.properties("config", inputProps,
+		old.Config.Variables, new.Config.Variables, old.Config.Required, new.Config.Required)
+	d.properties("provider/inputProperties", inputProps,
+		old.Provider.InputProperties, new.Provider.InputProperties,
+		old.Provider.RequiredInputs, new.Provider.RequiredInputs)
+
+	for _, tok := range unionKeys(old.Resources, new.Resources) {
+		path := "resources/" + tok
+		o, inOld := old.Resources[tok]
+		n, inNew := new.Resources[tok]
+		switch {
+		case !inOld:
+			d.add(SchemaChange{Path: path, Kind: ResourceAdded})
+		case !inNew:
+			d.add(SchemaChange{Path: path, Kind: ResourceRemoved, Breaks: slices.Clone(AllLanguages)})
+		default:
+			d.properties(path+"/inputProperties", inputProps,
+				o.InputProperties, n.InputProperties, o.RequiredInputs, n.RequiredInputs)
+			d.properties(path+"/properties", outputProps, o.Properties, n.Properties, o.Required, n.Required)
+		}
+	}
+
+	for _, tok := range unionKeys(old.Functions, new.Functions) {
+		path := "functions/" + tok
+		o, inOld := old.Functions[tok]
+		n, inNew := new.Functions[tok]
+		switch {
+		case !inOld:
+			d.add(SchemaChange{Path: path, Kind: FunctionAdded})
+		case !inNew:
+			d.add(SchemaChange{Path: path, Kind: FunctionRemoved, Breaks: slices.Clone(AllLanguages)})
+		default:
+			oi, ni := objectOrEmpty(o.Inputs), objectOrEmpty(n.Inputs)
+			d.properties(path+"/inputs", inputProps, oi.Properties, ni.Properties, oi.Required, ni.Required)
+			oo, no := objectOrEmpty(o.Outputs), objectOrEmpty(n.Outputs)
+			d.properties(path+"/outputs", outputProps, oo.Properties, no.Properties, oo.Required, no.Required)
+		}
+	}
+
+	for _, tok := range unionKeys(old.Types, new.Types) {
+		path := "types/" + tok
+		o, inOld := old.Types[tok]
+		n, inNew := new.Types[tok]
+		switch {
+		case !inOld:
+			d.add(SchemaChange{Path: path, Kind: TypeAdded})
+		case !inNew:
+			d.add(SchemaChange{Path: path, Kind: TypeRemoved, Breaks: slices.Clone(AllLanguages)})
+		default:
+			// Object types may be used as both inputs and outputs, so changes are classified for both.
+			d.properties(path+"/properties", inputProps|outputProps,
+				o.Properties, n.Properties, o.Required, n.Required)
+		}
+	}
+
+	sort.SliceStable(d.changes, func(i, j int) bool { return d.changes[i].Path < d.changes[j].Path })
+	return &SchemaDiff{Changes: d.changes}
+}
+
+// propsUsage tells whether a set of properties is read from (outputs) or written to (inputs) by user programs.
+type propsUsage int
+
+const (
+	inputProps propsUsage = 1 << iota
+	outputProps
+)
+
+type schemaDiffer struct {
+	changes []SchemaChange
+}
+
+func (d *schemaDiffer) add(c SchemaChange) {
+	d.changes = append(d.changes, c)
+}
+
+func (d *schemaDiffer) properties(
+	path string, usage propsUsage,
+	old, new map[string]pschema.PropertySpec,
+	oldRequired, newRequired []string,
+) {
+	for _, name := range unionKeys(old, new) {
+		p := path + "/" + name
+		o, inOld := old[name]
+		n, inNew := new[name]
+		wasRequired, isRequired := sliceContains(oldRequired, name), sliceContains(newRequired, name)
+
+		switch {
+		case !inOld:
+			var breaks []Language
+			if usage&inputProps != 0 && isRequired {
+				// Existing programs do not set the new required input.
+				breaks = slices.Clone(AllLanguages)
+			}
+			d.add(SchemaChange{Path: p, Kind: PropertyAdded, New: typeString(n.TypeSpec), Breaks: breaks})
+			continue
+		case !inNew:
+			d.add(SchemaChange{
+				Path: p, Kind: PropertyRemoved, Old: typeString(o.TypeSpec), Breaks: slices.Clone(AllLanguages),
+			})
+			continue
+		}
+
+		if ot, nt := typeString(o.TypeSpec), typeString(n.TypeSpec); ot != nt {
+			kind := PropertyTypeChanged
+			if isMaxItemsOneFlip(o.TypeSpec, n.TypeSpec) {
+				kind = PropertyMaxItemsOne
+			}
+			d.add(SchemaChange{Path: p, Kind: kind, Old: ot, New: nt, Breaks: typeChangeBreaks(ot, nt)})
+		}
+
+		switch {
+		case !wasRequired && isRequired:
+			var breaks []Language
+			if usage&inputProps != 0 {
+				breaks = slices.Clone(AllLanguages)
+			}
+			d.add(SchemaChange{Path: p, Kind: PropertyBecameRequired, Breaks: breaks})
+		case wasRequired && !isRequired:
+			var breaks []Language
+			if usage&outputProps != 0 {
+				// Outputs become nullable: pointer types in Go and `T | undefined` in TypeScript.
+				breaks = []Language{Golang, NodeJS}
+			}
+			if usage&inputProps != 0 && !sliceContains(breaks, Golang) {
+				// Go uses distinct Input and PtrInput types for required and optional inputs.
+				breaks = append(breaks, Golang)
+			}
+			d.add(SchemaChange{Path: p, Kind: PropertyBecameOptional, Breaks: breaks})
+		}
+	}
+}
+
+// typeChangeBreaks returns the languages broken by a property changing type. Any change breaks the typed SDKs; the
+// one exception is integers widening to numbers, which TypeScript and Python do not distinguish in practice.
+func typeChangeBreaks(old, new string) []Language {
+	if old == "integer" && new == "number" {
+		return []Language{Golang, CSharp, Java}
+	}
+	return slices.Clone(AllLanguages)
+}
+
+// isMaxItemsOneFlip detects a property changing between a list of T and T, as happens when MaxItemsOne is toggled.
+func isMaxItemsOneFlip(old, new pschema.TypeSpec) bool {
+	if old.Type == "array" && old.Items != nil {
+		return typeString(*old.Items) == typeString(new)
+	}
+	if new.Type == "array" && new.Items != nil {
+		return typeString(*new.Items) == typeString(old)
+	}
+	return false
+}
+
+// typeString renders a TypeSpec for comparison and display.
+func typeString(t pschema.TypeSpec) string {
+	switch {
+	case t.Ref != "":
+		return t.Ref
+	case len(t.OneOf) > 0:
+		parts := make([]string, len(t.OneOf))
+		for i, o := range t.OneOf {
+			parts[i] = typeString(o)
+		}
+		return "oneOf(" + strings.Join(parts, ", ") + ")"
+	case t.Type == "array" && t.Items != nil:
+		return "array<" + typeString(*t.Items) + ">"
+	case t.Type == "object" && t.AdditionalProperties != nil:
+		return "map<" + typeString(*t.AdditionalProperties) + ">"
+	default:
+		return t.Type
+	}
+}
+
+func objectOrEmpty(o *pschema.ObjectTypeSpec) pschema.ObjectTypeSpec {
+	if o == nil {
+		return pschema.ObjectTypeSpec{}
+	}
+	return *o
+}
+
+func unionKeys[T any](a, b map[string]T) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
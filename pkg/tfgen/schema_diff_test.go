@@ -0,0 +1,187 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"testing"
+
+	pschema "github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSchemas(t *testing.T) {
+	str := pschema.PropertySpec{TypeSpec: pschema.TypeSpec{Type: "string"}}
+	integer := pschema.PropertySpec{TypeSpec: pschema.TypeSpec{Type: "integer"}}
+	number := pschema.PropertySpec{TypeSpec: pschema.TypeSpec{Type: "number"}}
+	ref := pschema.TypeSpec{Ref: "#/types/test:index:Block"}
+	block := pschema.PropertySpec{TypeSpec: ref}
+	blocks := pschema.PropertySpec{TypeSpec: pschema.TypeSpec{Type: "array", Items: &ref}}
+
+	resource := func(inputs map[string]pschema.PropertySpec, required []string) pschema.ResourceSpec {
+		return pschema.ResourceSpec{
+			ObjectTypeSpec: pschema.ObjectTypeSpec{
+				Properties: inputs,
+				Required:   required,
+			},
+			InputProperties: inputs,
+			RequiredInputs:  required,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		old, new map[string]pschema.PropertySpec
+		oldReq   []string
+		newReq   []string
+		expected []SchemaChange
+	}{
+		{
+			name: "no changes",
+			old:  map[string]pschema.PropertySpec{"name": str},
+			new:  map[string]pschema.PropertySpec{"name": str},
+		},
+		{
+			name: "optional property added",
+			old:  map[string]pschema.PropertySpec{},
+			new:  map[string]pschema.PropertySpec{"name": str},
+			expected: []SchemaChange{
+				{Path: "resources/test:index:Res/inputProperties/name", Kind: PropertyAdded, New: "string"},
+				{Path: "resources/test:index:Res/properties/name", Kind: PropertyAdded, New: "string"},
+			},
+		},
+		{
+			name:   "required property added",
+			old:    map[string]pschema.PropertySpec{},
+			new:    map[string]pschema.PropertySpec{"name": str},
+			newReq: []string{"name"},
+			expected: []SchemaChange{
+				{
+					Path: "resources/test:index:Res/inputProperties/name", Kind: PropertyAdded, New: "string",
+					Breaks: AllLanguages,
+				},
+				{Path: "resources/test:index:Res/properties/name", Kind: PropertyAdded, New: "string"},
+			},
+		},
+		{
+			name: "property removed",
+			old:  map[string]pschema.PropertySpec{"name": str},
+			new:  map[string]pschema.PropertySpec{},
+			expected: []SchemaChange{
+				{
+					Path: "resources/test:index:Res/inputProperties/name", Kind: PropertyRemoved, Old: "string",
+					Breaks: AllLanguages,
+				},
+				{
+					Path: "resources/test:index:Res/properties/name", Kind: PropertyRemoved, Old: "string",
+					Breaks: AllLanguages,
+				},
+			},
+		},
+		{
+			name:   "property became optional",
+			old:    map[string]pschema.PropertySpec{"name": str},
+			new:    map[string]pschema.PropertySpec{"name": str},
+			oldReq: []string{"name"},
+			expected: []SchemaChange{
+				{
+					Path: "resources/test:index:Res/inputProperties/name", Kind: PropertyBecameOptional,
+					Breaks: []Language{Golang},
+				},
+				{
+					Path: "resources/test:index:Res/properties/name", Kind: PropertyBecameOptional,
+					Breaks: []Language{Golang, NodeJS},
+				},
+			},
+		},
+		{
+			name: "integer widened to number",
+			old:  map[string]pschema.PropertySpec{"count": integer},
+			new:  map[string]pschema.PropertySpec{"count": number},
+			expected: []SchemaChange{
+				{
+					Path: "resources/test:index:Res/inputProperties/count", Kind: PropertyTypeChanged,
+					Old: "integer", New: "number", Breaks: []Language{Golang, CSharp, Java},
+				},
+				{
+					Path: "resources/test:index:Res/properties/count", Kind: PropertyTypeChanged,
+					Old: "integer", New: "number", Breaks: []Language{Golang, CSharp, Java},
+				},
+			},
+		},
+		{
+			name: "MaxItemsOne flipped",
+			old:  map[string]pschema.PropertySpec{"block": blocks},
+			new:  map[string]pschema.PropertySpec{"block": block},
+			expected: []SchemaChange{
+				{
+					Path: "resources/test:index:Res/inputProperties/block", Kind: PropertyMaxItemsOne,
+					Old: "array<#/types/test:index:Block>", New: "#/types/test:index:Block", Breaks: AllLanguages,
+				},
+				{
+					Path: "resources/test:index:Res/properties/block", Kind: PropertyMaxItemsOne,
+					Old: "array<#/types/test:index:Block>", New: "#/types/test:index:Block", Breaks: AllLanguages,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			old := pschema.PackageSpec{Resources: map[string]pschema.ResourceSpec{
+				"test:index:Res": resource(tt.old, tt.oldReq),
+			}}
+			new := pschema.PackageSpec{Resources: map[string]pschema.ResourceSpec{
+				"test:index:Res": resource(tt.new, tt.newReq),
+			}}
+			diff := DiffSchemas(old, new)
+			assert.Equal(t, tt.expected, diff.Changes)
+		})
+	}
+}
+
+func TestDiffSchemasEntities(t *testing.T) {
+	old := pschema.PackageSpec{
+		Resources: map[string]pschema.ResourceSpec{"test:index:Old": {}},
+		Functions: map[string]pschema.FunctionSpec{"test:index:getOld": {}},
+	}
+	new := pschema.PackageSpec{
+		Resources: map[string]pschema.ResourceSpec{"test:index:New": {}},
+		Functions: map[string]pschema.FunctionSpec{"test:index:getNew": {}},
+	}
+
+	diff := DiffSchemas(old, new)
+	assert.Equal(t, []SchemaChange{
+		{Path: "functions/test:index:getNew", Kind: FunctionAdded},
+		{Path: "functions/test:index:getOld", Kind: FunctionRemoved, Breaks: AllLanguages},
+		{Path: "resources/test:index:New", Kind: ResourceAdded},
+		{Path: "resources/test:index:Old", Kind: ResourceRemoved, Breaks: AllLanguages},
+	}, diff.Changes)
+	assert.True(t, diff.HasBreakingChanges())
+	assert.Len(t, diff.Breaking(Python), 2)
+
+	assert.Equal(t, "# Schema changes\n"+
+		"\n## Breaking changes\n\n"+
+		"- `functions/test:index:getOld`: function removed (breaks go, nodejs, python, dotnet, java)\n"+
+		"- `resources/test:index:Old`: resource removed (breaks go, nodejs, python, dotnet, java)\n"+
+		"\n## Non-breaking changes\n\n"+
+		"- `functions/test:index:getNew`: function added\n"+
+		"- `resources/test:index:New`: resource added\n", diff.Markdown())
+
+	// Changes own their Breaks, so editing one must not leak into AllLanguages or other changes.
+	diff.Changes[1].Breaks[0] = Java
+	assert.Equal(t, Golang, AllLanguages[0])
+	assert.Equal(t, Golang, diff.Changes[3].Breaks[0])
+}
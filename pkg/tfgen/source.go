@@ -15,24 +15,33 @@
 package tfgen
 
 import (
+	"archive/tar"
+	"compress/gzip"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"sync"
 
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/spf13/afero"
+
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
 )
 
 // A source of documentation bytes.
+//
+// The source used by a Generator is selected through [GeneratorOptions.DocsSource]. When none is given, docs are read
+// from the upstream provider repository, see [NewGitRepoDocsSource].
 type DocsSource interface {
-	// Get the bytes for a resource with TF token rawname.
-	getResource(rawname string, info *tfbridge.DocInfo) (*DocFile, error)
+	// Get the bytes for a resource with TF token rawname. Returns nil if there are no docs for rawname.
+	GetResource(rawname string, info *tfbridge.DocInfo) (*DocFile, error)
 
-	// Get the bytes for a datasource with TF token rawname.
-	getDatasource(rawname string, info *tfbridge.DocInfo) (*DocFile, error)
+	// Get the bytes for a datasource with TF token rawname. Returns nil if there are no docs for rawname.
+	GetDatasource(rawname string, info *tfbridge.DocInfo) (*DocFile, error)
 }
 
 type DocFile struct {
@@ -40,33 +49,33 @@ type DocFile struct {
 	FileName string
 }
 
+// NewGitRepoDocsSource returns a DocsSource reading the docs of the upstream provider repository. The repository is
+// located at ProviderInfo.UpstreamRepoPath, or else resolved through the Go module cache.
 func NewGitRepoDocsSource(g *Generator) DocsSource {
 	return &gitRepoSource{
-		docRules:              g.info.DocRules,
+		docNames:              newDocNames(g.info),
 		upstreamRepoPath:      g.info.UpstreamRepoPath,
 		org:                   g.info.GetGitHubOrg(),
 		provider:              g.info.Name,
-		resourcePrefix:        g.info.GetResourcePrefix(),
 		providerModuleVersion: g.info.GetProviderModuleVersion(),
 		githost:               g.info.GetGitHubHost(),
 	}
 }
 
 type gitRepoSource struct {
-	docRules              *tfbridge.DocRuleInfo
+	docNames
 	upstreamRepoPath      string
 	org                   string
 	provider              string
-	resourcePrefix        string
 	providerModuleVersion string
 	githost               string
 }
 
-func (gh *gitRepoSource) getResource(rawname string, info *tfbridge.DocInfo) (*DocFile, error) {
+func (gh *gitRepoSource) GetResource(rawname string, info *tfbridge.DocInfo) (*DocFile, error) {
 	return gh.getFile(rawname, info, ResourceDocs)
 }
 
-func (gh *gitRepoSource) getDatasource(rawname string, info *tfbridge.DocInfo) (*DocFile, error) {
+func (gh *gitRepoSource) GetDatasource(rawname string, info *tfbridge.DocInfo) (*DocFile, error) {
 	return gh.getFile(rawname, info, DataSourceDocs)
 }
 
@@ -87,13 +96,121 @@ func (gh *gitRepoSource) getFile(
 		}
 	}
 
-	possibleMarkdownNames := getMarkdownNames(gh.resourcePrefix, rawname, gh.docRules)
+	return readMarkdown(afero.NewOsFs(), repoPath, kind, gh.names(rawname, info))
+}
+
+// NewLocalDocsSource returns a DocsSource reading docs from dir, a local directory laid out like the upstream provider
+// repository: docs are found under docs/resources and docs/data-sources, or the legacy website/docs/r and
+// website/docs/d.
+func NewLocalDocsSource(info tfbridge.ProviderInfo, dir string) DocsSource {
+	return &fsDocsSource{docNames: newDocNames(info), fs: afero.NewOsFs(), root: dir}
+}
+
+// NewArchiveDocsSource returns a DocsSource reading docs from the .tar.gz bundle at path. The bundle is laid out like
+// the directory of [NewLocalDocsSource], either at its root or under a single top-level directory.
+func NewArchiveDocsSource(info tfbridge.ProviderInfo, path string) (DocsSource, error) {
+	fs, err := extractTarball(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading docs archive %s: %w", path, err)
+	}
+	return &fsDocsSource{docNames: newDocNames(info), fs: fs, root: archiveDocsRoot(fs)}, nil
+}
+
+// fsDocsSource reads docs from a directory tree laid out like the upstream provider repository.
+type fsDocsSource struct {
+	docNames
+	fs   afero.Fs
+	root string
+}
+
+func (s *fsDocsSource) GetResource(rawname string, info *tfbridge.DocInfo) (*DocFile, error) {
+	return s.getFile(rawname, info, ResourceDocs)
+}
+
+func (s *fsDocsSource) GetDatasource(rawname string, info *tfbridge.DocInfo) (*DocFile, error) {
+	return s.getFile(rawname, info, DataSourceDocs)
+}
+
+func (s *fsDocsSource) getFile(rawname string, info *tfbridge.DocInfo, kind DocKind) (*DocFile, error) {
+	if info != nil && len(info.Markdown) != 0 {
+		return &DocFile{Content: info.Markdown}, nil
+	}
+	return readMarkdown(s.fs, s.root, kind, s.names(rawname, info))
+}
+
+func extractTarball(path string) (afero.Fs, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer contract.IgnoreClose(f)
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer contract.IgnoreClose(gz)
+
+	fs := afero.NewMemMapFs()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return fs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		name := filepath.Join("/", filepath.FromSlash(hdr.Name))
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = fs.MkdirAll(name, 0700)
+		case tar.TypeReg:
+			var contents []byte
+			if contents, err = io.ReadAll(tr); err == nil {
+				err = emitFile(fs, name, contents)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", hdr.Name, err)
+		}
+	}
+}
+
+// archiveDocsRoot returns the directory of fs that holds the docs: the root itself, or else its only subdirectory, as
+// produced by archiving a checkout of the upstream repository.
+func archiveDocsRoot(fs afero.Fs) string {
+	root := string(filepath.Separator)
+	if paths, err := getDocsPath(fs, root, ResourceDocs); err != nil || len(paths) > 0 {
+		return root
+	}
+	if paths, err := getDocsPath(fs, root, DataSourceDocs); err != nil || len(paths) > 0 {
+		return root
+	}
+	entries, err := afero.ReadDir(fs, root)
+	if err != nil || len(entries) != 1 || !entries[0].IsDir() {
+		return root
+	}
+	return filepath.Join(root, entries[0].Name())
+}
+
+// docNames computes the markdown file names that may hold the docs of a TF entity.
+type docNames struct {
+	docRules       *tfbridge.DocRuleInfo
+	resourcePrefix string
+}
+
+func newDocNames(info tfbridge.ProviderInfo) docNames {
+	return docNames{docRules: info.DocRules, resourcePrefix: info.GetResourcePrefix()}
+}
+
+func (n docNames) names(rawname string, info *tfbridge.DocInfo) []string {
+	possibleMarkdownNames := getMarkdownNames(n.resourcePrefix, rawname, n.docRules)
 
 	if info != nil && info.Source != "" {
 		possibleMarkdownNames = append(possibleMarkdownNames, info.Source)
 	}
-
-	return readMarkdown(repoPath, kind, possibleMarkdownNames)
+	return possibleMarkdownNames
 }
 
 // An error that represents a missing repo path directory.
@@ -192,8 +309,8 @@ func getMarkdownNames(packagePrefix, rawName string, globalInfo *tfbridge.DocRul
 }
 
 // readMarkdown searches all possible locations for the markdown content
-func readMarkdown(repo string, kind DocKind, possibleLocations []string) (*DocFile, error) {
-	locationPrefix, err := getDocsPath(repo, kind)
+func readMarkdown(fs afero.Fs, repo string, kind DocKind, possibleLocations []string) (*DocFile, error) {
+	locationPrefix, err := getDocsPath(fs, repo, kind)
 	if err != nil {
 		return nil, fmt.Errorf("could not gather location prefix for %q: %w", repo, err)
 	}
@@ -201,7 +318,7 @@ func readMarkdown(repo string, kind DocKind, possibleLocations []string) (*DocFi
 	for _, prefix := range locationPrefix {
 		for _, name := range possibleLocations {
 			location := filepath.Join(prefix, name)
-			markdownBytes, err := os.ReadFile(location)
+			markdownBytes, err := afero.ReadFile(fs, location)
 			if err == nil {
 				return &DocFile{markdownBytes, name}, nil
 			} else if !os.IsNotExist(err) && !errors.Is(err, &os.PathError{}) {
@@ -221,10 +338,10 @@ func readMarkdown(repo string, kind DocKind, possibleLocations []string) (*DocFi
 
 // getDocsPath finds the correct docs path for the repo/kind
 // add the legacy path first since the terraform registry docs also pick those first
-func getDocsPath(repo string, kind DocKind) ([]string, error) {
+func getDocsPath(fs afero.Fs, repo string, kind DocKind) ([]string, error) {
 	var err error
 	exists := func(p string) bool {
-		_, sErr := os.Stat(p)
+		_, sErr := fs.Stat(p)
 		if sErr == nil {
 			return true
 		} else if os.IsNotExist(sErr) {
@@ -0,0 +1,246 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+)
+
+// NewRegistryDocsSource returns a DocsSource reading docs from JSON snapshots.
+//
+// docsPath holds the provider docs as served by the Terraform Registry v2 API, that is a JSON:API document whose
+// data is a list of provider-docs with a category, slug, path and content. schemaPath holds the output of
+// `terraform providers schema -json`. Entities missing from the registry docs are documented from the descriptions
+// in the schema. Either path may be empty.
+func NewRegistryDocsSource(info tfbridge.ProviderInfo, docsPath, schemaPath string) (DocsSource, error) {
+	s := &registryDocsSource{
+		docNames: newDocNames(info),
+		docs:     map[DocKind]map[string]*DocFile{},
+	}
+
+	if docsPath != "" {
+		var docs registryDocs
+		if err := readJSONFile(docsPath, &docs); err != nil {
+			return nil, err
+		}
+		for _, d := range docs.Data {
+			s.addDoc(d.Attributes)
+		}
+	}
+
+	if schemaPath != "" {
+		var schemas tfProviderSchemas
+		if err := readJSONFile(schemaPath, &schemas); err != nil {
+			return nil, err
+		}
+		schema, err := schemas.find(info.Name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", schemaPath, err)
+		}
+		s.schema = schema
+	}
+
+	return s, nil
+}
+
+type registryDocsSource struct {
+	docNames
+	docs   map[DocKind]map[string]*DocFile // by file name
+	schema *tfProviderSchema
+}
+
+func (s *registryDocsSource) GetResource(rawname string, info *tfbridge.DocInfo) (*DocFile, error) {
+	return s.getFile(rawname, info, ResourceDocs)
+}
+
+func (s *registryDocsSource) GetDatasource(rawname string, info *tfbridge.DocInfo) (*DocFile, error) {
+	return s.getFile(rawname, info, DataSourceDocs)
+}
+
+func (s *registryDocsSource) getFile(rawname string, info *tfbridge.DocInfo, kind DocKind) (*DocFile, error) {
+	if info != nil && len(info.Markdown) != 0 {
+		return &DocFile{Content: info.Markdown}, nil
+	}
+
+	for _, name := range s.names(rawname, info) {
+		if doc, ok := s.docs[kind][name]; ok {
+			return doc, nil
+		}
+	}
+
+	if s.schema == nil {
+		return nil, nil
+	}
+	blocks := s.schema.ResourceSchemas
+	if kind == DataSourceDocs {
+		blocks = s.schema.DataSourceSchemas
+	}
+	if block, ok := blocks[rawname]; ok && block.Block != nil {
+		return &DocFile{
+			Content:  []byte(block.Block.markdown(rawname)),
+			FileName: rawname + ".md",
+		}, nil
+	}
+	return nil, nil
+}
+
+func (s *registryDocsSource) addDoc(doc registryDoc) {
+	kind := DocKind(doc.Category)
+	if kind != ResourceDocs && kind != DataSourceDocs {
+		return
+	}
+	if s.docs[kind] == nil {
+		s.docs[kind] = map[string]*DocFile{}
+	}
+
+	names := []string{doc.Slug + ".md"}
+	if doc.Path != "" {
+		names = append([]string{path.Base(doc.Path)}, names...)
+	}
+	file := &DocFile{Content: []byte(doc.Content), FileName: names[0]}
+	for _, name := range names {
+		if _, ok := s.docs[kind][name]; !ok {
+			s.docs[kind][name] = file
+		}
+	}
+}
+
+func readJSONFile(path string, v any) error {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(bytes, v); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// registryDocs is the response of the Terraform Registry for /v2/provider-docs.
+type registryDocs struct {
+	Data []struct {
+		Attributes registryDoc `json:"attributes"`
+	} `json:"data"`
+}
+
+type registryDoc struct {
+	Category string `json:"category"`
+	Slug     string `json:"slug"`
+	Path     string `json:"path"`
+	Content  string `json:"content"`
+}
+
+// tfProviderSchemas is the output of `terraform providers schema -json`.
+type tfProviderSchemas struct {
+	ProviderSchemas map[string]*tfProviderSchema `json:"provider_schemas"`
+}
+
+// find returns the schema of the provider with the given name, matched against the last segment of the provider
+// source address. If the output holds a single provider, it is returned regardless of its name.
+func (s tfProviderSchemas) find(name string) (*tfProviderSchema, error) {
+	if len(s.ProviderSchemas) == 1 {
+		for _, schema := range s.ProviderSchemas {
+			return schema, nil
+		}
+	}
+	for addr, schema := range s.ProviderSchemas {
+		if path.Base(addr) == name {
+			return schema, nil
+		}
+	}
+	return nil, fmt.Errorf("no schema found for provider %q", name)
+}
+
+type tfProviderSchema struct {
+	ResourceSchemas   map[string]*tfSchema `json:"resource_schemas"`
+	DataSourceSchemas map[string]*tfSchema `json:"data_source_schemas"`
+}
+
+type tfSchema struct {
+	Block *tfBlock `json:"block"`
+}
+
+type tfBlock struct {
+	Attributes  map[string]*tfAttribute `json:"attributes"`
+	BlockTypes  map[string]*tfBlockType `json:"block_types"`
+	Description string                  `json:"description"`
+}
+
+type tfAttribute struct {
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+	Optional    bool   `json:"optional"`
+	Computed    bool   `json:"computed"`
+}
+
+type tfBlockType struct {
+	Block    *tfBlock `json:"block"`
+	MinItems int      `json:"min_items"`
+}
+
+// markdown renders the top-level attributes and blocks of b in the layout of the upstream docs, so that it can be
+// parsed like any other markdown docs.
+func (b *tfBlock) markdown(rawname string) string {
+	var args, attrs []string
+	for _, name := range sortedKeys(b.Attributes) {
+		a := b.Attributes[name]
+		switch {
+		case a.Required:
+			args = append(args, fmt.Sprintf("* `%s` - (Required) %s", name, a.Description))
+		case a.Optional:
+			args = append(args, fmt.Sprintf("* `%s` - (Optional) %s", name, a.Description))
+		default:
+			attrs = append(attrs, fmt.Sprintf("* `%s` - %s", name, a.Description))
+		}
+	}
+	for _, name := range sortedKeys(b.BlockTypes) {
+		bt := b.BlockTypes[name]
+		var desc string
+		if bt.Block != nil {
+			desc = bt.Block.Description
+		}
+		req := "Optional"
+		if bt.MinItems > 0 {
+			req = "Required"
+		}
+		args = append(args, fmt.Sprintf("* `%s` - (%s) %s", name, req, desc))
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n%s\n", rawname, b.Description)
+	if len(args) > 0 {
+		fmt.Fprintf(&sb, "\n## Argument Reference\n\n%s\n", strings.Join(args, "\n"))
+	}
+	if len(attrs) > 0 {
+		fmt.Fprintf(&sb, "\n## Attribute Reference\n\n%s\n", strings.Join(attrs, "\n"))
+	}
+	return sb.String()
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
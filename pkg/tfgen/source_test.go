@@ -15,12 +15,17 @@
 package tfgen
 
 import (
+	"archive/tar"
+	"compress/gzip"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
 )
 
 func TestGetDocsPath(t *testing.T) {
@@ -83,11 +88,127 @@ func TestGetDocsPath(t *testing.T) {
 
 			}
 
-			actualResource, err := getDocsPath(repo, ResourceDocs)
+			actualResource, err := getDocsPath(afero.NewOsFs(), repo, ResourceDocs)
 			check(tt.expectedResource, actualResource, err)
 
-			actualDataSource, err := getDocsPath(repo, DataSourceDocs)
+			actualDataSource, err := getDocsPath(afero.NewOsFs(), repo, DataSourceDocs)
 			check(tt.expectedDataSource, actualDataSource, err)
 		})
 	}
 }
+
+func TestLocalDocsSource(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	p := filepath.Join(dir, "docs", "resources", "thing.md")
+	require.NoError(t, os.MkdirAll(filepath.Dir(p), 0700))
+	require.NoError(t, os.WriteFile(p, []byte("# thing"), 0600))
+
+	source := NewLocalDocsSource(tfbridge.ProviderInfo{Name: "test"}, dir)
+
+	doc, err := source.GetResource("test_thing", nil)
+	require.NoError(t, err)
+	require.NotNil(t, doc)
+	assert.Equal(t, "# thing", string(doc.Content))
+	assert.Equal(t, "thing.md", doc.FileName)
+
+	doc, err = source.GetDatasource("test_thing", nil)
+	require.NoError(t, err)
+	assert.Nil(t, doc)
+
+	doc, err = source.GetResource("test_other", &tfbridge.DocInfo{Markdown: []byte("# override")})
+	require.NoError(t, err)
+	assert.Equal(t, "# override", string(doc.Content))
+}
+
+func TestArchiveDocsSource(t *testing.T) {
+	t.Parallel()
+	for _, prefix := range []string{"", "terraform-provider-test-1.0.0/"} {
+		prefix := prefix
+		t.Run(prefix, func(t *testing.T) {
+			t.Parallel()
+			archive := filepath.Join(t.TempDir(), "docs.tar.gz")
+			writeTarball(t, archive, map[string]string{
+				prefix + "website/docs/d/thing.html.markdown": "# thing",
+			})
+
+			source, err := NewArchiveDocsSource(tfbridge.ProviderInfo{Name: "test"}, archive)
+			require.NoError(t, err)
+
+			doc, err := source.GetDatasource("test_thing", nil)
+			require.NoError(t, err)
+			require.NotNil(t, doc)
+			assert.Equal(t, "# thing", string(doc.Content))
+			assert.Equal(t, "thing.html.markdown", doc.FileName)
+		})
+	}
+}
+
+func TestRegistryDocsSource(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	docs := filepath.Join(dir, "docs.json")
+	require.NoError(t, os.WriteFile(docs, []byte(`{"data": [{
+		"type": "provider-docs",
+		"attributes": {
+			"category": "resources",
+			"slug": "thing",
+			"path": "website/docs/r/thing.html.markdown",
+			"content": "# thing"
+		}
+	}]}`), 0600))
+	schema := filepath.Join(dir, "schema.json")
+	require.NoError(t, os.WriteFile(schema, []byte(`{"provider_schemas": {
+		"registry.terraform.io/hashicorp/test": {
+			"data_source_schemas": {
+				"test_other": {"block": {
+					"description": "Looks up another thing.",
+					"attributes": {
+						"name": {"description": "The name.", "required": true},
+						"id": {"description": "The ID.", "computed": true}
+					}
+				}}
+			}
+		}
+	}}`), 0600))
+
+	source, err := NewRegistryDocsSource(tfbridge.ProviderInfo{Name: "test"}, docs, schema)
+	require.NoError(t, err)
+
+	doc, err := source.GetResource("test_thing", nil)
+	require.NoError(t, err)
+	require.NotNil(t, doc)
+	assert.Equal(t, "# thing", string(doc.Content))
+	assert.Equal(t, "thing.html.markdown", doc.FileName)
+
+	doc, err = source.GetDatasource("test_other", nil)
+	require.NoError(t, err)
+	require.NotNil(t, doc)
+	assert.Equal(t, "# test_other\n\nLooks up another thing.\n\n"+
+		"## Argument Reference\n\n* `name` - (Required) The name.\n\n"+
+		"## Attribute Reference\n\n* `id` - The ID.\n", string(doc.Content))
+
+	doc, err = source.GetDatasource("test_missing", nil)
+	require.NoError(t, err)
+	assert.Nil(t, doc)
+}
+
+func writeTarball(t *testing.T, path string, files map[string]string) {
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, contents := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0600,
+			Size:     int64(len(contents)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+}
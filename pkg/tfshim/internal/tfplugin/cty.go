@@ -1,4 +1,21 @@
-package tfplugin5
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tfplugin holds the parts of the Terraform plugin protocol shims that do not depend on the protocol version:
+// conversions between cty and Go values, schemas, resources and instance diffs. The tfplugin5 and tfplugin6 packages
+// add the protocol specific encoding of these values.
+package tfplugin
 
 import (
 	"fmt"
@@ -8,9 +25,9 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 )
 
-// ctyToGo converts a cty.Value to a plain Go value with the notable exception of sets, which are left as-is. Sets can
+// CtyToGo converts a cty.Value to a plain Go value with the notable exception of sets, which are left as-is. Sets can
 // be converted to plain values by calling provider.IsSet ala tfbridge. Capsule types are not supported.
-func ctyToGo(val cty.Value) (interface{}, error) {
+func CtyToGo(val cty.Value) (interface{}, error) {
 	switch {
 	case val.IsNull():
 		// Convert null values to nil.
@@ -37,7 +54,7 @@ func ctyToGo(val cty.Value) (interface{}, error) {
 			k, v := iter.Element()
 			i, _ := k.AsBigFloat().Int64()
 
-			gv, err := ctyToGo(v)
+			gv, err := CtyToGo(v)
 			if err != nil {
 				return nil, err
 			}
@@ -60,7 +77,7 @@ func ctyToGo(val cty.Value) (interface{}, error) {
 				return UnknownVariableValue, nil
 			}
 
-			gv, err := ctyToGo(v)
+			gv, err := CtyToGo(v)
 			if err != nil {
 				return nil, err
 			}
@@ -71,10 +88,10 @@ func ctyToGo(val cty.Value) (interface{}, error) {
 	return nil, fmt.Errorf("unsupported cty type %v", val.Type().FriendlyName())
 }
 
-// goToCty converts a reflect.Value to a cty.Value of the given type. Capsule types are not supported.
+// GoToCty converts a Go value to a cty.Value of the given type. Capsule types are not supported.
 // Only a limited set of Go values are supported: bools, ints/uints/floats, strings, arrays/slices, and maps with
 // string-typed keys. Structs are not supported.
-func goToCty(v interface{}, ty cty.Type) (cty.Value, error) {
+func GoToCty(v interface{}, ty cty.Type) (cty.Value, error) {
 	return reflectToCty(reflect.ValueOf(v), ty)
 }
 
@@ -1,4 +1,4 @@
-package tfplugin5
+package tfplugin
 
 import (
 	"testing"
@@ -8,7 +8,7 @@ import (
 )
 
 func testCtyToGo(t *testing.T, expected interface{}, val cty.Value) {
-	actual, err := ctyToGo(val)
+	actual, err := CtyToGo(val)
 	if assert.NoError(t, err) {
 		assert.Equal(t, expected, actual)
 	}
@@ -0,0 +1,125 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfplugin
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+// DeprecationMessage returns the deprecation message of a property that the provider schema marks as deprecated.
+func DeprecationMessage(propertyName string, isDeprecated bool) string {
+	if isDeprecated {
+		return fmt.Sprintf("%v is deprecated", propertyName)
+	}
+	return ""
+}
+
+func unmarshalNestedType(elementType cty.Type) (interface{}, error) {
+	valueType, elem, err := UnmarshalType(elementType)
+	if err != nil {
+		return nil, err
+	}
+
+	switch valueType {
+	case shim.TypeBool, shim.TypeInt, shim.TypeFloat, shim.TypeString:
+		return &Schema{Attribute: Attribute{
+			CtyType:   elementType,
+			ValueType: valueType,
+		}}, nil
+	case shim.TypeList, shim.TypeSet:
+		return &Schema{Attribute: Attribute{
+			CtyType:   elementType,
+			ValueType: valueType,
+			Elem:      elem,
+		}}, nil
+	case shim.TypeMap:
+		if r, ok := elem.(*Resource); ok {
+			return r, nil
+		}
+		return &Schema{Attribute: Attribute{
+			CtyType:   elementType,
+			ValueType: valueType,
+			Elem:      elem,
+		}}, nil
+	default:
+		return nil, fmt.Errorf("unexpected value type %v", valueType)
+	}
+}
+
+func unmarshalCompositeType(ty cty.Type) (shim.ValueType, interface{}, error) {
+	switch {
+	case ty.IsListType():
+		elementType, err := unmarshalNestedType(ty.ElementType())
+		if err != nil {
+			return shim.TypeInvalid, nil, err
+		}
+		return shim.TypeList, elementType, nil
+	case ty.IsMapType():
+		elementType, err := unmarshalNestedType(ty.ElementType())
+		if err != nil {
+			return shim.TypeInvalid, nil, err
+		}
+		return shim.TypeMap, elementType, nil
+	case ty.IsSetType():
+		elementType, err := unmarshalNestedType(ty.ElementType())
+		if err != nil {
+			return shim.TypeInvalid, nil, err
+		}
+		return shim.TypeSet, elementType, nil
+	case ty.IsObjectType():
+		properties := schema.SchemaMap{}
+		for name, ty := range ty.AttributeTypes() {
+			property, err := unmarshalNestedType(ty)
+			if err != nil {
+				return shim.TypeInvalid, nil, err
+			}
+			if s, ok := property.(*Schema); ok {
+				properties[name] = s
+			} else {
+				r, isResource := property.(*Resource)
+				contract.Assertf(isResource, "isResource")
+				properties[name] = &Schema{Attribute: Attribute{
+					CtyType:   r.CtyType,
+					ValueType: shim.TypeMap,
+					Elem:      s,
+				}}
+			}
+		}
+		return shim.TypeMap, &Resource{CtyType: ty, SchemaMap: properties}, nil
+	default:
+		return shim.TypeInvalid, nil, fmt.Errorf("unexpected composite type %v", ty)
+	}
+}
+
+// UnmarshalType converts the cty type of an attribute to the value type and element of a shim schema. The element
+// of an object type is a *Resource.
+func UnmarshalType(ty cty.Type) (shim.ValueType, interface{}, error) {
+	switch ty {
+	case cty.String:
+		return shim.TypeString, nil, nil
+	case cty.Bool:
+		return shim.TypeBool, nil, nil
+	case cty.Number:
+		return shim.TypeFloat, nil, nil
+	default:
+		return unmarshalCompositeType(ty)
+	}
+}
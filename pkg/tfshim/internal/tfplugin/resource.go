@@ -0,0 +1,164 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfplugin
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+var _ = shim.Resource((*Resource)(nil))
+var _ = shim.ResourceMap(ResourceMap{})
+
+// Resource implements shim.Resource for a resource, a data source, the provider configuration or a nested block of a
+// provider schema.
+type Resource struct {
+	ResourceType string
+	CtyType      cty.Type
+	SchemaMap    schema.SchemaMap
+	Version      int
+
+	// Import imports existing resources of this type. It is nil for data sources and nested blocks.
+	Import shim.ImportFunc
+	// NewState returns an instance state of this type. It is nil for nested blocks.
+	NewState func(id string, object, meta map[string]interface{}) shim.InstanceState
+}
+
+func (r *Resource) Schema() shim.SchemaMap {
+	return r.SchemaMap
+}
+
+func (r *Resource) SchemaVersion() int {
+	return r.Version
+}
+
+func (r *Resource) Importer() shim.ImportFunc {
+	return r.Import
+}
+
+func (r *Resource) DeprecationMessage() string {
+	return ""
+}
+
+func (r *Resource) Timeouts() *shim.ResourceTimeout {
+	return &shim.ResourceTimeout{}
+}
+
+func (r *Resource) InstanceState(id string, object, meta map[string]interface{}) (shim.InstanceState, error) {
+	// Stamp the ID into the object.
+	object["id"] = id
+
+	if r.NewState == nil {
+		return nil, fmt.Errorf("internal error: %q has no instance state", r.ResourceType)
+	}
+	return r.NewState(id, object, meta), nil
+}
+
+func parseTimeout(timeouts *shim.ResourceTimeout, timeoutsMap map[string]interface{}, key string) error {
+	timeoutValue, ok := timeoutsMap[key]
+	if !ok {
+		return nil
+	}
+	timeoutString, ok := timeoutValue.(string)
+	if !ok {
+		return fmt.Errorf("%v timeout must be a string", key)
+	}
+	duration, err := time.ParseDuration(timeoutString)
+	if err != nil {
+		return fmt.Errorf("failed to parse %v timeout: %w", key, err)
+	}
+	switch key {
+	case "create":
+		timeouts.Create = &duration
+	case "update":
+		timeouts.Update = &duration
+	case "read":
+		timeouts.Read = &duration
+	case "delete":
+		timeouts.Delete = &duration
+	case "default":
+		timeouts.Default = &duration
+	default:
+		return fmt.Errorf("%v timeout is unsupported", key)
+	}
+	return nil
+}
+
+func (r *Resource) DecodeTimeouts(c shim.ResourceConfig) (*shim.ResourceTimeout, error) {
+	config, ok := c.(ResourceConfig)
+	if !ok {
+		return nil, fmt.Errorf("internal error: foreign resource config")
+	}
+
+	timeoutsValue, ok := config["timeouts"]
+	if !ok {
+		return &shim.ResourceTimeout{}, nil
+	}
+	timeoutsMap, ok := timeoutsValue.(map[string]interface{})
+	if !ok {
+		return &shim.ResourceTimeout{}, nil
+	}
+
+	timeouts := &shim.ResourceTimeout{}
+	for _, key := range []string{"create", "update", "read", "delete", "default"} {
+		if err := parseTimeout(timeouts, timeoutsMap, key); err != nil {
+			return nil, err
+		}
+	}
+	return timeouts, nil
+}
+
+// ResourceMap implements shim.ResourceMap.
+type ResourceMap map[string]*Resource
+
+func (m ResourceMap) Len() int {
+	return len(m)
+}
+
+func (m ResourceMap) Get(key string) shim.Resource {
+	r, _ := m.GetOk(key)
+	return r
+}
+
+func (m ResourceMap) GetOk(key string) (shim.Resource, bool) {
+	if r, ok := m[key]; ok {
+		return r, true
+	}
+	return nil, false
+}
+
+func (m ResourceMap) Range(each func(key string, value shim.Resource) bool) {
+	for key, value := range m {
+		if !each(key, value) {
+			return
+		}
+	}
+}
+
+func (m ResourceMap) Set(key string, value shim.Resource) {
+	m[key] = value.(*Resource)
+}
+
+// ResourceConfig implements shim.ResourceConfig.
+type ResourceConfig map[string]interface{}
+
+func (c ResourceConfig) IsSet(k string) bool {
+	_, ok := c[k]
+	return ok
+}
@@ -0,0 +1,145 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfplugin
+
+import (
+	"github.com/hashicorp/go-cty/cty"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+// UnknownVariableValue is the sentinal defined in github.com/hashicorp/terraform/configs/hcl2shim,
+// representing a variable whose value is not known at some particular time. The value is duplicated here in
+// order to prevent an additional dependency - it is unlikely to ever change upstream since that would break
+// rather a lot of things.
+const UnknownVariableValue = "74D93920-ED26-11E3-AC10-0800200C9A66"
+
+// Attribute describes an attribute or a nested block of a provider schema.
+type Attribute struct {
+	CtyType     cty.Type
+	ValueType   shim.ValueType
+	Optional    bool
+	Required    bool
+	Description string
+	Computed    bool
+	ForceNew    bool
+	Elem        interface{}
+	MaxItems    int
+	MinItems    int
+	Deprecated  string
+	Sensitive   bool
+}
+
+// Schema implements shim.Schema for an Attribute.
+type Schema struct {
+	Attribute
+}
+
+var _ = shim.Schema((*Schema)(nil))
+
+func (s *Schema) Type() shim.ValueType {
+	return s.Attribute.ValueType
+}
+
+func (s *Schema) Optional() bool {
+	return s.Attribute.Optional
+}
+
+func (s *Schema) Required() bool {
+	return s.Attribute.Required
+}
+
+func (s *Schema) Default() interface{} {
+	return nil
+}
+
+func (s *Schema) DefaultFunc() shim.SchemaDefaultFunc {
+	return nil
+}
+
+func (s *Schema) DefaultValue() (interface{}, error) {
+	return nil, nil
+}
+
+func (s *Schema) Description() string {
+	return s.Attribute.Description
+}
+
+func (s *Schema) Computed() bool {
+	return s.Attribute.Computed
+}
+
+func (s *Schema) ForceNew() bool {
+	return s.Attribute.ForceNew
+}
+
+func (s *Schema) StateFunc() shim.SchemaStateFunc {
+	return nil
+}
+
+func (s *Schema) Elem() interface{} {
+	return s.Attribute.Elem
+}
+
+func (s *Schema) MaxItems() int {
+	return s.Attribute.MaxItems
+}
+
+func (s *Schema) MinItems() int {
+	return s.Attribute.MinItems
+}
+
+func (s *Schema) ConflictsWith() []string {
+	return nil
+}
+
+func (s *Schema) ExactlyOneOf() []string {
+	return nil
+}
+
+func (s *Schema) AtLeastOneOf() []string {
+	return nil
+}
+
+func (s *Schema) Removed() string {
+	return ""
+}
+
+func (s *Schema) Deprecated() string {
+	return s.Attribute.Deprecated
+}
+
+func (s *Schema) Sensitive() bool {
+	return s.Attribute.Sensitive
+}
+
+func (s *Schema) UnknownValue() interface{} {
+	return UnknownVariableValue
+}
+
+func (s *Schema) SetElement(v interface{}) (interface{}, error) {
+	val, err := GoToCty(v, s.CtyType)
+	if err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+func (s *Schema) SetHash(v interface{}) int {
+	val, ok := v.(cty.Value)
+	contract.Assertf(ok, "internal error: SetHash must be a cty.Value")
+	return val.Hash()
+}
@@ -20,7 +20,9 @@
 //   - sdk-v2 for https://github.com/hashicorp/terraform-plugin-sdk (v2)
 //
 // The tfplugin5 backend is experimental and is not as of time of this writing to build production
-// providers by Pulumi.
+// providers by Pulumi. It bridges prebuilt provider binaries speaking Terraform plugin protocol
+// version 5, see tfplugin5.StartProvider. The tfplugin6 backend is its counterpart for providers
+// that only speak protocol version 6, exposed as a tfprotov6.ProviderServer.
 //
 // Note that providers built with the Plugin Framework do not currently conform to the backend
 // interface and are handled separately, see github.com/pulumi/pulumi-terraform-bridge/pf
@@ -33,6 +33,8 @@ const (
 
 	// This strategy would run both PlanState and ClassicDiff strategies and compare their result, generating a
 	// warning if they mismatch. It always behaves as ClassicDiff execept for the warnings.
+	//
+	// Every comparison is also sent to the DiffStrategyReporter given to WithDiffStrategy.
 	TryPlanState DiffStrategy = 2
 )
 
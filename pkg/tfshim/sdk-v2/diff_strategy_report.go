@@ -0,0 +1,135 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdkv2
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// DiffComparison is the outcome of a Diff computed with the TryPlanState strategy, which runs both the ClassicDiff
+// and PlanState strategies. Comparisons where both strategies agree are reported as well, so that the share of
+// agreeing diffs of a resource can be computed.
+type DiffComparison struct {
+	// The TF type name of the resource, such as aws_ssm_document.
+	ResourceType string `json:"resourceType"`
+
+	// The flatmap paths of the attributes whose diffs differ between the strategies, such as "tags.%".
+	Attributes []string `json:"attributes,omitempty"`
+
+	ClassicChangeType   string `json:"classicChangeType"`
+	PlanStateChangeType string `json:"planStateChangeType,omitempty"`
+
+	ClassicRequiresNew   bool `json:"classicRequiresNew"`
+	PlanStateRequiresNew bool `json:"planStateRequiresNew"`
+
+	// DecisionChanged is true when the strategies disagree on whether to update or replace the resource.
+	DecisionChanged bool `json:"decisionChanged"`
+
+	// The error returned by the PlanState strategy, if it failed.
+	PlanStateError string `json:"planStateError,omitempty"`
+}
+
+// Mismatch returns true if the strategies did not compute the same diff.
+func (c DiffComparison) Mismatch() bool {
+	return len(c.Attributes) > 0 || c.DecisionChanged || c.PlanStateError != ""
+}
+
+// DiffStrategyReporter receives the comparisons computed by the TryPlanState strategy.
+type DiffStrategyReporter interface {
+	ReportDiffComparison(ctx context.Context, c DiffComparison)
+}
+
+const diffStrategyReportEnvVar = "PULUMI_DIFF_STRATEGY_REPORT"
+
+// NewDiffStrategyFileReporter returns a DiffStrategyReporter that appends every comparison as a line of JSON to the
+// file at path.
+//
+// Providers given no reporter write to the file named by the PULUMI_DIFF_STRATEGY_REPORT environment variable, if
+// set, whether or not they use WithDiffStrategy.
+func NewDiffStrategyFileReporter(path string) DiffStrategyReporter {
+	return &fileDiffStrategyReporter{path: path}
+}
+
+type fileDiffStrategyReporter struct {
+	mu   sync.Mutex
+	path string
+}
+
+func (r *fileDiffStrategyReporter) ReportDiffComparison(_ context.Context, c DiffComparison) {
+	line, err := json.Marshal(c)
+	if err != nil {
+		glog.Warningf("failed to report the diff comparison of %s: %v", c.ResourceType, err)
+		return
+	}
+	line = append(line, '\n')
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		glog.Warningf("failed to open the diff strategy report %s: %v", r.path, err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(line); err != nil {
+		glog.Warningf("failed to write the diff strategy report %s: %v", r.path, err)
+	}
+}
+
+func compareDiffs(resourceType string, classic, planState *terraform.InstanceDiff) DiffComparison {
+	c := DiffComparison{
+		ResourceType:         resourceType,
+		ClassicChangeType:    showDiffChangeType(byte(classic.ChangeType())),
+		PlanStateChangeType:  showDiffChangeType(byte(planState.ChangeType())),
+		ClassicRequiresNew:   classic.RequiresNew(),
+		PlanStateRequiresNew: planState.RequiresNew(),
+	}
+	c.DecisionChanged = c.ClassicChangeType != c.PlanStateChangeType || c.ClassicRequiresNew != c.PlanStateRequiresNew
+
+	var classicAttrs, planStateAttrs map[string]*terraform.ResourceAttrDiff
+	if classic != nil {
+		classicAttrs = classic.Attributes
+	}
+	if planState != nil {
+		planStateAttrs = planState.Attributes
+	}
+	for k, a := range classicAttrs {
+		if b, ok := planStateAttrs[k]; !ok || !sameAttrDiff(a, b) {
+			c.Attributes = append(c.Attributes, k)
+		}
+	}
+	for k := range planStateAttrs {
+		if _, ok := classicAttrs[k]; !ok {
+			c.Attributes = append(c.Attributes, k)
+		}
+	}
+	sort.Strings(c.Attributes)
+	return c
+}
+
+func sameAttrDiff(a, b *terraform.ResourceAttrDiff) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Old == b.Old && a.New == b.New && a.NewComputed == b.NewComputed &&
+		a.NewRemoved == b.NewRemoved && a.RequiresNew == b.RequiresNew
+}
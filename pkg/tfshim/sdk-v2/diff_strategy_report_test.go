@@ -0,0 +1,132 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdkv2
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+)
+
+type testDiffStrategyReporter []DiffComparison
+
+func (r *testDiffStrategyReporter) ReportDiffComparison(_ context.Context, c DiffComparison) {
+	*r = append(*r, c)
+}
+
+func TestTryPlanStateReportsComparisons(t *testing.T) {
+	t.Setenv(diffStrategyEnvVar, "")
+	ctx := context.Background()
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"tags": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+	p := &schema.Provider{
+		ResourcesMap: map[string]*schema.Resource{"myres": r},
+	}
+
+	reporter := &testDiffStrategyReporter{}
+	wp := NewProvider(p, WithDiffStrategy(TryPlanState, reporter))
+
+	state := cty.ObjectVal(map[string]cty.Value{
+		"tags": cty.MapVal(map[string]cty.Value{"tag1": cty.StringVal("tag1v")}),
+	})
+	config := cty.ObjectVal(map[string]cty.Value{
+		"tags": cty.MapVal(map[string]cty.Value{"tag1": cty.StringVal("tag1v2")}),
+	})
+
+	instanceState := terraform.NewInstanceStateShimmedFromValue(state, 0)
+	instanceState.ID = "oldid"
+	instanceState.Meta = map[string]interface{}{} // ignore schema versions for this test
+	resourceConfig := terraform.NewResourceConfigShimmed(config, r.CoreConfigSchema())
+
+	_, err := wp.Diff(ctx, "myres", v2InstanceState{resource: r, tf: instanceState}, v2ResourceConfig{
+		tf: resourceConfig,
+	}, shim.DiffOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []DiffComparison{{
+		ResourceType:        "myres",
+		ClassicChangeType:   "diffUpdate",
+		PlanStateChangeType: "diffUpdate",
+	}}, []DiffComparison(*reporter))
+	assert.False(t, (*reporter)[0].Mismatch())
+}
+
+func TestCompareDiffs(t *testing.T) {
+	classic := &terraform.InstanceDiff{Attributes: map[string]*terraform.ResourceAttrDiff{
+		"name":   {Old: "a", New: "b"},
+		"tags.%": {Old: "1", New: "2"},
+	}}
+	planState := &terraform.InstanceDiff{Attributes: map[string]*terraform.ResourceAttrDiff{
+		"name": {Old: "a", New: "b", RequiresNew: true},
+		"zone": {Old: "", New: "z"},
+	}}
+
+	c := compareDiffs("myres", classic, planState)
+	assert.Equal(t, DiffComparison{
+		ResourceType:         "myres",
+		Attributes:           []string{"name", "tags.%", "zone"},
+		ClassicChangeType:    "diffUpdate",
+		PlanStateChangeType:  "diffCreate",
+		PlanStateRequiresNew: true,
+		DecisionChanged:      true,
+	}, c)
+	assert.True(t, c.Mismatch())
+}
+
+func TestDiffStrategyFileReporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.jsonl")
+	r := NewDiffStrategyFileReporter(path)
+	r.ReportDiffComparison(context.Background(), DiffComparison{ResourceType: "a", ClassicChangeType: "diffNone"})
+	r.ReportDiffComparison(context.Background(), DiffComparison{ResourceType: "b", Attributes: []string{"x"}})
+
+	bytes, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t,
+		`{"resourceType":"a","classicChangeType":"diffNone","classicRequiresNew":false,`+
+			`"planStateRequiresNew":false,"decisionChanged":false}`+"\n"+
+			`{"resourceType":"b","attributes":["x"],"classicChangeType":"","classicRequiresNew":false,`+
+			`"planStateRequiresNew":false,"decisionChanged":false}`+"\n",
+		string(bytes))
+}
+
+func TestDiffStrategyReportFromEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.jsonl")
+	t.Setenv(diffStrategyReportEnvVar, path)
+
+	opts, err := getProviderOptions(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []DiffStrategyReporter{NewDiffStrategyFileReporter(path)}, opts.diffStrategyReporters)
+
+	reporter := &testDiffStrategyReporter{}
+	opts, err = getProviderOptions([]providerOption{WithDiffStrategy(TryPlanState, reporter)})
+	require.NoError(t, err)
+	assert.Equal(t, []DiffStrategyReporter{reporter}, opts.diffStrategyReporters)
+}
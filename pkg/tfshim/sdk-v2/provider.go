@@ -3,6 +3,7 @@ package sdkv2
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -12,6 +13,7 @@ import (
 )
 
 var _ = shim.Provider(v2Provider{})
+var _ = shim.StateUpgrader(v2Provider{})
 
 func configFromShim(c shim.ResourceConfig) *terraform.ResourceConfig {
 	if c == nil {
@@ -156,6 +158,30 @@ func (p v2Provider) Refresh(
 	return stateToShim(r, state), errors(diags)
 }
 
+// UpgradeState implements shim.StateUpgrader.
+func (p v2Provider) UpgradeState(
+	ctx context.Context,
+	t string,
+	version int,
+	rawState map[string]interface{},
+) (shim.InstanceState, error) {
+	r, ok := p.tf.ResourcesMap[t]
+	if !ok {
+		return nil, fmt.Errorf("unknown resource %v", t)
+	}
+
+	v, err := upgradeJSONState(ctx, p.tf, r, version, rawState)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upgrade resource state: %w", err)
+	}
+	state, err := r.ShimInstanceStateFromValue(v)
+	if err != nil {
+		return nil, err
+	}
+	state.Meta = map[string]interface{}{"schema_version": strconv.Itoa(r.SchemaVersion)}
+	return stateToShim(r, state), nil
+}
+
 func (p v2Provider) ReadDataDiff(
 	ctx context.Context,
 	t string,
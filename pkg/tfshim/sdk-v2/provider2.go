@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/go-cty/cty/msgpack"
@@ -350,6 +351,29 @@ func (p *planResourceChangeImpl) upgradeState(
 	}, nil
 }
 
+// UpgradeState upgrades a raw state recorded at an older schema version; the result is wrapped the same way as the
+// states returned by the other methods of planResourceChangeImpl.
+func (p *planResourceChangeImpl) UpgradeState(
+	ctx context.Context,
+	t string,
+	version int,
+	rawState map[string]interface{},
+) (shim.InstanceState, error) {
+	res, ok := p.tf.ResourcesMap[t]
+	if !ok {
+		return nil, fmt.Errorf("unknown resource %v", t)
+	}
+	stateValue, err := upgradeJSONState(ctx, p.tf, res, version, rawState)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upgrade resource state: %w", err)
+	}
+	return &v2InstanceState2{
+		resourceType: t,
+		stateValue:   stateValue,
+		meta:         map[string]interface{}{"schema_version": strconv.Itoa(res.SchemaVersion)},
+	}, nil
+}
+
 // Helper to unwrap gRPC types from GRPCProviderServer.
 type grpcServer struct {
 	gserver *schema.GRPCProviderServer
@@ -629,12 +653,18 @@ type planResourceChangeProvider interface {
 		ctx context.Context, t string, s shim.InstanceState, c shim.ResourceConfig,
 	) (shim.InstanceState, error)
 
+	UpgradeState(
+		ctx context.Context, t string, version int, rawState map[string]interface{},
+	) (shim.InstanceState, error)
+
 	NewDestroyDiff(ctx context.Context, t string, opts shim.TimeoutOptions) shim.InstanceDiff
 
 	// Moving this method to the provider object from the shim.Resource object for convenience.
 	Importer(t string) shim.ImportFunc
 }
 
+var _ shim.StateUpgrader = (*providerWithPlanResourceChangeDispatch)(nil)
+
 // Wraps a provider to redirect select resources to use a PlanResourceChange strategy.
 type providerWithPlanResourceChangeDispatch struct {
 	// Fallback provider to dispatch calls to.
@@ -699,6 +729,20 @@ func (p *providerWithPlanResourceChangeDispatch) Refresh(
 	return p.Provider.Refresh(ctx, t, s, c)
 }
 
+// Override UpgradeState to dispatch appropriately.
+func (p *providerWithPlanResourceChangeDispatch) UpgradeState(
+	ctx context.Context, t string, version int, rawState map[string]interface{},
+) (shim.InstanceState, error) {
+	if p.usePlanResourceChange(t) {
+		return p.planResourceChangeProvider.UpgradeState(ctx, t, version, rawState)
+	}
+	upgrader, ok := p.Provider.(shim.StateUpgrader)
+	if !ok {
+		return nil, fmt.Errorf("resource %v does not support state upgrades", t)
+	}
+	return upgrader.UpgradeState(ctx, t, version, rawState)
+}
+
 // Override NewDestroyDiff to dispatch appropriately.
 func (p *providerWithPlanResourceChangeDispatch) NewDestroyDiff(
 	ctx context.Context, t string, opts shim.TimeoutOptions,
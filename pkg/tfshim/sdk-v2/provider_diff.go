@@ -72,7 +72,7 @@ func (p v2Provider) Diff(
 		}
 	}
 
-	diff, err := p.simpleDiff(ctx, providerOpts.diffStrategy, r, state, config, rawConfig, p.tf.Meta())
+	diff, err := p.simpleDiff(ctx, providerOpts, t, r, state, config, rawConfig, p.tf.Meta())
 	if err != nil {
 		return nil, err
 	}
@@ -91,7 +91,8 @@ func (p v2Provider) Diff(
 
 func (p v2Provider) simpleDiff(
 	ctx context.Context,
-	diffStrat DiffStrategy,
+	providerOpts providerOptions,
+	t string,
 	res *schema.Resource,
 	s *terraform.InstanceState,
 	c *terraform.ResourceConfig,
@@ -99,7 +100,7 @@ func (p v2Provider) simpleDiff(
 	meta interface{},
 ) (*terraform.InstanceDiff, error) {
 
-	switch diffStrat {
+	switch providerOpts.diffStrategy {
 	case ClassicDiff:
 		state := s.DeepCopy()
 		if state.RawPlan.IsNull() {
@@ -129,6 +130,22 @@ func (p v2Provider) simpleDiff(
 			return nil, err
 		}
 		planStateResult, err := simpleDiffViaPlanState(ctx, res, s, rawConfigVal, meta)
+		if len(providerOpts.diffStrategyReporters) > 0 {
+			var comparison DiffComparison
+			if err != nil {
+				comparison = DiffComparison{
+					ResourceType:       t,
+					ClassicChangeType:  showDiffChangeType(byte(classicResult.ChangeType())),
+					ClassicRequiresNew: classicResult.RequiresNew(),
+					PlanStateError:     err.Error(),
+				}
+			} else {
+				comparison = compareDiffs(t, classicResult, planStateResult)
+			}
+			for _, r := range providerOpts.diffStrategyReporters {
+				r.ReportDiffComparison(ctx, comparison)
+			}
+		}
 		if err != nil {
 			glog.Errorf("Ignoring PlanState DiffStrategy that failed with an unexpected error. "+
 				"You can set the environment variable %s to %q to avoid this message. "+
@@ -219,12 +236,10 @@ func showDiffChangeType(b byte) string {
 	case 2:
 		return "diffCreate"
 	case 3:
-		return "diffCreate"
-	case 4:
 		return "diffUpdate"
-	case 5:
+	case 4:
 		return "diffDestroy"
-	case 6:
+	case 5:
 		return "diffDestroyCreate"
 	default:
 		return "diffInvalid"
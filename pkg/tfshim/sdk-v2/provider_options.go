@@ -14,15 +14,23 @@
 
 package sdkv2
 
+import "os"
+
 type providerOptions struct {
 	diffStrategy             DiffStrategy
+	diffStrategyReporters    []DiffStrategyReporter
 	planResourceChangeFilter func(string) bool
 }
 
 type providerOption func(providerOptions) (providerOptions, error)
 
-func WithDiffStrategy(s DiffStrategy) providerOption { //nolint:revive
+// Selects the DiffStrategy of the provider, unless overridden by the PULUMI_DIFF_STRATEGY environment variable.
+//
+// The comparisons computed by the TryPlanState strategy are sent to the reporters, or to the file named by the
+// PULUMI_DIFF_STRATEGY_REPORT environment variable if no reporter is given. See [NewDiffStrategyFileReporter].
+func WithDiffStrategy(s DiffStrategy, reporters ...DiffStrategyReporter) providerOption { //nolint:revive
 	return func(opts providerOptions) (providerOptions, error) {
+		opts.diffStrategyReporters = reporters
 
 		diffStrategyFromEnv, gotDiffStrategyFromEnv, err := ParseDiffStrategyFromEnv()
 		if err != nil {
@@ -48,6 +56,11 @@ func getProviderOptions(opts []providerOption) (providerOptions, error) {
 			return res, err
 		}
 	}
+	if len(res.diffStrategyReporters) == 0 {
+		if path := os.Getenv(diffStrategyReportEnvVar); path != "" {
+			res.diffStrategyReporters = []DiffStrategyReporter{NewDiffStrategyFileReporter(path)}
+		}
+	}
 	return res, nil
 }
 
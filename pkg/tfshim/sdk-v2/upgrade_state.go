@@ -41,35 +41,11 @@ func upgradeResourceState(ctx context.Context, p *schema.Provider, res *schema.R
 		return nil, err
 	}
 
-	// Next, migrate the JSON state up to the current version.
-	json, err = schema.UpgradeJSONState(ctx, version, json, res, p.Meta())
+	v, err := upgradeJSONState(ctx, p, res, version, json)
 	if err != nil {
 		return nil, err
 	}
 
-	configBlock := res.CoreConfigSchema()
-
-	// Strip out removed fields.
-	schema.RemoveAttributes(ctx, json, configBlock.ImpliedType())
-
-	// now we need to turn the state into the default json representation, so
-	// that it can be re-decoded using the actual schema.
-	v, err := schema.JSONMapToStateValue(json, configBlock)
-	if err != nil {
-		return nil, err
-	}
-
-	// Now we need to make sure blocks are represented correctly, which means
-	// that missing blocks are empty collections, rather than null.
-	// First we need to CoerceValue to ensure that all object types match.
-	v, err = configBlock.CoerceValue(v)
-	if err != nil {
-		return nil, err
-	}
-
-	// Normalize the value and fill in any missing blocks.
-	v = schema.NormalizeObjectFromLegacySDK(v, configBlock)
-
 	// Convert the value back to an InstanceState.
 	newState, err := res.ShimInstanceStateFromValue(v)
 	if err != nil {
@@ -95,6 +71,41 @@ func upgradeResourceState(ctx context.Context, p *schema.Provider, res *schema.R
 	return newState, nil
 }
 
+// upgradeJSONState migrates a JSON state recorded at the given schema version up to the current version of res and
+// decodes it with the current schema.
+func upgradeJSONState(ctx context.Context, p *schema.Provider, res *schema.Resource, version int,
+	json map[string]interface{}) (cty.Value, error) {
+
+	// Migrate the JSON state up to the current version.
+	json, err := schema.UpgradeJSONState(ctx, version, json, res, p.Meta())
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	configBlock := res.CoreConfigSchema()
+
+	// Strip out removed fields.
+	schema.RemoveAttributes(ctx, json, configBlock.ImpliedType())
+
+	// now we need to turn the state into the default json representation, so
+	// that it can be re-decoded using the actual schema.
+	v, err := schema.JSONMapToStateValue(json, configBlock)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	// Now we need to make sure blocks are represented correctly, which means
+	// that missing blocks are empty collections, rather than null.
+	// First we need to CoerceValue to ensure that all object types match.
+	v, err = configBlock.CoerceValue(v)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	// Normalize the value and fill in any missing blocks.
+	return schema.NormalizeObjectFromLegacySDK(v, configBlock), nil
+}
+
 func findID(v cty.Value) (string, bool) {
 	if !v.Type().IsObjectType() {
 		return "", false
@@ -231,6 +231,14 @@ type Provider interface {
 	IsSet(ctx context.Context, v interface{}) ([]interface{}, bool)
 }
 
+// StateUpgrader is implemented by providers that can upgrade the raw state of a resource, as recorded by Terraform
+// at an older schema version, to the current schema of the resource without contacting the cloud.
+type StateUpgrader interface {
+	// UpgradeState upgrades rawState, the JSON attributes of a resource of type t recorded at the given schema
+	// version. The meta of the returned state records the current schema version of the resource.
+	UpgradeState(ctx context.Context, t string, version int, rawState map[string]interface{}) (InstanceState, error)
+}
+
 type TimeoutOptions struct {
 	ResourceTimeout  *ResourceTimeout // optional
 	TimeoutOverrides map[TimeoutKey]time.Duration
@@ -0,0 +1,290 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package snapshot serializes the schema of a [shim.Provider], so that tfgen can generate a Pulumi schema, docs and
+// SDKs without compiling the upstream provider.
+//
+// Unlike [info.MarshallableProviderShim], which only holds what `pulumi convert` needs, a snapshot holds everything
+// tfgen reads from the provider: descriptions, static defaults, deprecations, timeouts and schema versions. Providers
+// loaded from a snapshot are schema-only and panic on runtime operations.
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+// FormatVersion is the version of the snapshot file layout.
+const FormatVersion = 1
+
+// Provider is the serialized schema of a provider.
+type Provider struct {
+	FormatVersion int                  `json:"formatVersion"`
+	Schema        map[string]*Schema   `json:"schema,omitempty"`
+	Resources     map[string]*Resource `json:"resources,omitempty"`
+	DataSources   map[string]*Resource `json:"dataSources,omitempty"`
+}
+
+// Resource is the serialized schema of a resource, data source or nested block.
+type Resource struct {
+	Schema             map[string]*Schema `json:"schema,omitempty"`
+	SchemaVersion      int                `json:"schemaVersion,omitempty"`
+	DeprecationMessage string             `json:"deprecated,omitempty"`
+	Timeouts           *Timeouts          `json:"timeouts,omitempty"`
+}
+
+// Timeouts are the default timeouts of a resource.
+type Timeouts struct {
+	Create  *time.Duration `json:"create,omitempty"`
+	Read    *time.Duration `json:"read,omitempty"`
+	Update  *time.Duration `json:"update,omitempty"`
+	Delete  *time.Duration `json:"delete,omitempty"`
+	Default *time.Duration `json:"default,omitempty"`
+}
+
+// Schema is the serialized schema of an attribute.
+type Schema struct {
+	Type      shim.ValueType `json:"type"`
+	Optional  bool           `json:"optional,omitempty"`
+	Required  bool           `json:"required,omitempty"`
+	Computed  bool           `json:"computed,omitempty"`
+	ForceNew  bool           `json:"forceNew,omitempty"`
+	Sensitive bool           `json:"sensitive,omitempty"`
+
+	// The static default value of the attribute. Default functions are not recorded, since they may read the
+	// environment of the machine taking the snapshot.
+	Default interface{} `json:"default,omitempty"`
+
+	Description   string   `json:"description,omitempty"`
+	Elem          *Elem    `json:"element,omitempty"`
+	MaxItems      int      `json:"maxItems,omitempty"`
+	MinItems      int      `json:"minItems,omitempty"`
+	ConflictsWith []string `json:"conflictsWith,omitempty"`
+	ExactlyOneOf  []string `json:"exactlyOneOf,omitempty"`
+	Deprecated    string   `json:"deprecated,omitempty"`
+	Removed       string   `json:"removed,omitempty"`
+}
+
+// Elem is the serialized element of an attribute. See [shim.Schema.Elem].
+type Elem struct {
+	Schema   *Schema   `json:"schema,omitempty"`
+	Resource *Resource `json:"resource,omitempty"`
+}
+
+// Take snapshots the schema of p.
+func Take(p shim.Provider) *Provider {
+	return &Provider{
+		FormatVersion: FormatVersion,
+		Schema:        takeSchemaMap(p.Schema()),
+		Resources:     takeResourceMap(p.ResourcesMap()),
+		DataSources:   takeResourceMap(p.DataSourcesMap()),
+	}
+}
+
+func takeResourceMap(m shim.ResourceMap) map[string]*Resource {
+	result := make(map[string]*Resource, m.Len())
+	m.Range(func(key string, r shim.Resource) bool {
+		result[key] = TakeResource(r)
+		return true
+	})
+	return result
+}
+
+// TakeResource snapshots the schema of a single resource or data source.
+func TakeResource(r shim.Resource) *Resource {
+	result := &Resource{
+		Schema:             takeSchemaMap(r.Schema()),
+		SchemaVersion:      optional(r.SchemaVersion),
+		DeprecationMessage: optional(r.DeprecationMessage),
+	}
+	if t := optional(r.Timeouts); t != nil {
+		result.Timeouts = &Timeouts{
+			Create:  t.Create,
+			Read:    t.Read,
+			Update:  t.Update,
+			Delete:  t.Delete,
+			Default: t.Default,
+		}
+	}
+	return result
+}
+
+func takeSchemaMap(m shim.SchemaMap) map[string]*Schema {
+	if m == nil {
+		return nil
+	}
+	result := make(map[string]*Schema, m.Len())
+	m.Range(func(key string, s shim.Schema) bool {
+		result[key] = takeSchema(s)
+		return true
+	})
+	return result
+}
+
+// optional returns the result of get, or the zero value if get panics. Plugin Framework providers panic on the
+// parts of the shim they do not have, such as static defaults, ConflictsWith and ExactlyOneOf, or the schema versions
+// and timeouts of resources.
+func optional[T any](get func() T) (v T) {
+	defer func() {
+		if recover() != nil {
+			var zero T
+			v = zero
+		}
+	}()
+	return get()
+}
+
+func takeSchema(s shim.Schema) *Schema {
+	result := &Schema{
+		Type:          s.Type(),
+		Optional:      s.Optional(),
+		Required:      s.Required(),
+		Computed:      s.Computed(),
+		ForceNew:      s.ForceNew(),
+		Sensitive:     s.Sensitive(),
+		Default:       optional(s.Default),
+		Description:   s.Description(),
+		MaxItems:      s.MaxItems(),
+		MinItems:      s.MinItems(),
+		ConflictsWith: optional(s.ConflictsWith),
+		ExactlyOneOf:  optional(s.ExactlyOneOf),
+		Deprecated:    s.Deprecated(),
+		Removed:       optional(s.Removed),
+	}
+	switch e := s.Elem().(type) {
+	case shim.Schema:
+		result.Elem = &Elem{Schema: takeSchema(e)}
+	case shim.Resource:
+		result.Elem = &Elem{Resource: TakeResource(e)}
+	}
+	return result
+}
+
+// Shim returns a schema-only provider with the schema of the snapshot.
+func (p *Provider) Shim() shim.Provider {
+	resources := schema.ResourceMap{}
+	for k, r := range p.Resources {
+		resources[k] = r.shim()
+	}
+	dataSources := schema.ResourceMap{}
+	for k, r := range p.DataSources {
+		dataSources[k] = r.shim()
+	}
+	return (&schema.Provider{
+		Schema:         shimSchemaMap(p.Schema),
+		ResourcesMap:   resources,
+		DataSourcesMap: dataSources,
+	}).Shim()
+}
+
+func (r *Resource) shim() shim.Resource {
+	result := &schema.Resource{
+		Schema:             shimSchemaMap(r.Schema),
+		SchemaVersion:      r.SchemaVersion,
+		DeprecationMessage: r.DeprecationMessage,
+	}
+	if t := r.Timeouts; t != nil {
+		result.Timeouts = &shim.ResourceTimeout{
+			Create:  t.Create,
+			Read:    t.Read,
+			Update:  t.Update,
+			Delete:  t.Delete,
+			Default: t.Default,
+		}
+	}
+	return result.Shim()
+}
+
+func shimSchemaMap(m map[string]*Schema) shim.SchemaMap {
+	result := schema.SchemaMap{}
+	for k, s := range m {
+		result[k] = s.shim()
+	}
+	return result
+}
+
+func (s *Schema) shim() shim.Schema {
+	result := &schema.Schema{
+		Type:          s.Type,
+		Optional:      s.Optional,
+		Required:      s.Required,
+		Computed:      s.Computed,
+		ForceNew:      s.ForceNew,
+		Sensitive:     s.Sensitive,
+		Default:       s.defaultValue(),
+		Description:   s.Description,
+		MaxItems:      s.MaxItems,
+		MinItems:      s.MinItems,
+		ConflictsWith: s.ConflictsWith,
+		ExactlyOneOf:  s.ExactlyOneOf,
+		Deprecated:    s.Deprecated,
+		Removed:       s.Removed,
+	}
+	switch {
+	case s.Elem == nil:
+	case s.Elem.Schema != nil:
+		result.Elem = s.Elem.Schema.shim()
+	case s.Elem.Resource != nil:
+		result.Elem = s.Elem.Resource.shim()
+	}
+	return result.Shim()
+}
+
+// defaultValue restores the Go type of numeric defaults, which are decoded from JSON as float64.
+func (s *Schema) defaultValue() interface{} {
+	if f, ok := s.Default.(float64); ok && s.Type == shim.TypeInt {
+		return int(f)
+	}
+	return s.Default
+}
+
+// Marshal snapshots the schema of p in the format read by ReadFile.
+func Marshal(p shim.Provider) ([]byte, error) {
+	bytes, err := json.MarshalIndent(Take(p), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(bytes, '\n'), nil
+}
+
+// WriteFile snapshots the schema of p to the file at path.
+func WriteFile(path string, p shim.Provider) error {
+	bytes, err := Marshal(p)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, bytes, 0o600)
+}
+
+// ReadFile loads a provider from a snapshot written by WriteFile.
+func ReadFile(path string) (shim.Provider, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot Provider
+	if err := json.Unmarshal(bytes, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to read the provider snapshot %s: %w", path, err)
+	}
+	if snapshot.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("unsupported provider snapshot format version %d in %s, expected %d",
+			snapshot.FormatVersion, path, FormatVersion)
+	}
+	return snapshot.Shim(), nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+func TestRoundTrip(t *testing.T) {
+	create := 10 * time.Minute
+	p := (&schema.Provider{
+		Schema: schema.SchemaMap{
+			"region": (&schema.Schema{
+				Type:        shim.TypeString,
+				Optional:    true,
+				Description: "The region.",
+				DefaultFunc: func() (interface{}, error) { return "us-east-1", nil },
+			}).Shim(),
+		},
+		ResourcesMap: schema.ResourceMap{
+			"test_res": (&schema.Resource{
+				SchemaVersion:      2,
+				DeprecationMessage: "use test_other",
+				Timeouts:           &shim.ResourceTimeout{Create: &create},
+				Schema: schema.SchemaMap{
+					"port": (&schema.Schema{Type: shim.TypeInt, Optional: true, Default: 80}).Shim(),
+					"password": (&schema.Schema{
+						Type:      shim.TypeString,
+						Required:  true,
+						Sensitive: true,
+						ForceNew:  true,
+					}).Shim(),
+					"rule": (&schema.Schema{
+						Type:     shim.TypeList,
+						Optional: true,
+						MaxItems: 1,
+						Elem: (&schema.Resource{
+							Schema: schema.SchemaMap{
+								"tags": (&schema.Schema{
+									Type:          shim.TypeSet,
+									Optional:      true,
+									Elem:          (&schema.Schema{Type: shim.TypeString}).Shim(),
+									ConflictsWith: []string{"rule.0.name"},
+									Deprecated:    "tags are ignored",
+								}).Shim(),
+							},
+						}).Shim(),
+					}).Shim(),
+				},
+			}).Shim(),
+		},
+		DataSourcesMap: schema.ResourceMap{
+			"test_data": (&schema.Resource{
+				Schema: schema.SchemaMap{
+					"id": (&schema.Schema{Type: shim.TypeString, Computed: true}).Shim(),
+				},
+			}).Shim(),
+		},
+	}).Shim()
+
+	path := filepath.Join(t.TempDir(), "shim.json")
+	require.NoError(t, WriteFile(path, p))
+	loaded, err := ReadFile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, Take(p), Take(loaded))
+
+	res, ok := loaded.ResourcesMap().GetOk("test_res")
+	require.True(t, ok)
+	assert.Equal(t, 2, res.SchemaVersion())
+	assert.Equal(t, &create, res.Timeouts().Create)
+	port, ok := res.Schema().GetOk("port")
+	require.True(t, ok)
+	dv, err := port.DefaultValue()
+	require.NoError(t, err)
+	assert.Equal(t, 80, dv)
+
+	// Default functions may read the environment, so they are not recorded.
+	region, ok := loaded.Schema().GetOk("region")
+	require.True(t, ok)
+	dv, err = region.DefaultValue()
+	require.NoError(t, err)
+	assert.Nil(t, dv)
+}
+
+// Plugin Framework shims panic on the parts of the schema they do not have.
+type pfLikeResource struct{ shim.Resource }
+
+func (pfLikeResource) SchemaVersion() int              { panic("SchemaVersion is not supported") }
+func (pfLikeResource) Timeouts() *shim.ResourceTimeout { panic("Timeouts is not supported") }
+
+type pfLikeSchema struct{ shim.Schema }
+
+func (pfLikeSchema) Default() interface{}    { panic("Default is not supported") }
+func (pfLikeSchema) ConflictsWith() []string { panic("ConflictsWith is not supported") }
+func (pfLikeSchema) ExactlyOneOf() []string  { panic("ExactlyOneOf is not supported") }
+
+func TestTakePluginFrameworkLike(t *testing.T) {
+	p := (&schema.Provider{
+		DataSourcesMap: schema.ResourceMap{
+			"test_data": pfLikeResource{(&schema.Resource{
+				Schema: schema.SchemaMap{
+					"id": pfLikeSchema{(&schema.Schema{Type: shim.TypeString, Computed: true}).Shim()},
+				},
+			}).Shim()},
+		},
+	}).Shim()
+
+	assert.Equal(t, &Resource{
+		Schema: map[string]*Schema{"id": {Type: shim.TypeString, Computed: true}},
+	}, Take(p).DataSources["test_data"])
+}
+
+func TestReadFileVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shim.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"formatVersion": 99}`), 0o600))
+	_, err := ReadFile(path)
+	assert.ErrorContains(t, err, "unsupported provider snapshot format version 99")
+}
@@ -0,0 +1,136 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfplugin5
+
+import (
+	"github.com/hashicorp/go-cty/cty"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/internal/tfplugin"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+// The value conversions and the timeouts key used by the provider tests.
+var (
+	ctyToGo = tfplugin.CtyToGo
+	goToCty = tfplugin.GoToCty
+)
+
+const timeoutsKey = tfplugin.TimeoutsKey
+
+// attributeSchema and resource describe the expected schemas in the provider tests. They mirror tfplugin.Schema and
+// tfplugin.Resource, leaving out the fields that the tests do not compare. The embedded interfaces are never called.
+type attributeSchema struct {
+	shim.Schema
+
+	ctyType     cty.Type
+	valueType   shim.ValueType
+	optional    bool
+	required    bool
+	description string
+	computed    bool
+	forceNew    bool
+	elem        interface{}
+	maxItems    int
+	minItems    int
+	deprecated  string
+	sensitive   bool
+}
+
+type resource struct {
+	shim.Resource
+
+	resourceType  string
+	ctyType       cty.Type
+	schema        schema.SchemaMap
+	schemaVersion int
+}
+
+// instanceDiff describes the expected diffs in the provider tests. It mirrors tfplugin.InstanceDiff.
+type instanceDiff struct {
+	config      cty.Value
+	planned     cty.Value
+	meta        map[string]interface{}
+	destroy     bool
+	requiresNew bool
+	attributes  map[string]shim.ResourceAttrDiff
+}
+
+// testInstanceDiff converts a diff of the provider to the shape of the expected diffs.
+func testInstanceDiff(d shim.InstanceDiff) *instanceDiff {
+	td := d.(*tfplugin.InstanceDiff)
+	return &instanceDiff{
+		config:      td.Config,
+		planned:     td.Planned,
+		meta:        td.Meta,
+		destroy:     td.IsDestroy,
+		requiresNew: td.IsRequiresNew,
+		attributes:  td.AttributeDiffs,
+	}
+}
+
+// testSchema converts a schema of the provider to the shape of the expected schemas.
+func testSchema(s shim.Schema) *attributeSchema {
+	ts := s.(*tfplugin.Schema)
+	if ts == nil {
+		return nil
+	}
+	return &attributeSchema{
+		ctyType:     ts.CtyType,
+		valueType:   ts.ValueType,
+		optional:    ts.Attribute.Optional,
+		required:    ts.Attribute.Required,
+		description: ts.Attribute.Description,
+		computed:    ts.Attribute.Computed,
+		forceNew:    ts.Attribute.ForceNew,
+		elem:        testElem(ts.Attribute.Elem),
+		maxItems:    ts.Attribute.MaxItems,
+		minItems:    ts.Attribute.MinItems,
+		deprecated:  ts.Attribute.Deprecated,
+		sensitive:   ts.Attribute.Sensitive,
+	}
+}
+
+// testResource converts a resource of the provider to the shape of the expected resources.
+func testResource(r shim.Resource) *resource {
+	tr := r.(*tfplugin.Resource)
+	if tr == nil {
+		return nil
+	}
+	var sm schema.SchemaMap
+	if tr.SchemaMap != nil {
+		sm = schema.SchemaMap{}
+		for k, v := range tr.SchemaMap {
+			sm[k] = testSchema(v)
+		}
+	}
+	return &resource{
+		resourceType:  tr.ResourceType,
+		ctyType:       tr.CtyType,
+		schema:        sm,
+		schemaVersion: tr.Version,
+	}
+}
+
+func testElem(elem interface{}) interface{} {
+	switch elem := elem.(type) {
+	case *tfplugin.Schema:
+		return testSchema(elem)
+	case *tfplugin.Resource:
+		return testResource(elem)
+	default:
+		return elem
+	}
+}
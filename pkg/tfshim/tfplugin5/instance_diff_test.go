@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/internal/tfplugin"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/tfplugin5/proto"
 )
 
@@ -82,23 +83,23 @@ func diffTest(t *testing.T, attributes map[string]cty.Type, requiresReplace []*p
 		resolvePath(p, objectType)
 	}
 
-	priorVal, err := goToCty(prior, objectType)
+	priorVal, err := tfplugin.GoToCty(prior, objectType)
 	if !assert.NoError(t, err) {
 		return
 	}
-	plannedVal, err := goToCty(planned, objectType)
+	plannedVal, err := tfplugin.GoToCty(planned, objectType)
 	if !assert.NoError(t, err) {
 		return
 	}
 
-	expectedDiff := &instanceDiff{
-		config:     plannedVal,
-		planned:    plannedVal,
-		attributes: expected,
+	expectedDiff := &tfplugin.InstanceDiff{
+		Config:         plannedVal,
+		Planned:        plannedVal,
+		AttributeDiffs: expected,
 	}
 	for _, v := range expected {
 		if v.RequiresNew {
-			expectedDiff.requiresNew = true
+			expectedDiff.IsRequiresNew = true
 			break
 		}
 	}
@@ -5,6 +5,7 @@ import (
 	"github.com/hashicorp/go-cty/cty/msgpack"
 
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/internal/tfplugin"
 )
 
 var _ = shim.InstanceState((*instanceState)(nil))
@@ -41,7 +42,7 @@ func (s *instanceState) getObject() map[string]interface{} {
 }
 
 func (s *instanceState) marshal(ty cty.Type) ([]byte, error) {
-	val, err := goToCty(s.getObject(), ty)
+	val, err := tfplugin.GoToCty(s.getObject(), ty)
 	if err != nil {
 		return nil, err
 	}
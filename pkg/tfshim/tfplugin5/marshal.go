@@ -5,117 +5,20 @@ import (
 	fmt "fmt"
 
 	"github.com/hashicorp/go-cty/cty"
-	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/internal/tfplugin"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/tfplugin5/proto"
 )
 
-func deprecationMessage(propertyName string, isDeprecated bool) string {
-	if isDeprecated {
-		return fmt.Sprintf("%v is deprecated", propertyName)
-	}
-	return ""
-}
-
-func unmarshalNestedType(elementType cty.Type) (interface{}, error) {
-	valueType, elem, err := unmarshalType(elementType)
-	if err != nil {
-		return nil, err
-	}
-
-	switch valueType {
-	case shim.TypeBool, shim.TypeInt, shim.TypeFloat, shim.TypeString:
-		return &attributeSchema{
-			ctyType:   elementType,
-			valueType: valueType,
-		}, nil
-	case shim.TypeList, shim.TypeSet:
-		return &attributeSchema{
-			ctyType:   elementType,
-			valueType: valueType,
-			elem:      elem,
-		}, nil
-	case shim.TypeMap:
-		if r, ok := elem.(*resource); ok {
-			return r, nil
-		}
-		return &attributeSchema{
-			ctyType:   elementType,
-			valueType: valueType,
-			elem:      elem,
-		}, nil
-	default:
-		return nil, fmt.Errorf("unexpected value type %v", valueType)
-	}
-}
-
-func unmarshalCompositeType(ty cty.Type) (shim.ValueType, interface{}, error) {
-	switch {
-	case ty.IsListType():
-		elementType, err := unmarshalNestedType(ty.ElementType())
-		if err != nil {
-			return shim.TypeInvalid, nil, err
-		}
-		return shim.TypeList, elementType, nil
-	case ty.IsMapType():
-		elementType, err := unmarshalNestedType(ty.ElementType())
-		if err != nil {
-			return shim.TypeInvalid, nil, err
-		}
-		return shim.TypeMap, elementType, nil
-	case ty.IsSetType():
-		elementType, err := unmarshalNestedType(ty.ElementType())
-		if err != nil {
-			return shim.TypeInvalid, nil, err
-		}
-		return shim.TypeSet, elementType, nil
-	case ty.IsObjectType():
-		properties := schema.SchemaMap{}
-		for name, ty := range ty.AttributeTypes() {
-			property, err := unmarshalNestedType(ty)
-			if err != nil {
-				return shim.TypeInvalid, nil, err
-			}
-			if s, ok := property.(*attributeSchema); ok {
-				properties[name] = s
-			} else {
-				r, isResource := property.(*resource)
-				contract.Assertf(isResource, "isResource")
-				properties[name] = &attributeSchema{
-					ctyType:   r.ctyType,
-					valueType: shim.TypeMap,
-					elem:      s,
-				}
-			}
-		}
-		return shim.TypeMap, &resource{ctyType: ty, schema: properties}, nil
-	default:
-		return shim.TypeInvalid, nil, fmt.Errorf("unexpected composite type %v", ty)
-	}
-}
-
-func unmarshalType(ty cty.Type) (shim.ValueType, interface{}, error) {
-	switch ty {
-	case cty.String:
-		return shim.TypeString, nil, nil
-	case cty.Bool:
-		return shim.TypeBool, nil, nil
-	case cty.Number:
-		return shim.TypeFloat, nil, nil
-	default:
-		return unmarshalCompositeType(ty)
-	}
-}
-
-func unmarshalAttribute(attribute *proto.Schema_Attribute) (*attributeSchema, error) {
+func unmarshalAttribute(attribute *proto.Schema_Attribute) (*tfplugin.Schema, error) {
 	var ty cty.Type
 	if err := json.Unmarshal(attribute.Type, &ty); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal type: %w", err)
 	}
 
-	valueType, elem, err := unmarshalType(ty)
+	valueType, elem, err := tfplugin.UnmarshalType(ty)
 	if err != nil {
 		return nil, err
 	}
@@ -125,17 +28,17 @@ func unmarshalAttribute(attribute *proto.Schema_Attribute) (*attributeSchema, er
 		optional = true
 	}
 
-	return &attributeSchema{
-		ctyType:     ty,
-		valueType:   valueType,
-		elem:        elem,
-		description: attribute.Description,
-		required:    attribute.Required,
-		optional:    optional,
-		computed:    attribute.Computed,
-		sensitive:   attribute.Sensitive,
-		deprecated:  deprecationMessage(attribute.Name, attribute.Deprecated),
-	}, nil
+	return &tfplugin.Schema{Attribute: tfplugin.Attribute{
+		CtyType:     ty,
+		ValueType:   valueType,
+		Elem:        elem,
+		Description: attribute.Description,
+		Required:    attribute.Required,
+		Optional:    optional,
+		Computed:    attribute.Computed,
+		Sensitive:   attribute.Sensitive,
+		Deprecated:  tfplugin.DeprecationMessage(attribute.Name, attribute.Deprecated),
+	}}, nil
 }
 
 func unmarshalBlock(block *proto.Schema_Block) (cty.Type, schema.SchemaMap, bool, error) {
@@ -145,7 +48,7 @@ func unmarshalBlock(block *proto.Schema_Block) (cty.Type, schema.SchemaMap, bool
 		if err != nil {
 			return cty.Type{}, nil, false, err
 		}
-		attributes[attribute.Name], properties[attribute.Name] = property.ctyType, property
+		attributes[attribute.Name], properties[attribute.Name] = property.CtyType, property
 		if !property.Computed() {
 			allComputed = false
 		}
@@ -155,7 +58,7 @@ func unmarshalBlock(block *proto.Schema_Block) (cty.Type, schema.SchemaMap, bool
 		if err != nil {
 			return cty.Type{}, nil, false, err
 		}
-		attributes[nestedBlock.TypeName], properties[nestedBlock.TypeName] = property.ctyType, property
+		attributes[nestedBlock.TypeName], properties[nestedBlock.TypeName] = property.CtyType, property
 		if !property.Computed() {
 			allComputed = false
 		}
@@ -163,7 +66,7 @@ func unmarshalBlock(block *proto.Schema_Block) (cty.Type, schema.SchemaMap, bool
 	return cty.Object(attributes), properties, allComputed, nil
 }
 
-func unmarshalNestedBlock(nestedBlock *proto.Schema_NestedBlock) (*attributeSchema, error) {
+func unmarshalNestedBlock(nestedBlock *proto.Schema_NestedBlock) (*tfplugin.Schema, error) {
 	objectType, properties, computed, err := unmarshalBlock(nestedBlock.Block)
 	if err != nil {
 		return nil, err
@@ -187,21 +90,21 @@ func unmarshalNestedBlock(nestedBlock *proto.Schema_NestedBlock) (*attributeSche
 			required, optional = nestedBlock.MinItems > 0, nestedBlock.MinItems == 0
 		}
 	}
-	return &attributeSchema{
-		ctyType:     ctyType,
-		valueType:   valueType,
-		elem:        &resource{ctyType: objectType, schema: properties},
-		description: nestedBlock.Block.Description,
-		required:    required,
-		optional:    optional,
-		computed:    computed,
-		deprecated:  deprecationMessage(nestedBlock.TypeName, nestedBlock.Block.Deprecated),
-		minItems:    int(nestedBlock.MinItems),
-		maxItems:    int(nestedBlock.MaxItems),
-	}, nil
+	return &tfplugin.Schema{Attribute: tfplugin.Attribute{
+		CtyType:     ctyType,
+		ValueType:   valueType,
+		Elem:        &tfplugin.Resource{CtyType: objectType, SchemaMap: properties},
+		Description: nestedBlock.Block.Description,
+		Required:    required,
+		Optional:    optional,
+		Computed:    computed,
+		Deprecated:  tfplugin.DeprecationMessage(nestedBlock.TypeName, nestedBlock.Block.Deprecated),
+		MinItems:    int(nestedBlock.MinItems),
+		MaxItems:    int(nestedBlock.MaxItems),
+	}}, nil
 }
 
-func unmarshalResource(p *provider, typeName string, resourceSchema *proto.Schema) (*resource, error) {
+func unmarshalResource(p *provider, typeName string, resourceSchema *proto.Schema) (*tfplugin.Resource, error) {
 	ctyType, properties, _, err := unmarshalBlock(resourceSchema.Block)
 	if err != nil {
 		return nil, err
@@ -209,23 +112,29 @@ func unmarshalResource(p *provider, typeName string, resourceSchema *proto.Schem
 
 	// Ensure that `id` is treated as a pure output property.
 	if id, ok := properties["id"]; ok {
-		schema := id.(*attributeSchema)
-		schema.optional = false
-		schema.required = false
-		schema.computed = true
+		schema := id.(*tfplugin.Schema)
+		schema.Attribute.Optional = false
+		schema.Attribute.Required = false
+		schema.Attribute.Computed = true
 	}
 
-	return &resource{
-		provider:      p,
-		resourceType:  typeName,
-		ctyType:       ctyType,
-		schema:        properties,
-		schemaVersion: int(resourceSchema.Version),
-	}, nil
+	r := &tfplugin.Resource{
+		ResourceType: typeName,
+		CtyType:      ctyType,
+		SchemaMap:    properties,
+		Version:      int(resourceSchema.Version),
+		NewState: func(id string, object, meta map[string]interface{}) shim.InstanceState {
+			return &instanceState{resourceType: typeName, id: id, object: object, meta: meta}
+		},
+	}
+	if p != nil {
+		r.Import = p.importResourceState
+	}
+	return r, nil
 }
 
-func unmarshalResourceMap(p *provider, resources map[string]*proto.Schema) (resourceMap, error) {
-	resourceMap := resourceMap{}
+func unmarshalResourceMap(p *provider, resources map[string]*proto.Schema) (tfplugin.ResourceMap, error) {
+	resourceMap := tfplugin.ResourceMap{}
 	for name, schema := range resources {
 		r, err := unmarshalResource(p, name, schema)
 		if err != nil {
@@ -8,8 +8,10 @@ import (
 
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/go-plugin"
 
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/internal/tfplugin"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/tfplugin5/proto"
 )
 
@@ -17,11 +19,16 @@ type provider struct {
 	client           proto.ProviderClient
 	terraformVersion string
 
-	resources   resourceMap
-	dataSources resourceMap
-	config      *resource
+	resources   tfplugin.ResourceMap
+	dataSources tfplugin.ResourceMap
+	config      *tfplugin.Resource
+
+	// pluginClient manages the provider process if the provider was launched by StartProvider.
+	pluginClient *plugin.Client
 }
 
+var _ = shim.StateUpgrader((*provider)(nil))
+
 func NewProvider(ctx context.Context, client proto.ProviderClient, terraformVersion string) (shim.Provider, error) {
 	schemaResponse, err := client.GetSchema(ctx, &proto.GetProviderSchema_Request{})
 	if err != nil {
@@ -56,7 +63,7 @@ func NewProvider(ctx context.Context, client proto.ProviderClient, terraformVers
 	return p, nil
 }
 
-func (p *provider) decodeState(resource *resource, s *instanceState,
+func (p *provider) decodeState(resource *tfplugin.Resource, s *instanceState,
 	val cty.Value, meta map[string]interface{}) (shim.InstanceState, error) {
 
 	if !val.Type().IsObjectType() || !val.IsKnown() {
@@ -68,7 +75,7 @@ func (p *provider) decodeState(resource *resource, s *instanceState,
 	}
 
 	if s == nil {
-		s = &instanceState{resourceType: resource.resourceType}
+		s = &instanceState{resourceType: resource.ResourceType}
 	}
 
 	if val.IsNull() {
@@ -82,7 +89,7 @@ func (p *provider) decodeState(resource *resource, s *instanceState,
 		s.id = idVal.AsString()
 	}
 
-	object, err := ctyToGo(val)
+	object, err := tfplugin.CtyToGo(val)
 	if err != nil {
 		return nil, err
 	}
@@ -90,7 +97,7 @@ func (p *provider) decodeState(resource *resource, s *instanceState,
 	return s, nil
 }
 
-func (p *provider) upgradeResourceState(resource *resource, s *instanceState) (*instanceState, error) {
+func (p *provider) upgradeResourceState(resource *tfplugin.Resource, s *instanceState) (*instanceState, error) {
 	if s == nil {
 		return nil, nil
 	}
@@ -112,7 +119,7 @@ func (p *provider) upgradeResourceState(resource *resource, s *instanceState) (*
 	}
 
 	resp, err := p.client.UpgradeResourceState(context.TODO(), &proto.UpgradeResourceState_Request{
-		TypeName: resource.resourceType,
+		TypeName: resource.ResourceType,
 		Version:  schemaVersion,
 		RawState: &proto.RawState{Json: stateBytes},
 	})
@@ -123,7 +130,7 @@ func (p *provider) upgradeResourceState(resource *resource, s *instanceState) (*
 		return nil, err
 	}
 
-	upgradedVal, err := msgpack.Unmarshal(resp.UpgradedState.Msgpack, resource.ctyType)
+	upgradedVal, err := msgpack.Unmarshal(resp.UpgradedState.Msgpack, resource.CtyType)
 	if err != nil {
 		return nil, err
 	}
@@ -149,7 +156,7 @@ func (p *provider) importResourceState(t, id string, _ interface{}) ([]shim.Inst
 			return nil, fmt.Errorf("unknown resource type %v", importedResource.TypeName)
 		}
 
-		stateVal, err := msgpack.Unmarshal(importedResource.State.Msgpack, resource.ctyType)
+		stateVal, err := msgpack.Unmarshal(importedResource.State.Msgpack, resource.CtyType)
 		if err != nil {
 			return nil, err
 		}
@@ -168,7 +175,7 @@ func (p *provider) importResourceState(t, id string, _ interface{}) ([]shim.Inst
 }
 
 func (p *provider) Schema() shim.SchemaMap {
-	return p.config.schema
+	return p.config.SchemaMap
 }
 
 func (p *provider) ResourcesMap() shim.ResourceMap {
@@ -184,12 +191,12 @@ func (p *provider) InternalValidate() error {
 }
 
 func (p *provider) Validate(ctx context.Context, c shim.ResourceConfig) ([]string, []error) {
-	config, ok := c.(resourceConfig)
+	config, ok := c.(tfplugin.ResourceConfig)
 	if !ok {
 		return nil, []error{fmt.Errorf("internal error: foreign resource config")}
 	}
 
-	val, err := config.marshal(p.config.ctyType)
+	val, err := marshalConfig(config, p.config.CtyType)
 	if err != nil {
 		return nil, []error{err}
 	}
@@ -205,7 +212,7 @@ func (p *provider) Validate(ctx context.Context, c shim.ResourceConfig) ([]strin
 }
 
 func (p *provider) ValidateResource(ctx context.Context, t string, c shim.ResourceConfig) ([]string, []error) {
-	config, ok := c.(resourceConfig)
+	config, ok := c.(tfplugin.ResourceConfig)
 	if !ok {
 		return nil, []error{fmt.Errorf("internal error: foreign resource config")}
 	}
@@ -215,7 +222,7 @@ func (p *provider) ValidateResource(ctx context.Context, t string, c shim.Resour
 		return nil, []error{fmt.Errorf("unknown resource type %v", t)}
 	}
 
-	val, err := config.marshal(resource.ctyType)
+	val, err := marshalConfig(config, resource.CtyType)
 	if err != nil {
 		return nil, []error{err}
 	}
@@ -232,7 +239,7 @@ func (p *provider) ValidateResource(ctx context.Context, t string, c shim.Resour
 }
 
 func (p *provider) ValidateDataSource(ctx context.Context, t string, c shim.ResourceConfig) ([]string, []error) {
-	config, ok := c.(resourceConfig)
+	config, ok := c.(tfplugin.ResourceConfig)
 	if !ok {
 		return nil, []error{fmt.Errorf("internal error: foreign resource config")}
 	}
@@ -242,7 +249,7 @@ func (p *provider) ValidateDataSource(ctx context.Context, t string, c shim.Reso
 		return nil, []error{fmt.Errorf("unknown data source %v", t)}
 	}
 
-	val, err := config.marshal(dataSource.ctyType)
+	val, err := marshalConfig(config, dataSource.CtyType)
 	if err != nil {
 		return nil, []error{err}
 	}
@@ -259,12 +266,12 @@ func (p *provider) ValidateDataSource(ctx context.Context, t string, c shim.Reso
 }
 
 func (p *provider) Configure(ctx context.Context, c shim.ResourceConfig) error {
-	config, ok := c.(resourceConfig)
+	config, ok := c.(tfplugin.ResourceConfig)
 	if !ok {
 		return fmt.Errorf("internal error: foreign resource config")
 	}
 
-	val, err := config.marshal(p.config.ctyType)
+	val, err := marshalConfig(config, p.config.CtyType)
 	if err != nil {
 		return err
 	}
@@ -292,7 +299,7 @@ func (p *provider) Diff(
 	if s != nil && !ok {
 		return nil, fmt.Errorf("internal error: foreign resource state")
 	}
-	config, ok := c.(resourceConfig)
+	config, ok := c.(tfplugin.ResourceConfig)
 	if !ok {
 		return nil, fmt.Errorf("internal error: foreign resource config")
 	}
@@ -307,16 +314,16 @@ func (p *provider) Diff(
 		return nil, err
 	}
 
-	stateVal, err := goToCty(state.getObject(), resource.ctyType)
+	stateVal, err := tfplugin.GoToCty(state.getObject(), resource.CtyType)
 	if err != nil {
 		return nil, err
 	}
-	configVal, err := goToCty(config, resource.ctyType)
+	configVal, err := tfplugin.GoToCty(config, resource.CtyType)
 	if err != nil {
 		return nil, err
 	}
 
-	stateBytes, err := msgpack.Marshal(stateVal, resource.ctyType)
+	stateBytes, err := msgpack.Marshal(stateVal, resource.CtyType)
 	if err != nil {
 		return nil, err
 	}
@@ -328,13 +335,13 @@ func (p *provider) Diff(
 		}
 		metaBytes = m
 	}
-	configBytes, err := msgpack.Marshal(configVal, resource.ctyType)
+	configBytes, err := msgpack.Marshal(configVal, resource.CtyType)
 	if err != nil {
 		return nil, err
 	}
 
 	resp, err := p.client.PlanResourceChange(context.TODO(), &proto.PlanResourceChange_Request{
-		TypeName:         resource.resourceType,
+		TypeName:         resource.ResourceType,
 		PriorState:       &proto.DynamicValue{Msgpack: stateBytes},
 		ProposedNewState: &proto.DynamicValue{Msgpack: configBytes},
 		Config:           &proto.DynamicValue{Msgpack: configBytes},
@@ -344,7 +351,7 @@ func (p *provider) Diff(
 		return nil, err
 	}
 
-	plannedVal, err := msgpack.Unmarshal(resp.PlannedState.Msgpack, resource.ctyType)
+	plannedVal, err := msgpack.Unmarshal(resp.PlannedState.Msgpack, resource.CtyType)
 	if err != nil {
 		return nil, err
 	}
@@ -364,7 +371,7 @@ func (p *provider) Apply(
 	if s != nil && !ok {
 		return nil, fmt.Errorf("internal error: foreign resource state")
 	}
-	diff, ok := d.(*instanceDiff)
+	diff, ok := d.(*tfplugin.InstanceDiff)
 	if !ok {
 		return nil, fmt.Errorf("internal error: foreign instance diff")
 	}
@@ -379,32 +386,32 @@ func (p *provider) Apply(
 		return nil, err
 	}
 
-	stateBytes, err := state.marshal(resource.ctyType)
+	stateBytes, err := state.marshal(resource.CtyType)
 	if err != nil {
 		return nil, err
 	}
-	if diff.planned == (cty.Value{}) {
-		diff.planned = cty.NullVal(resource.ctyType)
+	if diff.Planned == (cty.Value{}) {
+		diff.Planned = cty.NullVal(resource.CtyType)
 	}
-	plannedStateBytes, err := msgpack.Marshal(diff.planned, resource.ctyType)
+	plannedStateBytes, err := msgpack.Marshal(diff.Planned, resource.CtyType)
 	if err != nil {
 		return nil, err
 	}
-	plannedMetaBytes, err := json.Marshal(diff.meta)
+	plannedMetaBytes, err := json.Marshal(diff.Meta)
 	if err != nil {
 		return nil, err
 	}
 
-	if diff.config == (cty.Value{}) {
-		diff.config = cty.NullVal(resource.ctyType)
+	if diff.Config == (cty.Value{}) {
+		diff.Config = cty.NullVal(resource.CtyType)
 	}
-	configBytes, err := msgpack.Marshal(diff.config, resource.ctyType)
+	configBytes, err := msgpack.Marshal(diff.Config, resource.CtyType)
 	if err != nil {
 		return nil, err
 	}
 
 	resp, err := p.client.ApplyResourceChange(context.TODO(), &proto.ApplyResourceChange_Request{
-		TypeName:       resource.resourceType,
+		TypeName:       resource.ResourceType,
 		PriorState:     &proto.DynamicValue{Msgpack: stateBytes},
 		PlannedState:   &proto.DynamicValue{Msgpack: plannedStateBytes},
 		Config:         &proto.DynamicValue{Msgpack: configBytes},
@@ -414,7 +421,7 @@ func (p *provider) Apply(
 		return nil, err
 	}
 
-	newStateVal, err := msgpack.Unmarshal(resp.NewState.Msgpack, resource.ctyType)
+	newStateVal, err := msgpack.Unmarshal(resp.NewState.Msgpack, resource.CtyType)
 	if err != nil {
 		return nil, err
 	}
@@ -452,7 +459,7 @@ func (p *provider) Refresh(
 		return nil, err
 	}
 
-	stateBytes, err := state.marshal(resource.ctyType)
+	stateBytes, err := state.marshal(resource.CtyType)
 	if err != nil {
 		return nil, err
 	}
@@ -462,7 +469,7 @@ func (p *provider) Refresh(
 	}
 
 	resp, err := p.client.ReadResource(context.TODO(), &proto.ReadResource_Request{
-		TypeName:     resource.resourceType,
+		TypeName:     resource.ResourceType,
 		CurrentState: &proto.DynamicValue{Msgpack: stateBytes},
 		Private:      metaBytes,
 	})
@@ -474,7 +481,7 @@ func (p *provider) Refresh(
 		return nil, unmarshalErrors(resp.Diagnostics)
 	}
 
-	newStateVal, err := msgpack.Unmarshal(resp.NewState.Msgpack, resource.ctyType)
+	newStateVal, err := msgpack.Unmarshal(resp.NewState.Msgpack, resource.CtyType)
 	if err != nil {
 		return nil, err
 	}
@@ -494,22 +501,43 @@ func (p *provider) Refresh(
 	return newState, unmarshalErrors(resp.Diagnostics)
 }
 
+// UpgradeState implements shim.StateUpgrader by sending rawState to the provider's UpgradeResourceState.
+func (p *provider) UpgradeState(
+	_ context.Context, t string, version int, rawState map[string]interface{},
+) (shim.InstanceState, error) {
+	resource, ok := p.resources[t]
+	if !ok {
+		return nil, fmt.Errorf("unknown resource type %v", t)
+	}
+
+	state, err := p.upgradeResourceState(resource, &instanceState{
+		resourceType: t,
+		object:       rawState,
+		meta:         map[string]interface{}{"schema_version": strconv.Itoa(version)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	state.meta = map[string]interface{}{"schema_version": strconv.Itoa(resource.Version)}
+	return state, nil
+}
+
 func (p *provider) ReadDataDiff(ctx context.Context, t string, c shim.ResourceConfig) (shim.InstanceDiff, error) {
 	dataSource, ok := p.dataSources[t]
 	if !ok {
 		return nil, fmt.Errorf("unknown data source %v", t)
 	}
 
-	planned, err := goToCty(c, dataSource.ctyType)
+	planned, err := tfplugin.GoToCty(c, dataSource.CtyType)
 	if err != nil {
 		return nil, err
 	}
 
-	return &instanceDiff{planned: planned}, nil
+	return &tfplugin.InstanceDiff{Planned: planned}, nil
 }
 
 func (p *provider) ReadDataApply(ctx context.Context, t string, d shim.InstanceDiff) (shim.InstanceState, error) {
-	diff, ok := d.(*instanceDiff)
+	diff, ok := d.(*tfplugin.InstanceDiff)
 	if d != nil && !ok {
 		return nil, fmt.Errorf("internal error: foreign instance diff")
 	}
@@ -519,7 +547,7 @@ func (p *provider) ReadDataApply(ctx context.Context, t string, d shim.InstanceD
 		return nil, fmt.Errorf("unknown data source %v", t)
 	}
 
-	configBytes, err := msgpack.Marshal(diff.planned, dataSource.ctyType)
+	configBytes, err := msgpack.Marshal(diff.Planned, dataSource.CtyType)
 	if err != nil {
 		return nil, err
 	}
@@ -532,7 +560,7 @@ func (p *provider) ReadDataApply(ctx context.Context, t string, d shim.InstanceD
 		return nil, err
 	}
 
-	stateVal, err := msgpack.Unmarshal(resp.State.Msgpack, dataSource.ctyType)
+	stateVal, err := msgpack.Unmarshal(resp.State.Msgpack, dataSource.CtyType)
 	if err != nil {
 		return nil, err
 	}
@@ -545,12 +573,12 @@ func (p *provider) Meta(ctx context.Context) interface{} {
 }
 
 func (p *provider) Stop(ctx context.Context) error {
-	resp, err := p.client.Stop(context.TODO(), &proto.Stop_Request{})
+	resp, err := p.client.Stop(ctx, &proto.Stop_Request{})
 	switch {
 	case err != nil:
 		return err
 	case resp.Error != "":
-		return fmt.Errorf("%s", err)
+		return fmt.Errorf("%s", resp.Error)
 	default:
 		return nil
 	}
@@ -561,13 +589,13 @@ func (p *provider) InitLogging(ctx context.Context) {
 }
 
 func (p *provider) NewDestroyDiff(ctx context.Context, t string, opts shim.TimeoutOptions) shim.InstanceDiff {
-	d := &instanceDiff{destroy: true}
-	d.applyTimeoutOptions(opts)
+	d := &tfplugin.InstanceDiff{IsDestroy: true}
+	d.ApplyTimeoutOptions(opts)
 	return d
 }
 
 func (p *provider) NewResourceConfig(ctx context.Context, object map[string]interface{}) shim.ResourceConfig {
-	return resourceConfig(object)
+	return tfplugin.ResourceConfig(object)
 }
 
 func (p *provider) IsSet(ctx context.Context, v interface{}) ([]interface{}, bool) {
@@ -583,7 +611,7 @@ func (p *provider) IsSet(ctx context.Context, v interface{}) ([]interface{}, boo
 	iter := val.ElementIterator()
 	for iter.Next() {
 		v, _ := iter.Element()
-		gv, err := ctyToGo(v)
+		gv, err := tfplugin.CtyToGo(v)
 		if err != nil {
 			// NOTE: this might be worthy of a panic.
 			return nil, false
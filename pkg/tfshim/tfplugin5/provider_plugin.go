@@ -36,21 +36,18 @@ func (p *providerPlugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) e
 	return fmt.Errorf("unsupported")
 }
 
+// StartProvider launches the Terraform provider binary at executablePath, negotiates plugin protocol version 5 with
+// it and returns a shim.Provider that forwards all calls to the running process over gRPC.
+//
+// The provider process is terminated when ctx is cancelled or when the returned provider is passed to
+// [StopProvider], whichever happens first. The TF_LOG environment variable controls the log level of the provider
+// process output, matching the behavior of the Terraform CLI.
+//
+// terraformVersion is the Terraform version the bridge reports to the provider during Configure. If empty, a
+// default version is reported.
 func StartProvider(ctx context.Context, executablePath, terraformVersion string) (shim.Provider, error) {
-	var logger hclog.Logger
-	switch os.Getenv("TF_LOG") {
-	case "TRACE":
-		logger = hclog.New(&hclog.LoggerOptions{Level: hclog.Trace})
-	case "DEBUG":
-		logger = hclog.New(&hclog.LoggerOptions{Level: hclog.Debug})
-	case "INFO":
-		logger = hclog.New(&hclog.LoggerOptions{Level: hclog.Info})
-	case "WARN":
-		logger = hclog.New(&hclog.LoggerOptions{Level: hclog.Warn})
-	case "ERROR":
-		logger = hclog.New(&hclog.LoggerOptions{Level: hclog.Error})
-	default:
-		logger = hclog.NewNullLogger()
+	if _, err := os.Stat(executablePath); err != nil {
+		return nil, fmt.Errorf("cannot find provider executable: %w", err)
 	}
 
 	pluginClient := plugin.NewClient(&plugin.ClientConfig{
@@ -60,7 +57,7 @@ func StartProvider(ctx context.Context, executablePath, terraformVersion string)
 		Managed:          true,
 		AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
 		AutoMTLS:         true,
-		Logger:           logger,
+		Logger:           newLogger(os.Getenv("TF_LOG")),
 	})
 	go func() {
 		<-ctx.Done()
@@ -69,11 +66,53 @@ func StartProvider(ctx context.Context, executablePath, terraformVersion string)
 
 	client, err := pluginClient.Client()
 	if err != nil {
-		return nil, err
+		pluginClient.Kill()
+		return nil, fmt.Errorf("error launching provider %q: %w", executablePath, err)
 	}
-	provider, err := client.Dispense("provider")
+	dispensed, err := client.Dispense("provider")
 	if err != nil {
-		return nil, err
+		pluginClient.Kill()
+		return nil, fmt.Errorf("error connecting to provider %q: %w", executablePath, err)
+	}
+
+	p := dispensed.(*provider)
+	p.pluginClient = pluginClient
+	return p, nil
+}
+
+// StopProvider gracefully stops a provider started by [StartProvider] and terminates its process. Calling
+// StopProvider on a provider that was not started by StartProvider, or calling it more than once, is a no-op.
+func StopProvider(ctx context.Context, p shim.Provider) error {
+	prov, ok := p.(*provider)
+	if !ok || prov.pluginClient == nil || prov.pluginClient.Exited() {
+		return nil
+	}
+	err := prov.Stop(ctx)
+	prov.pluginClient.Kill()
+	return err
+}
+
+// Close stops the provider process if the provider was started by [StartProvider]. See [StopProvider].
+//
+// Close lets callers that must not import this package, such as tfbridge.Main, terminate the process through
+// [io.Closer].
+func (p *provider) Close() error {
+	return StopProvider(context.Background(), p)
+}
+
+func newLogger(level string) hclog.Logger {
+	switch level {
+	case "TRACE":
+		return hclog.New(&hclog.LoggerOptions{Level: hclog.Trace})
+	case "DEBUG":
+		return hclog.New(&hclog.LoggerOptions{Level: hclog.Debug})
+	case "INFO":
+		return hclog.New(&hclog.LoggerOptions{Level: hclog.Info})
+	case "WARN":
+		return hclog.New(&hclog.LoggerOptions{Level: hclog.Warn})
+	case "ERROR":
+		return hclog.New(&hclog.LoggerOptions{Level: hclog.Error})
+	default:
+		return hclog.NewNullLogger()
 	}
-	return provider.(shim.Provider), nil
 }
@@ -8,8 +8,10 @@ import (
 	"log"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/go-hclog"
@@ -19,6 +21,7 @@ import (
 
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/diagnostics"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/internal/tfplugin"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 )
@@ -147,8 +150,7 @@ func (l *testLogger) StandardWriter(opts *hclog.StandardLoggerOptions) io.Writer
 	panic("unsupported")
 }
 
-func startTestProvider(t *testing.T) (*provider, bool) {
-	ctx := context.Background()
+func findTestProvider(t *testing.T) string {
 	const testProviderEnv = "PULUMI_TERRAFORM_BRIDGE_TEST_PROVIDER"
 
 	testProviderPath := os.Getenv(testProviderEnv)
@@ -158,6 +160,12 @@ func startTestProvider(t *testing.T) (*provider, bool) {
 		require.NoError(t, err,
 			"Could not find pulumi-terraform-bridge-test-provider on PATH or %v", testProviderEnv)
 	}
+	return testProviderPath
+}
+
+func startTestProvider(t *testing.T) (*provider, bool) {
+	ctx := context.Background()
+	testProviderPath := findTestProvider(t)
 
 	var logger hclog.Logger
 	switch os.Getenv("TF_LOG") {
@@ -201,6 +209,48 @@ func startTestProvider(t *testing.T) (*provider, bool) {
 	return provider, true
 }
 
+func TestStartProvider(t *testing.T) {
+	ctx := context.Background()
+
+	p, err := StartProvider(ctx, findTestProvider(t), "")
+	require.NoError(t, err)
+
+	prov, ok := p.(*provider)
+	require.True(t, ok)
+	require.NotNil(t, prov.pluginClient)
+
+	_, ok = p.ResourcesMap().GetOk("example_resource")
+	assert.True(t, ok)
+	_, ok = p.DataSourcesMap().GetOk("example_resource")
+	assert.True(t, ok)
+
+	require.NoError(t, StopProvider(ctx, p))
+	assert.True(t, prov.pluginClient.Exited())
+
+	// Stopping an already stopped provider is a no-op.
+	require.NoError(t, StopProvider(ctx, p))
+
+	closer, ok := p.(io.Closer)
+	require.True(t, ok)
+	require.NoError(t, closer.Close())
+}
+
+func TestStartProviderCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	p, err := StartProvider(ctx, findTestProvider(t), "")
+	require.NoError(t, err)
+	prov := p.(*provider)
+
+	cancel()
+	assert.Eventually(t, prov.pluginClient.Exited, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestStartProviderMissingExecutable(t *testing.T) {
+	_, err := StartProvider(context.Background(), filepath.Join(t.TempDir(), "terraform-provider-missing"), "")
+	assert.ErrorContains(t, err, "cannot find provider executable")
+}
+
 func TestProviderSchema(t *testing.T) {
 	p, ok := startTestProvider(t)
 	if !ok {
@@ -209,7 +259,7 @@ func TestProviderSchema(t *testing.T) {
 
 	properties := map[string]*attributeSchema{}
 	p.Schema().Range(func(k string, v shim.Schema) bool {
-		properties[k] = v.(*attributeSchema)
+		properties[k] = testSchema(v)
 		return true
 	})
 	assert.Equal(t, map[string]*attributeSchema{
@@ -607,7 +657,7 @@ func TestProviderResourcesMap(t *testing.T) {
 		names[name] = true
 
 		// Ignore the provider field of both resources.
-		actual := v.(*resource)
+		actual := testResource(v)
 		assert.Equal(t, expected.resourceType, actual.resourceType)
 		assert.Equal(t, expected.ctyType, actual.ctyType)
 		assert.Equal(t, expected.schema, actual.schema)
@@ -760,7 +810,7 @@ func TestProviderDataSourcesMap(t *testing.T) {
 		names[name] = true
 
 		// Ignore the provider field of both resources.
-		actual := v.(*resource)
+		actual := testResource(v)
 		assert.Equal(t, expected.resourceType, actual.resourceType)
 		assert.Equal(t, expected.ctyType, actual.ctyType)
 		assert.Equal(t, expected.schema, actual.schema)
@@ -1039,7 +1089,7 @@ func TestDiff(t *testing.T) {
 			require.NoError(t, err)
 
 			config := p.NewResourceConfig(ctx, c.config)
-			configVal, err := goToCty(config, res.(*resource).ctyType)
+			configVal, err := goToCty(config, res.(*tfplugin.Resource).CtyType)
 			require.NoError(t, err)
 
 			diff, err := p.Diff(ctx, "example_resource", state, config, shim.DiffOptions{})
@@ -1061,7 +1111,7 @@ func TestDiff(t *testing.T) {
 				attributes:  c.attributes,
 				requiresNew: requiresNew,
 				meta:        meta,
-			}, diff)
+			}, testInstanceDiff(diff))
 		})
 	}
 }
@@ -1348,7 +1398,7 @@ func TestReadDataDiff(t *testing.T) {
 		"string_with_bad_interpolation": cty.NullVal(cty.String),
 	})
 
-	assert.Equal(t, &instanceDiff{planned: expected}, diff)
+	assert.Equal(t, &instanceDiff{planned: expected}, testInstanceDiff(diff))
 }
 
 func TestReadDataApply(t *testing.T) {
@@ -3,17 +3,12 @@ package tfplugin5
 import (
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/go-cty/cty/msgpack"
-)
-
-type resourceConfig map[string]interface{}
 
-func (c resourceConfig) IsSet(k string) bool {
-	_, ok := c[k]
-	return ok
-}
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/internal/tfplugin"
+)
 
-func (c resourceConfig) marshal(ty cty.Type) ([]byte, error) {
-	val, err := goToCty(c, ty)
+func marshalConfig(c tfplugin.ResourceConfig, ty cty.Type) ([]byte, error) {
+	val, err := tfplugin.GoToCty(c, ty)
 	if err != nil {
 		return nil, err
 	}
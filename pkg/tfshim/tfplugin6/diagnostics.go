@@ -0,0 +1,69 @@
+package tfplugin6
+
+import (
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/diagnostics"
+)
+
+// unmarshalWarningsAndErrors converts a set of diagnostics to a list of warnings and a list of errors. Diagnostics
+// with unknown severity will be dropped.
+func unmarshalWarningsAndErrors(diags []*tfprotov6.Diagnostic) ([]string, []error) {
+	var warnings []string
+	var errors []error
+	for _, d := range diags {
+		switch d.Severity {
+		case tfprotov6.DiagnosticSeverityError:
+			errors = append(errors, fromTF6Diag(d))
+		case tfprotov6.DiagnosticSeverityWarning:
+			// the summary doesn't contain the parameter name for which the warning occurs to
+			details := d.Summary
+			if d.Detail != "" {
+				details = d.Detail
+			}
+			warnings = append(warnings, details)
+		}
+	}
+	return warnings, errors
+}
+
+// unmarshalErrors converts a set of diagnostics to a (possibly multi-) error. Diagnostics that are not errors are
+// dropped.
+func unmarshalErrors(diags []*tfprotov6.Diagnostic) error {
+	var err error
+	for _, d := range diags {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			err = multierror.Append(err, fromTF6Diag(d))
+		}
+	}
+	return err
+}
+
+func fromTF6Diag(diagnostic *tfprotov6.Diagnostic) error {
+	return &diagnostics.ValidationError{
+		AttributePath: pathToCty(diagnostic.Attribute),
+		Summary:       diagnostic.Summary,
+		Detail:        diagnostic.Detail,
+	}
+}
+
+func pathToCty(path *tftypes.AttributePath) cty.Path {
+	var p cty.Path
+	if path == nil {
+		return p
+	}
+	for _, s := range path.Steps() {
+		switch s := s.(type) {
+		case tftypes.AttributeName:
+			p = p.GetAttr(string(s))
+		case tftypes.ElementKeyString:
+			p = p.IndexString(string(s))
+		case tftypes.ElementKeyInt:
+			p = p.Index(cty.NumberIntVal(int64(s)))
+		}
+	}
+	return p
+}
@@ -0,0 +1,45 @@
+package tfplugin6
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/internal/tfplugin"
+)
+
+func newInstanceDiff(config, prior, planned cty.Value, meta map[string]interface{},
+	requiresReplace []*tftypes.AttributePath) *tfplugin.InstanceDiff {
+
+	paths := make([]string, len(requiresReplace))
+	for i, path := range requiresReplace {
+		paths[i] = pathString(path)
+	}
+	return tfplugin.NewInstanceDiff(config, prior, planned, meta, paths)
+}
+
+func pathString(path *tftypes.AttributePath) string {
+	var builder strings.Builder
+	for _, s := range path.Steps() {
+		switch s := s.(type) {
+		case tftypes.AttributeName:
+			if builder.Len() != 0 {
+				builder.WriteString(".")
+			}
+			builder.WriteString(string(s))
+		case tftypes.ElementKeyString:
+			if builder.Len() != 0 {
+				builder.WriteString(".")
+			}
+			builder.WriteString(string(s))
+		case tftypes.ElementKeyInt:
+			if builder.Len() != 0 {
+				builder.WriteString(".")
+			}
+			builder.WriteString(strconv.FormatInt(int64(s), 10))
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,52 @@
+package tfplugin6
+
+import (
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/internal/tfplugin"
+)
+
+var _ = shim.InstanceState((*instanceState)(nil))
+
+type instanceState struct {
+	resourceType string
+	id           string
+
+	object map[string]interface{}
+	meta   map[string]interface{}
+}
+
+func (s *instanceState) Type() string {
+	return s.resourceType
+}
+
+func (s *instanceState) ID() string {
+	return s.id
+}
+
+func (s *instanceState) Object(sch shim.SchemaMap) (map[string]interface{}, error) {
+	return s.object, nil
+}
+
+func (s *instanceState) Meta() map[string]interface{} {
+	return s.meta
+}
+
+// value returns the state as a value of the given type. A missing state is represented as null, which is what
+// protocol 6 servers expect as the prior state of a resource that is being created.
+func (s *instanceState) value(ty cty.Type) (cty.Value, error) {
+	if s == nil {
+		return cty.NullVal(ty), nil
+	}
+	return tfplugin.GoToCty(s.object, ty)
+}
+
+func (s *instanceState) marshal(ty cty.Type) (*tfprotov6.DynamicValue, error) {
+	val, err := s.value(ty)
+	if err != nil {
+		return nil, err
+	}
+	return marshalDynamicValue(val, ty)
+}
@@ -0,0 +1,264 @@
+package tfplugin6
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/internal/tfplugin"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+// unmarshalTFType converts a tftypes.Type to the equivalent cty.Type by way of their shared JSON type signature.
+func unmarshalTFType(tfType tftypes.Type) (cty.Type, error) {
+	//nolint:staticcheck // MarshalJSON is the only public way to obtain the type signature.
+	typeJSON, err := tfType.MarshalJSON()
+	if err != nil {
+		return cty.Type{}, err
+	}
+
+	var ty cty.Type
+	if err := json.Unmarshal(typeJSON, &ty); err != nil {
+		return cty.Type{}, fmt.Errorf("failed to unmarshal type: %w", err)
+	}
+	return ty, nil
+}
+
+func unmarshalAttribute(attribute *tfprotov6.SchemaAttribute) (*tfplugin.Schema, error) {
+	if attribute.NestedType != nil {
+		return unmarshalNestedAttribute(attribute)
+	}
+
+	ty, err := unmarshalTFType(attribute.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	valueType, elem, err := tfplugin.UnmarshalType(ty)
+	if err != nil {
+		return nil, err
+	}
+
+	optional := attribute.Optional
+	if !attribute.Computed && !attribute.Required {
+		optional = true
+	}
+
+	return &tfplugin.Schema{Attribute: tfplugin.Attribute{
+		CtyType:     ty,
+		ValueType:   valueType,
+		Elem:        elem,
+		Description: attribute.Description,
+		Required:    attribute.Required,
+		Optional:    optional,
+		Computed:    attribute.Computed,
+		Sensitive:   attribute.Sensitive,
+		Deprecated:  tfplugin.DeprecationMessage(attribute.Name, attribute.Deprecated),
+	}}, nil
+}
+
+// unmarshalNestedAttribute converts an attribute with a NestedType to a shim schema. Nested attributes are exposed
+// the same way as the nested blocks that are equivalent to them: the nested object becomes a shim.Resource element of
+// a list, set or single object property. Since a map property with a shim.Resource element reads as a single object,
+// the objects of a map are wrapped in an object-typed element schema instead.
+func unmarshalNestedAttribute(attribute *tfprotov6.SchemaAttribute) (*tfplugin.Schema, error) {
+	attributes, properties := map[string]cty.Type{}, schema.SchemaMap{}
+	for _, nested := range attribute.NestedType.Attributes {
+		property, err := unmarshalAttribute(nested)
+		if err != nil {
+			return nil, err
+		}
+		attributes[nested.Name], properties[nested.Name] = property.CtyType, property
+	}
+	objectType := cty.Object(attributes)
+
+	var elem interface{} = &tfplugin.Resource{CtyType: objectType, SchemaMap: properties}
+	ctyType, valueType := objectType, shim.TypeMap
+	switch attribute.NestedType.Nesting {
+	case tfprotov6.SchemaObjectNestingModeSingle:
+	case tfprotov6.SchemaObjectNestingModeList:
+		ctyType, valueType = cty.List(objectType), shim.TypeList
+	case tfprotov6.SchemaObjectNestingModeSet:
+		ctyType, valueType = cty.Set(objectType), shim.TypeSet
+	case tfprotov6.SchemaObjectNestingModeMap:
+		ctyType = cty.Map(objectType)
+		elem = &tfplugin.Schema{Attribute: tfplugin.Attribute{
+			CtyType:   objectType,
+			ValueType: shim.TypeMap,
+			Elem:      elem,
+		}}
+	default:
+		return nil, fmt.Errorf("attribute %v has unsupported nesting mode %v",
+			attribute.Name, attribute.NestedType.Nesting)
+	}
+
+	optional := attribute.Optional
+	if !attribute.Computed && !attribute.Required {
+		optional = true
+	}
+
+	return &tfplugin.Schema{Attribute: tfplugin.Attribute{
+		CtyType:     ctyType,
+		ValueType:   valueType,
+		Elem:        elem,
+		Description: attribute.Description,
+		Required:    attribute.Required,
+		Optional:    optional,
+		Computed:    attribute.Computed,
+		Sensitive:   attribute.Sensitive,
+		Deprecated:  tfplugin.DeprecationMessage(attribute.Name, attribute.Deprecated),
+	}}, nil
+}
+
+func unmarshalBlock(block *tfprotov6.SchemaBlock) (cty.Type, schema.SchemaMap, bool, error) {
+	attributes, properties, allComputed := map[string]cty.Type{}, schema.SchemaMap{}, true
+	if block == nil {
+		return cty.EmptyObject, properties, allComputed, nil
+	}
+	for _, attribute := range block.Attributes {
+		property, err := unmarshalAttribute(attribute)
+		if err != nil {
+			return cty.Type{}, nil, false, err
+		}
+		attributes[attribute.Name], properties[attribute.Name] = property.CtyType, property
+		if !property.Computed() {
+			allComputed = false
+		}
+	}
+	for _, nestedBlock := range block.BlockTypes {
+		property, err := unmarshalNestedBlock(nestedBlock)
+		if err != nil {
+			return cty.Type{}, nil, false, err
+		}
+		attributes[nestedBlock.TypeName], properties[nestedBlock.TypeName] = property.CtyType, property
+		if !property.Computed() {
+			allComputed = false
+		}
+	}
+	return cty.Object(attributes), properties, allComputed, nil
+}
+
+func unmarshalNestedBlock(nestedBlock *tfprotov6.SchemaNestedBlock) (*tfplugin.Schema, error) {
+	objectType, properties, computed, err := unmarshalBlock(nestedBlock.Block)
+	if err != nil {
+		return nil, err
+	}
+
+	ctyType, valueType := objectType, shim.TypeMap
+	switch nestedBlock.Nesting {
+	case tfprotov6.SchemaNestedBlockNestingModeList:
+		ctyType, valueType = cty.List(objectType), shim.TypeList
+	case tfprotov6.SchemaNestedBlockNestingModeSet:
+		ctyType, valueType = cty.Set(objectType), shim.TypeSet
+	case tfprotov6.SchemaNestedBlockNestingModeMap:
+		ctyType = cty.Map(objectType)
+	}
+
+	single := nestedBlock.Nesting == tfprotov6.SchemaNestedBlockNestingModeSingle ||
+		nestedBlock.Nesting == tfprotov6.SchemaNestedBlockNestingModeGroup
+
+	required, optional := false, false
+	if !computed {
+		if single {
+			required, optional = true, false
+		} else {
+			required, optional = nestedBlock.MinItems > 0, nestedBlock.MinItems == 0
+		}
+	}
+
+	var description string
+	var deprecated bool
+	if nestedBlock.Block != nil {
+		description, deprecated = nestedBlock.Block.Description, nestedBlock.Block.Deprecated
+	}
+
+	return &tfplugin.Schema{Attribute: tfplugin.Attribute{
+		CtyType:     ctyType,
+		ValueType:   valueType,
+		Elem:        &tfplugin.Resource{CtyType: objectType, SchemaMap: properties},
+		Description: description,
+		Required:    required,
+		Optional:    optional,
+		Computed:    computed,
+		Deprecated:  tfplugin.DeprecationMessage(nestedBlock.TypeName, deprecated),
+		MinItems:    int(nestedBlock.MinItems),
+		MaxItems:    int(nestedBlock.MaxItems),
+	}}, nil
+}
+
+func unmarshalResource(p *provider, typeName string, resourceSchema *tfprotov6.Schema) (*tfplugin.Resource, error) {
+	var block *tfprotov6.SchemaBlock
+	var version int64
+	if resourceSchema != nil {
+		block, version = resourceSchema.Block, resourceSchema.Version
+	}
+
+	ctyType, properties, _, err := unmarshalBlock(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure that `id` is treated as a pure output property.
+	if id, ok := properties["id"]; ok {
+		schema := id.(*tfplugin.Schema)
+		schema.Attribute.Optional = false
+		schema.Attribute.Required = false
+		schema.Attribute.Computed = true
+	}
+
+	r := &tfplugin.Resource{
+		ResourceType: typeName,
+		CtyType:      ctyType,
+		SchemaMap:    properties,
+		Version:      int(version),
+		NewState: func(id string, object, meta map[string]interface{}) shim.InstanceState {
+			return &instanceState{resourceType: typeName, id: id, object: object, meta: meta}
+		},
+	}
+	if p != nil {
+		r.Import = p.importResourceState
+	}
+	return r, nil
+}
+
+func unmarshalResourceMap(p *provider, resources map[string]*tfprotov6.Schema) (tfplugin.ResourceMap, error) {
+	resourceMap := tfplugin.ResourceMap{}
+	for name, schema := range resources {
+		r, err := unmarshalResource(p, name, schema)
+		if err != nil {
+			return nil, err
+		}
+		resourceMap[name] = r
+	}
+	return resourceMap, nil
+}
+
+// marshalDynamicValue encodes a value of the given type in the msgpack wire format.
+func marshalDynamicValue(val cty.Value, ty cty.Type) (*tfprotov6.DynamicValue, error) {
+	bytes, err := msgpack.Marshal(val, ty)
+	if err != nil {
+		return nil, err
+	}
+	return &tfprotov6.DynamicValue{MsgPack: bytes}, nil
+}
+
+// unmarshalDynamicValue decodes a value of the given type. Both the msgpack and the JSON wire formats are accepted.
+// A missing value decodes as null.
+func unmarshalDynamicValue(v *tfprotov6.DynamicValue, ty cty.Type) (cty.Value, error) {
+	switch {
+	case v == nil:
+		return cty.NullVal(ty), nil
+	case v.MsgPack != nil:
+		return msgpack.Unmarshal(v.MsgPack, ty)
+	case v.JSON != nil:
+		return ctyjson.Unmarshal(v.JSON, ty)
+	default:
+		return cty.NullVal(ty), nil
+	}
+}
@@ -0,0 +1,645 @@
+package tfplugin6
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/internal/tfplugin"
+)
+
+type provider struct {
+	server           tfprotov6.ProviderServer
+	terraformVersion string
+
+	resources   tfplugin.ResourceMap
+	dataSources tfplugin.ResourceMap
+	config      *tfplugin.Resource
+}
+
+var _ = shim.StateUpgrader((*provider)(nil))
+
+// NewProvider creates a shim.Provider that forwards all calls to a server speaking Terraform plugin protocol version
+// 6. The server may run in-process, for example a Plugin Framework provider wrapped with providerserver.NewProtocol6,
+// or be a client for a remote provider process.
+func NewProvider(ctx context.Context, server tfprotov6.ProviderServer, terraformVersion string) (shim.Provider, error) {
+	schemaResponse, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving schema: %w", err)
+	}
+	if err = unmarshalErrors(schemaResponse.Diagnostics); err != nil {
+		return nil, fmt.Errorf("error retrieving schema: %w", err)
+	}
+
+	// Default to reporting 1.0.0.
+	if terraformVersion == "" {
+		terraformVersion = "1.0.0"
+	}
+
+	p := &provider{
+		server:           server,
+		terraformVersion: terraformVersion,
+	}
+
+	p.resources, err = unmarshalResourceMap(p, schemaResponse.ResourceSchemas)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling resources: %w", err)
+	}
+
+	p.dataSources, err = unmarshalResourceMap(p, schemaResponse.DataSourceSchemas)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling data sources: %w", err)
+	}
+
+	p.config, err = unmarshalResource(p, "", schemaResponse.Provider)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling provider config: %w", err)
+	}
+
+	return p, nil
+}
+
+func (p *provider) decodeState(resource *tfplugin.Resource, s *instanceState,
+	val cty.Value, meta map[string]interface{}) (shim.InstanceState, error) {
+
+	if !val.Type().IsObjectType() || !val.IsKnown() {
+		return nil, fmt.Errorf("internal error: state is not an object or is unknown")
+	}
+
+	if val.IsNull() && s == nil {
+		return nil, nil
+	}
+
+	if s == nil {
+		s = &instanceState{resourceType: resource.ResourceType}
+	}
+
+	if val.IsNull() {
+		s.id = ""
+		s.object = nil
+		return s, nil
+	}
+
+	valueMap := val.AsValueMap()
+	if idVal := valueMap["id"]; idVal.Type() == cty.String && !idVal.IsNull() && idVal.IsKnown() {
+		s.id = idVal.AsString()
+	}
+
+	object, err := tfplugin.CtyToGo(val)
+	if err != nil {
+		return nil, err
+	}
+	s.object, s.meta = object.(map[string]interface{}), meta
+	return s, nil
+}
+
+func unmarshalMeta(private []byte) (map[string]interface{}, error) {
+	if len(private) == 0 {
+		return nil, nil
+	}
+	var meta map[string]interface{}
+	if err := json.Unmarshal(private, &meta); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+func (p *provider) upgradeResourceState(
+	ctx context.Context, resource *tfplugin.Resource, s *instanceState,
+) (*instanceState, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	schemaVersion := int64(0)
+	if schemaVersionValue, ok := s.meta["schema_version"]; ok {
+		if schemaVersionString, ok := schemaVersionValue.(string); ok {
+			sv, err := strconv.ParseInt(schemaVersionString, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("could not parse schema version: %v", err)
+			}
+			schemaVersion = sv
+		}
+	}
+
+	stateBytes, err := json.Marshal(s.object)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.server.UpgradeResourceState(ctx, &tfprotov6.UpgradeResourceStateRequest{
+		TypeName: resource.ResourceType,
+		Version:  schemaVersion,
+		RawState: &tfprotov6.RawState{JSON: stateBytes},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = unmarshalErrors(resp.Diagnostics); err != nil {
+		return nil, err
+	}
+
+	upgradedVal, err := unmarshalDynamicValue(resp.UpgradedState, resource.CtyType)
+	if err != nil {
+		return nil, err
+	}
+
+	upgradedShim, err := p.decodeState(resource, s, upgradedVal, s.meta)
+	upgradedState, _ := upgradedShim.(*instanceState)
+	return upgradedState, err
+}
+
+func (p *provider) importResourceState(t, id string, _ interface{}) ([]shim.InstanceState, error) {
+	resp, err := p.server.ImportResourceState(context.TODO(), &tfprotov6.ImportResourceStateRequest{
+		TypeName: t,
+		ID:       id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = unmarshalErrors(resp.Diagnostics); err != nil {
+		return nil, err
+	}
+
+	states := make([]shim.InstanceState, len(resp.ImportedResources))
+	for i, importedResource := range resp.ImportedResources {
+		resource, ok := p.resources[importedResource.TypeName]
+		if !ok {
+			return nil, fmt.Errorf("unknown resource type %v", importedResource.TypeName)
+		}
+
+		stateVal, err := unmarshalDynamicValue(importedResource.State, resource.CtyType)
+		if err != nil {
+			return nil, err
+		}
+
+		metaVal, err := unmarshalMeta(importedResource.Private)
+		if err != nil {
+			return nil, err
+		}
+
+		states[i], err = p.decodeState(resource, nil, stateVal, metaVal)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return states, nil
+}
+
+func (p *provider) Schema() shim.SchemaMap {
+	return p.config.SchemaMap
+}
+
+func (p *provider) ResourcesMap() shim.ResourceMap {
+	return p.resources
+}
+
+func (p *provider) DataSourcesMap() shim.ResourceMap {
+	return p.dataSources
+}
+
+func (p *provider) InternalValidate() error {
+	return nil
+}
+
+func (p *provider) Validate(ctx context.Context, c shim.ResourceConfig) ([]string, []error) {
+	config, ok := c.(tfplugin.ResourceConfig)
+	if !ok {
+		return nil, []error{fmt.Errorf("internal error: foreign resource config")}
+	}
+
+	val, err := marshalConfig(config, p.config.CtyType)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	resp, err := p.server.ValidateProviderConfig(ctx, &tfprotov6.ValidateProviderConfigRequest{
+		Config: val,
+	})
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	return unmarshalWarningsAndErrors(resp.Diagnostics)
+}
+
+func (p *provider) ValidateResource(ctx context.Context, t string, c shim.ResourceConfig) ([]string, []error) {
+	config, ok := c.(tfplugin.ResourceConfig)
+	if !ok {
+		return nil, []error{fmt.Errorf("internal error: foreign resource config")}
+	}
+
+	resource, ok := p.resources[t]
+	if !ok {
+		return nil, []error{fmt.Errorf("unknown resource type %v", t)}
+	}
+
+	val, err := marshalConfig(config, resource.CtyType)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	resp, err := p.server.ValidateResourceConfig(ctx, &tfprotov6.ValidateResourceConfigRequest{
+		TypeName: t,
+		Config:   val,
+	})
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	return unmarshalWarningsAndErrors(resp.Diagnostics)
+}
+
+func (p *provider) ValidateDataSource(ctx context.Context, t string, c shim.ResourceConfig) ([]string, []error) {
+	config, ok := c.(tfplugin.ResourceConfig)
+	if !ok {
+		return nil, []error{fmt.Errorf("internal error: foreign resource config")}
+	}
+
+	dataSource, ok := p.dataSources[t]
+	if !ok {
+		return nil, []error{fmt.Errorf("unknown data source %v", t)}
+	}
+
+	val, err := marshalConfig(config, dataSource.CtyType)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	resp, err := p.server.ValidateDataResourceConfig(ctx, &tfprotov6.ValidateDataResourceConfigRequest{
+		TypeName: t,
+		Config:   val,
+	})
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	return unmarshalWarningsAndErrors(resp.Diagnostics)
+}
+
+func (p *provider) Configure(ctx context.Context, c shim.ResourceConfig) error {
+	config, ok := c.(tfplugin.ResourceConfig)
+	if !ok {
+		return fmt.Errorf("internal error: foreign resource config")
+	}
+
+	val, err := marshalConfig(config, p.config.CtyType)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{
+		TerraformVersion: p.terraformVersion,
+		Config:           val,
+	})
+	if err != nil {
+		return err
+	}
+
+	return unmarshalErrors(resp.Diagnostics)
+}
+
+func (p *provider) Diff(
+	ctx context.Context, t string, s shim.InstanceState, c shim.ResourceConfig, opts shim.DiffOptions,
+) (shim.InstanceDiff, error) {
+
+	if opts.IgnoreChanges != nil {
+		return nil, fmt.Errorf("IgnoreChanges option is not yet supported")
+	}
+
+	state, ok := s.(*instanceState)
+	if s != nil && !ok {
+		return nil, fmt.Errorf("internal error: foreign resource state")
+	}
+	config, ok := c.(tfplugin.ResourceConfig)
+	if !ok {
+		return nil, fmt.Errorf("internal error: foreign resource config")
+	}
+
+	resource, ok := p.resources[t]
+	if !ok {
+		return nil, fmt.Errorf("unknown resource type %v", t)
+	}
+
+	state, err := p.upgradeResourceState(ctx, resource, state)
+	if err != nil {
+		return nil, err
+	}
+
+	stateVal, err := state.value(resource.CtyType)
+	if err != nil {
+		return nil, err
+	}
+	configVal, err := tfplugin.GoToCty(config, resource.CtyType)
+	if err != nil {
+		return nil, err
+	}
+
+	priorState, err := marshalDynamicValue(stateVal, resource.CtyType)
+	if err != nil {
+		return nil, err
+	}
+	var metaBytes []byte
+	if state != nil {
+		m, err := json.Marshal(state.meta)
+		if err != nil {
+			return nil, err
+		}
+		metaBytes = m
+	}
+	proposedNewState, err := marshalDynamicValue(configVal, resource.CtyType)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.server.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         resource.ResourceType,
+		PriorState:       priorState,
+		ProposedNewState: proposedNewState,
+		Config:           proposedNewState,
+		PriorPrivate:     metaBytes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = unmarshalErrors(resp.Diagnostics); err != nil {
+		return nil, err
+	}
+
+	plannedVal, err := unmarshalDynamicValue(resp.PlannedState, resource.CtyType)
+	if err != nil {
+		return nil, err
+	}
+
+	plannedMeta, err := unmarshalMeta(resp.PlannedPrivate)
+	if err != nil {
+		return nil, err
+	}
+
+	return newInstanceDiff(configVal, stateVal, plannedVal, plannedMeta, resp.RequiresReplace), nil
+}
+
+func (p *provider) Apply(
+	ctx context.Context, t string, s shim.InstanceState, d shim.InstanceDiff,
+) (shim.InstanceState, error) {
+	state, ok := s.(*instanceState)
+	if s != nil && !ok {
+		return nil, fmt.Errorf("internal error: foreign resource state")
+	}
+	diff, ok := d.(*tfplugin.InstanceDiff)
+	if !ok {
+		return nil, fmt.Errorf("internal error: foreign instance diff")
+	}
+
+	resource, ok := p.resources[t]
+	if !ok {
+		return nil, fmt.Errorf("unknown resource type %v", t)
+	}
+
+	state, err := p.upgradeResourceState(ctx, resource, state)
+	if err != nil {
+		return nil, err
+	}
+
+	priorState, err := state.marshal(resource.CtyType)
+	if err != nil {
+		return nil, err
+	}
+	if diff.Planned == (cty.Value{}) {
+		diff.Planned = cty.NullVal(resource.CtyType)
+	}
+	plannedState, err := marshalDynamicValue(diff.Planned, resource.CtyType)
+	if err != nil {
+		return nil, err
+	}
+	plannedMetaBytes, err := json.Marshal(diff.Meta)
+	if err != nil {
+		return nil, err
+	}
+
+	if diff.Config == (cty.Value{}) {
+		diff.Config = cty.NullVal(resource.CtyType)
+	}
+	config, err := marshalDynamicValue(diff.Config, resource.CtyType)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.server.ApplyResourceChange(ctx, &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:       resource.ResourceType,
+		PriorState:     priorState,
+		PlannedState:   plannedState,
+		Config:         config,
+		PlannedPrivate: plannedMetaBytes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	newStateVal, err := unmarshalDynamicValue(resp.NewState, resource.CtyType)
+	if err != nil {
+		return nil, err
+	}
+
+	newMetaVal, err := unmarshalMeta(resp.Private)
+	if err != nil {
+		return nil, err
+	}
+
+	newState, err := p.decodeState(resource, state, newStateVal, newMetaVal)
+	if err != nil {
+		return nil, err
+	}
+
+	return newState, unmarshalErrors(resp.Diagnostics)
+}
+
+func (p *provider) Refresh(
+	ctx context.Context, t string, s shim.InstanceState, _ shim.ResourceConfig,
+) (shim.InstanceState, error) {
+	state, ok := s.(*instanceState)
+	if s != nil && !ok {
+		return nil, fmt.Errorf("internal error: foreign resource state")
+	}
+
+	resource, ok := p.resources[t]
+	if !ok {
+		return nil, fmt.Errorf("unknown resource type %v", t)
+	}
+
+	state, err := p.upgradeResourceState(ctx, resource, state)
+	if err != nil {
+		return nil, err
+	}
+
+	currentState, err := state.marshal(resource.CtyType)
+	if err != nil {
+		return nil, err
+	}
+	var metaBytes []byte
+	if state != nil {
+		m, err := json.Marshal(state.meta)
+		if err != nil {
+			return nil, err
+		}
+		metaBytes = m
+	}
+
+	resp, err := p.server.ReadResource(ctx, &tfprotov6.ReadResourceRequest{
+		TypeName:     resource.ResourceType,
+		CurrentState: currentState,
+		Private:      metaBytes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.NewState == nil {
+		return nil, unmarshalErrors(resp.Diagnostics)
+	}
+
+	newStateVal, err := unmarshalDynamicValue(resp.NewState, resource.CtyType)
+	if err != nil {
+		return nil, err
+	}
+
+	newMetaVal, err := unmarshalMeta(resp.Private)
+	if err != nil {
+		return nil, err
+	}
+
+	newState, err := p.decodeState(resource, state, newStateVal, newMetaVal)
+	if err != nil {
+		return nil, err
+	}
+
+	return newState, unmarshalErrors(resp.Diagnostics)
+}
+
+// UpgradeState implements shim.StateUpgrader by sending rawState to the provider's UpgradeResourceState.
+func (p *provider) UpgradeState(
+	ctx context.Context, t string, version int, rawState map[string]interface{},
+) (shim.InstanceState, error) {
+	resource, ok := p.resources[t]
+	if !ok {
+		return nil, fmt.Errorf("unknown resource type %v", t)
+	}
+
+	state, err := p.upgradeResourceState(ctx, resource, &instanceState{
+		resourceType: t,
+		object:       rawState,
+		meta:         map[string]interface{}{"schema_version": strconv.Itoa(version)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	state.meta = map[string]interface{}{"schema_version": strconv.Itoa(resource.Version)}
+	return state, nil
+}
+
+func (p *provider) ReadDataDiff(ctx context.Context, t string, c shim.ResourceConfig) (shim.InstanceDiff, error) {
+	dataSource, ok := p.dataSources[t]
+	if !ok {
+		return nil, fmt.Errorf("unknown data source %v", t)
+	}
+
+	planned, err := tfplugin.GoToCty(c, dataSource.CtyType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tfplugin.InstanceDiff{Planned: planned}, nil
+}
+
+func (p *provider) ReadDataApply(ctx context.Context, t string, d shim.InstanceDiff) (shim.InstanceState, error) {
+	diff, ok := d.(*tfplugin.InstanceDiff)
+	if !ok {
+		return nil, fmt.Errorf("internal error: foreign instance diff")
+	}
+
+	dataSource, ok := p.dataSources[t]
+	if !ok {
+		return nil, fmt.Errorf("unknown data source %v", t)
+	}
+
+	config, err := marshalDynamicValue(diff.Planned, dataSource.CtyType)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.server.ReadDataSource(ctx, &tfprotov6.ReadDataSourceRequest{
+		TypeName: t,
+		Config:   config,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = unmarshalErrors(resp.Diagnostics); err != nil {
+		return nil, err
+	}
+
+	stateVal, err := unmarshalDynamicValue(resp.State, dataSource.CtyType)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.decodeState(dataSource, nil, stateVal, nil)
+}
+
+func (p *provider) Meta(ctx context.Context) interface{} {
+	return nil
+}
+
+func (p *provider) Stop(ctx context.Context) error {
+	resp, err := p.server.StopProvider(ctx, &tfprotov6.StopProviderRequest{})
+	switch {
+	case err != nil:
+		return err
+	case resp.Error != "":
+		return fmt.Errorf("%s", resp.Error)
+	default:
+		return nil
+	}
+}
+
+func (p *provider) InitLogging(ctx context.Context) {
+	// Nothing to do.
+}
+
+func (p *provider) NewDestroyDiff(ctx context.Context, t string, opts shim.TimeoutOptions) shim.InstanceDiff {
+	d := &tfplugin.InstanceDiff{IsDestroy: true}
+	d.ApplyTimeoutOptions(opts)
+	return d
+}
+
+func (p *provider) NewResourceConfig(ctx context.Context, object map[string]interface{}) shim.ResourceConfig {
+	return tfplugin.ResourceConfig(object)
+}
+
+func (p *provider) IsSet(ctx context.Context, v interface{}) ([]interface{}, bool) {
+	val, ok := v.(cty.Value)
+	if !ok {
+		return nil, false
+	}
+	if !val.Type().IsSetType() {
+		return nil, false
+	}
+
+	result := make([]interface{}, 0, val.LengthInt())
+	iter := val.ElementIterator()
+	for iter.Next() {
+		v, _ := iter.Element()
+		gv, err := tfplugin.CtyToGo(v)
+		if err != nil {
+			// NOTE: this might be worthy of a panic.
+			return nil, false
+		}
+		result = append(result, gv)
+	}
+	return result, true
+}
@@ -0,0 +1,539 @@
+package tfplugin6
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/diagnostics"
+)
+
+var testResourceSchema = &tfprotov6.Schema{
+	Version: 1,
+	Block: &tfprotov6.SchemaBlock{
+		Attributes: []*tfprotov6.SchemaAttribute{
+			{Name: "id", Type: tftypes.String, Computed: true},
+			{Name: "name", Type: tftypes.String, Required: true},
+			{Name: "secret", Type: tftypes.String, Optional: true, Sensitive: true},
+			{
+				Name: "endpoint",
+				Type: tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+					"host": tftypes.String,
+					"port": tftypes.Number,
+				}},
+				Computed: true,
+			},
+			{
+				Name:     "rules",
+				Optional: true,
+				NestedType: &tfprotov6.SchemaObject{
+					Nesting: tfprotov6.SchemaObjectNestingModeList,
+					Attributes: []*tfprotov6.SchemaAttribute{
+						{Name: "port", Type: tftypes.Number, Required: true},
+						{Name: "tags", Type: tftypes.Set{ElementType: tftypes.String}, Optional: true},
+					},
+				},
+			},
+			{
+				Name:     "settings",
+				Optional: true,
+				NestedType: &tfprotov6.SchemaObject{
+					Nesting: tfprotov6.SchemaObjectNestingModeSingle,
+					Attributes: []*tfprotov6.SchemaAttribute{
+						{Name: "enabled", Type: tftypes.Bool, Optional: true},
+					},
+				},
+			},
+			{
+				Name:     "targets",
+				Optional: true,
+				NestedType: &tfprotov6.SchemaObject{
+					Nesting: tfprotov6.SchemaObjectNestingModeMap,
+					Attributes: []*tfprotov6.SchemaAttribute{
+						{Name: "weight", Type: tftypes.Number, Optional: true},
+					},
+				},
+			},
+		},
+		BlockTypes: []*tfprotov6.SchemaNestedBlock{{
+			TypeName: "block",
+			Nesting:  tfprotov6.SchemaNestedBlockNestingModeSet,
+			MaxItems: 2,
+			Block: &tfprotov6.SchemaBlock{
+				Attributes: []*tfprotov6.SchemaAttribute{
+					{Name: "value", Type: tftypes.String, Required: true},
+				},
+			},
+		}},
+	},
+}
+
+var testDataSourceSchema = &tfprotov6.Schema{
+	Block: &tfprotov6.SchemaBlock{
+		Attributes: []*tfprotov6.SchemaAttribute{
+			{Name: "filter", Type: tftypes.String, Required: true},
+			{Name: "results", Type: tftypes.List{ElementType: tftypes.String}, Computed: true},
+		},
+	},
+}
+
+// testServer is a minimal in-memory tfprotov6.ProviderServer with a single resource and data source.
+type testServer struct {
+	ctyType   cty.Type
+	stopped   bool
+	configure *tfprotov6.ConfigureProviderRequest
+	upgrades  []*tfprotov6.UpgradeResourceStateRequest
+}
+
+var _ tfprotov6.ProviderServer = (*testServer)(nil)
+
+func newTestServer(t *testing.T) *testServer {
+	ty, err := unmarshalTFType(testResourceSchema.ValueType())
+	require.NoError(t, err)
+	return &testServer{ctyType: ty}
+}
+
+func (s *testServer) GetMetadata(
+	context.Context, *tfprotov6.GetMetadataRequest,
+) (*tfprotov6.GetMetadataResponse, error) {
+	return &tfprotov6.GetMetadataResponse{}, nil
+}
+
+func (s *testServer) GetProviderSchema(
+	context.Context, *tfprotov6.GetProviderSchemaRequest,
+) (*tfprotov6.GetProviderSchemaResponse, error) {
+	return &tfprotov6.GetProviderSchemaResponse{
+		Provider: &tfprotov6.Schema{Block: &tfprotov6.SchemaBlock{
+			Attributes: []*tfprotov6.SchemaAttribute{
+				{Name: "region", Type: tftypes.String, Optional: true},
+			},
+		}},
+		ResourceSchemas:   map[string]*tfprotov6.Schema{"test_resource": testResourceSchema},
+		DataSourceSchemas: map[string]*tfprotov6.Schema{"test_data": testDataSourceSchema},
+	}, nil
+}
+
+func (s *testServer) ValidateProviderConfig(
+	_ context.Context, req *tfprotov6.ValidateProviderConfigRequest,
+) (*tfprotov6.ValidateProviderConfigResponse, error) {
+	return &tfprotov6.ValidateProviderConfigResponse{PreparedConfig: req.Config}, nil
+}
+
+func (s *testServer) ConfigureProvider(
+	_ context.Context, req *tfprotov6.ConfigureProviderRequest,
+) (*tfprotov6.ConfigureProviderResponse, error) {
+	s.configure = req
+	return &tfprotov6.ConfigureProviderResponse{}, nil
+}
+
+func (s *testServer) StopProvider(
+	context.Context, *tfprotov6.StopProviderRequest,
+) (*tfprotov6.StopProviderResponse, error) {
+	s.stopped = true
+	return &tfprotov6.StopProviderResponse{}, nil
+}
+
+func (s *testServer) ValidateResourceConfig(
+	_ context.Context, req *tfprotov6.ValidateResourceConfigRequest,
+) (*tfprotov6.ValidateResourceConfigResponse, error) {
+	config, err := msgpack.Unmarshal(req.Config.MsgPack, s.ctyType)
+	if err != nil {
+		return nil, err
+	}
+	if name := config.GetAttr("name"); name.IsKnown() && !name.IsNull() && name.AsString() == "" {
+		return &tfprotov6.ValidateResourceConfigResponse{
+			Diagnostics: []*tfprotov6.Diagnostic{{
+				Severity:  tfprotov6.DiagnosticSeverityError,
+				Summary:   "name must not be empty",
+				Attribute: tftypes.NewAttributePath().WithAttributeName("name"),
+			}},
+		}, nil
+	}
+	return &tfprotov6.ValidateResourceConfigResponse{}, nil
+}
+
+func (s *testServer) UpgradeResourceState(
+	_ context.Context, req *tfprotov6.UpgradeResourceStateRequest,
+) (*tfprotov6.UpgradeResourceStateResponse, error) {
+	s.upgrades = append(s.upgrades, req)
+	val, err := ctyjson.Unmarshal(req.RawState.JSON, s.ctyType)
+	if err != nil {
+		return nil, err
+	}
+	upgraded, err := msgpack.Marshal(val, s.ctyType)
+	if err != nil {
+		return nil, err
+	}
+	return &tfprotov6.UpgradeResourceStateResponse{
+		UpgradedState: &tfprotov6.DynamicValue{MsgPack: upgraded},
+	}, nil
+}
+
+func (s *testServer) ReadResource(
+	_ context.Context, req *tfprotov6.ReadResourceRequest,
+) (*tfprotov6.ReadResourceResponse, error) {
+	return &tfprotov6.ReadResourceResponse{NewState: req.CurrentState, Private: req.Private}, nil
+}
+
+func (s *testServer) PlanResourceChange(
+	_ context.Context, req *tfprotov6.PlanResourceChangeRequest,
+) (*tfprotov6.PlanResourceChangeResponse, error) {
+	prior, err := msgpack.Unmarshal(req.PriorState.MsgPack, s.ctyType)
+	if err != nil {
+		return nil, err
+	}
+	proposed, err := msgpack.Unmarshal(req.ProposedNewState.MsgPack, s.ctyType)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &tfprotov6.PlanResourceChangeResponse{}
+	planned := proposed.AsValueMap()
+	if prior.IsNull() {
+		planned["id"] = cty.UnknownVal(cty.String)
+		planned["endpoint"] = cty.UnknownVal(s.ctyType.AttributeType("endpoint"))
+	} else {
+		planned["id"] = prior.GetAttr("id")
+		planned["endpoint"] = prior.GetAttr("endpoint")
+		if !prior.GetAttr("name").RawEquals(proposed.GetAttr("name")) {
+			resp.RequiresReplace = []*tftypes.AttributePath{tftypes.NewAttributePath().WithAttributeName("name")}
+		}
+	}
+
+	resp.PlannedState, err = marshalDynamicValue(cty.ObjectVal(planned), s.ctyType)
+	if err != nil {
+		return nil, err
+	}
+	resp.PlannedPrivate = []byte(`{"planned":true}`)
+	return resp, nil
+}
+
+func (s *testServer) ApplyResourceChange(
+	_ context.Context, req *tfprotov6.ApplyResourceChangeRequest,
+) (*tfprotov6.ApplyResourceChangeResponse, error) {
+	planned, err := msgpack.Unmarshal(req.PlannedState.MsgPack, s.ctyType)
+	if err != nil {
+		return nil, err
+	}
+	if planned.IsNull() {
+		return &tfprotov6.ApplyResourceChangeResponse{NewState: req.PlannedState}, nil
+	}
+
+	state := planned.AsValueMap()
+	if !state["id"].IsKnown() {
+		state["id"] = cty.StringVal("new-id")
+	}
+	if !state["endpoint"].IsKnown() {
+		state["endpoint"] = cty.ObjectVal(map[string]cty.Value{
+			"host": cty.StringVal("localhost"),
+			"port": cty.NumberIntVal(8080),
+		})
+	}
+
+	newState, err := marshalDynamicValue(cty.ObjectVal(state), s.ctyType)
+	if err != nil {
+		return nil, err
+	}
+	return &tfprotov6.ApplyResourceChangeResponse{NewState: newState, Private: req.PlannedPrivate}, nil
+}
+
+func (s *testServer) ImportResourceState(
+	_ context.Context, req *tfprotov6.ImportResourceStateRequest,
+) (*tfprotov6.ImportResourceStateResponse, error) {
+	state := map[string]cty.Value{}
+	for name, ty := range s.ctyType.AttributeTypes() {
+		state[name] = cty.NullVal(ty)
+	}
+	state["id"], state["name"] = cty.StringVal(req.ID), cty.StringVal("imported")
+
+	imported, err := marshalDynamicValue(cty.ObjectVal(state), s.ctyType)
+	if err != nil {
+		return nil, err
+	}
+	return &tfprotov6.ImportResourceStateResponse{
+		ImportedResources: []*tfprotov6.ImportedResource{{
+			TypeName: req.TypeName,
+			State:    imported,
+		}},
+	}, nil
+}
+
+func (s *testServer) MoveResourceState(
+	context.Context, *tfprotov6.MoveResourceStateRequest,
+) (*tfprotov6.MoveResourceStateResponse, error) {
+	return &tfprotov6.MoveResourceStateResponse{}, nil
+}
+
+func (s *testServer) ValidateDataResourceConfig(
+	context.Context, *tfprotov6.ValidateDataResourceConfigRequest,
+) (*tfprotov6.ValidateDataResourceConfigResponse, error) {
+	return &tfprotov6.ValidateDataResourceConfigResponse{}, nil
+}
+
+func (s *testServer) ReadDataSource(
+	_ context.Context, req *tfprotov6.ReadDataSourceRequest,
+) (*tfprotov6.ReadDataSourceResponse, error) {
+	ty, err := unmarshalTFType(testDataSourceSchema.ValueType())
+	if err != nil {
+		return nil, err
+	}
+	config, err := msgpack.Unmarshal(req.Config.MsgPack, ty)
+	if err != nil {
+		return nil, err
+	}
+	filter := config.GetAttr("filter")
+	state, err := marshalDynamicValue(cty.ObjectVal(map[string]cty.Value{
+		"filter":  filter,
+		"results": cty.ListVal([]cty.Value{filter, filter}),
+	}), ty)
+	if err != nil {
+		return nil, err
+	}
+	return &tfprotov6.ReadDataSourceResponse{State: state}, nil
+}
+
+func startTestProvider(t *testing.T) (*testServer, shim.Provider) {
+	server := newTestServer(t)
+	p, err := NewProvider(context.Background(), server, "")
+	require.NoError(t, err)
+	return server, p
+}
+
+func TestProviderSchema(t *testing.T) {
+	_, p := startTestProvider(t)
+
+	region, ok := p.Schema().GetOk("region")
+	require.True(t, ok)
+	assert.Equal(t, shim.TypeString, region.Type())
+	assert.True(t, region.Optional())
+
+	r, ok := p.ResourcesMap().GetOk("test_resource")
+	require.True(t, ok)
+	assert.Equal(t, 1, r.SchemaVersion())
+
+	id := r.Schema().Get("id")
+	assert.True(t, id.Computed())
+	assert.False(t, id.Optional())
+
+	assert.True(t, r.Schema().Get("name").Required())
+	assert.True(t, r.Schema().Get("secret").Sensitive())
+
+	// Object-typed attributes are represented as maps with a resource element.
+	endpoint := r.Schema().Get("endpoint")
+	assert.Equal(t, shim.TypeMap, endpoint.Type())
+	endpointElem, ok := endpoint.Elem().(shim.Resource)
+	require.True(t, ok)
+	assert.Equal(t, shim.TypeFloat, endpointElem.Schema().Get("port").Type())
+
+	// Nested attributes are represented like the equivalent nested blocks.
+	rules := r.Schema().Get("rules")
+	assert.Equal(t, shim.TypeList, rules.Type())
+	assert.True(t, rules.Optional())
+	rulesElem, ok := rules.Elem().(shim.Resource)
+	require.True(t, ok)
+	assert.True(t, rulesElem.Schema().Get("port").Required())
+	assert.Equal(t, shim.TypeSet, rulesElem.Schema().Get("tags").Type())
+
+	settings := r.Schema().Get("settings")
+	assert.Equal(t, shim.TypeMap, settings.Type())
+	settingsElem, ok := settings.Elem().(shim.Resource)
+	require.True(t, ok)
+	assert.Equal(t, shim.TypeBool, settingsElem.Schema().Get("enabled").Type())
+
+	// Map nested attributes are maps of objects rather than single objects.
+	targets := r.Schema().Get("targets")
+	assert.Equal(t, shim.TypeMap, targets.Type())
+	targetsElem, ok := targets.Elem().(shim.Schema)
+	require.True(t, ok)
+	assert.Equal(t, shim.TypeMap, targetsElem.Type())
+	targetElem, ok := targetsElem.Elem().(shim.Resource)
+	require.True(t, ok)
+	assert.Equal(t, shim.TypeFloat, targetElem.Schema().Get("weight").Type())
+
+	block := r.Schema().Get("block")
+	assert.Equal(t, shim.TypeSet, block.Type())
+	assert.Equal(t, 2, block.MaxItems())
+	assert.True(t, block.Optional())
+
+	d, ok := p.DataSourcesMap().GetOk("test_data")
+	require.True(t, ok)
+	assert.Equal(t, shim.TypeList, d.Schema().Get("results").Type())
+}
+
+func TestConfigure(t *testing.T) {
+	ctx := context.Background()
+	server, p := startTestProvider(t)
+
+	config := p.NewResourceConfig(ctx, map[string]interface{}{"region": "us-west-2"})
+	warnings, errors := p.Validate(ctx, config)
+	assert.Empty(t, warnings)
+	assert.Empty(t, errors)
+
+	require.NoError(t, p.Configure(ctx, config))
+	require.NotNil(t, server.configure)
+	assert.Equal(t, "1.0.0", server.configure.TerraformVersion)
+
+	val, err := msgpack.Unmarshal(server.configure.Config.MsgPack, cty.Object(map[string]cty.Type{
+		"region": cty.String,
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "us-west-2", val.GetAttr("region").AsString())
+}
+
+func TestValidateResource(t *testing.T) {
+	ctx := context.Background()
+	_, p := startTestProvider(t)
+
+	_, errors := p.ValidateResource(ctx, "test_resource",
+		p.NewResourceConfig(ctx, map[string]interface{}{"name": "ok"}))
+	assert.Empty(t, errors)
+
+	_, errors = p.ValidateResource(ctx, "test_resource",
+		p.NewResourceConfig(ctx, map[string]interface{}{"name": ""}))
+	require.Len(t, errors, 1)
+	var validationError *diagnostics.ValidationError
+	require.ErrorAs(t, errors[0], &validationError)
+	assert.Equal(t, "name must not be empty", validationError.Summary)
+	assert.Equal(t, cty.GetAttrPath("name"), validationError.AttributePath)
+}
+
+func TestCreate(t *testing.T) {
+	ctx := context.Background()
+	_, p := startTestProvider(t)
+
+	config := p.NewResourceConfig(ctx, map[string]interface{}{
+		"name": "example",
+		"rules": []interface{}{
+			map[string]interface{}{"port": 80, "tags": []interface{}{"http"}},
+		},
+		"settings": map[string]interface{}{"enabled": true},
+	})
+
+	diff, err := p.Diff(ctx, "test_resource", nil, config, shim.DiffOptions{})
+	require.NoError(t, err)
+	assert.False(t, diff.RequiresNew())
+	assert.Equal(t, shim.ResourceAttrDiff{New: "example"}, *diff.Attribute("name"))
+	assert.Equal(t, shim.ResourceAttrDiff{New: "80"}, *diff.Attribute("rules.0.port"))
+	assert.Equal(t, shim.ResourceAttrDiff{New: UnknownVariableValue}, *diff.Attribute("id"))
+
+	state, err := p.Apply(ctx, "test_resource", nil, diff)
+	require.NoError(t, err)
+	assert.Equal(t, "new-id", state.ID())
+	assert.Equal(t, map[string]interface{}{"planned": true}, state.Meta())
+
+	object, err := state.Object(p.ResourcesMap().Get("test_resource").Schema())
+	require.NoError(t, err)
+	assert.Equal(t, "example", object["name"])
+	assert.Equal(t, map[string]interface{}{"host": "localhost", "port": float64(8080)}, object["endpoint"])
+	assert.Equal(t, map[string]interface{}{"enabled": true}, object["settings"])
+
+	rules, ok := object["rules"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, rules, 1)
+	tags, ok := p.IsSet(ctx, rules[0].(map[string]interface{})["tags"])
+	require.True(t, ok)
+	assert.Equal(t, []interface{}{"http"}, tags)
+}
+
+func TestUpdateRequiresReplace(t *testing.T) {
+	ctx := context.Background()
+	server, p := startTestProvider(t)
+	r := p.ResourcesMap().Get("test_resource")
+
+	prior, err := r.InstanceState("some-id", map[string]interface{}{
+		"name": "before",
+		"endpoint": map[string]interface{}{
+			"host": "localhost",
+			"port": 8080,
+		},
+	}, map[string]interface{}{"schema_version": "1"})
+	require.NoError(t, err)
+
+	config := p.NewResourceConfig(ctx, map[string]interface{}{"name": "after"})
+	diff, err := p.Diff(ctx, "test_resource", prior, config, shim.DiffOptions{})
+	require.NoError(t, err)
+	assert.True(t, diff.RequiresNew())
+	assert.Equal(t, shim.ResourceAttrDiff{Old: "before", New: "after", RequiresNew: true},
+		*diff.Attribute("name"))
+
+	require.Len(t, server.upgrades, 1)
+	assert.Equal(t, int64(1), server.upgrades[0].Version)
+}
+
+func TestRefresh(t *testing.T) {
+	ctx := context.Background()
+	_, p := startTestProvider(t)
+	r := p.ResourcesMap().Get("test_resource")
+
+	prior, err := r.InstanceState("some-id", map[string]interface{}{"name": "example"}, nil)
+	require.NoError(t, err)
+
+	state, err := p.Refresh(ctx, "test_resource", prior, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "some-id", state.ID())
+
+	object, err := state.Object(r.Schema())
+	require.NoError(t, err)
+	assert.Equal(t, "example", object["name"])
+}
+
+func TestUpgradeState(t *testing.T) {
+	ctx := context.Background()
+	server, p := startTestProvider(t)
+
+	upgrader, ok := p.(shim.StateUpgrader)
+	require.True(t, ok)
+	state, err := upgrader.UpgradeState(ctx, "test_resource", 0, map[string]interface{}{
+		"id":   "some-id",
+		"name": "example",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "some-id", state.ID())
+	assert.Equal(t, map[string]interface{}{"schema_version": "1"}, state.Meta())
+
+	require.Len(t, server.upgrades, 1)
+	assert.Equal(t, int64(0), server.upgrades[0].Version)
+}
+
+func TestImport(t *testing.T) {
+	_, p := startTestProvider(t)
+
+	importer := p.ResourcesMap().Get("test_resource").Importer()
+	require.NotNil(t, importer)
+
+	states, err := importer("test_resource", "imported-id", nil)
+	require.NoError(t, err)
+	require.Len(t, states, 1)
+	assert.Equal(t, "imported-id", states[0].ID())
+	assert.Equal(t, "test_resource", states[0].Type())
+}
+
+func TestReadDataSource(t *testing.T) {
+	ctx := context.Background()
+	_, p := startTestProvider(t)
+
+	diff, err := p.ReadDataDiff(ctx, "test_data",
+		p.NewResourceConfig(ctx, map[string]interface{}{"filter": "abc"}))
+	require.NoError(t, err)
+
+	state, err := p.ReadDataApply(ctx, "test_data", diff)
+	require.NoError(t, err)
+
+	object, err := state.Object(p.DataSourcesMap().Get("test_data").Schema())
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"abc", "abc"}, object["results"])
+}
+
+func TestStop(t *testing.T) {
+	server, p := startTestProvider(t)
+	require.NoError(t, p.Stop(context.Background()))
+	assert.True(t, server.stopped)
+}
@@ -0,0 +1,16 @@
+package tfplugin6
+
+import (
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/internal/tfplugin"
+)
+
+func marshalConfig(c tfplugin.ResourceConfig, ty cty.Type) (*tfprotov6.DynamicValue, error) {
+	val, err := tfplugin.GoToCty(c, ty)
+	if err != nil {
+		return nil, err
+	}
+	return marshalDynamicValue(val, ty)
+}
@@ -0,0 +1,11 @@
+package tfplugin6
+
+import (
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/internal/tfplugin"
+)
+
+// UnknownVariableValue is the sentinal defined in github.com/hashicorp/terraform/configs/hcl2shim,
+// representing a variable whose value is not known at some particular time. The value is duplicated here in
+// order to prevent an additional dependency - it is unlikely to ever change upstream since that would break
+// rather a lot of things.
+const UnknownVariableValue = tfplugin.UnknownVariableValue
@@ -0,0 +1,62 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cancel ties the contexts of individual provider operations to a provider-wide cancellation signal, so
+// that a Cancel request from the engine aborts in-flight Terraform operations.
+package cancel
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrCanceled is the cause of contexts canceled by [Scope.Cancel].
+var ErrCanceled = errors.New("the provider was canceled")
+
+// Scope is a provider-wide cancellation scope.
+//
+// The zero value is ready to use. A Scope must not be copied after first use.
+type Scope struct {
+	once   sync.Once
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+}
+
+func (s *Scope) init() {
+	s.once.Do(func() {
+		s.ctx, s.cancel = context.WithCancelCause(context.Background())
+	})
+}
+
+// Context returns a context derived from ctx that is also canceled when [Scope.Cancel] is called, including when
+// Cancel was called before. The returned context.CancelFunc releases the resources associated with the context and
+// must be called once the operation completes.
+func (s *Scope) Context(ctx context.Context) (context.Context, context.CancelFunc) {
+	s.init()
+	ctx, cancel := context.WithCancelCause(ctx)
+	stop := context.AfterFunc(s.ctx, func() {
+		cancel(context.Cause(s.ctx))
+	})
+	return ctx, func() {
+		stop()
+		cancel(context.Canceled)
+	}
+}
+
+// Cancel cancels every context returned by [Scope.Context].
+func (s *Scope) Cancel() {
+	s.init()
+	s.cancel(ErrCanceled)
+}
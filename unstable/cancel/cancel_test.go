@@ -0,0 +1,80 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cancel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScope(t *testing.T) {
+	t.Parallel()
+
+	t.Run("cancel in-flight", func(t *testing.T) {
+		t.Parallel()
+		var s Scope
+		ctx, done := s.Context(context.Background())
+		defer done()
+
+		go s.Cancel()
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(10 * time.Second):
+			require.Fail(t, "context was not canceled")
+		}
+		assert.ErrorIs(t, context.Cause(ctx), ErrCanceled)
+	})
+
+	t.Run("cancel before", func(t *testing.T) {
+		t.Parallel()
+		var s Scope
+		s.Cancel()
+		ctx, done := s.Context(context.Background())
+		defer done()
+
+		<-ctx.Done()
+		assert.ErrorIs(t, context.Cause(ctx), ErrCanceled)
+	})
+
+	t.Run("parent canceled", func(t *testing.T) {
+		t.Parallel()
+		var s Scope
+		parent, cancel := context.WithCancel(context.Background())
+		ctx, done := s.Context(parent)
+		defer done()
+
+		cancel()
+		<-ctx.Done()
+		assert.ErrorIs(t, context.Cause(ctx), context.Canceled)
+	})
+
+	t.Run("done releases", func(t *testing.T) {
+		t.Parallel()
+		var s Scope
+		ctx, done := s.Context(context.Background())
+		done()
+		assert.Error(t, ctx.Err())
+
+		// Canceling the scope afterwards does not change the cause.
+		s.Cancel()
+		assert.ErrorIs(t, context.Cause(ctx), context.Canceled)
+		assert.NotErrorIs(t, context.Cause(ctx), ErrCanceled)
+	})
+}
@@ -0,0 +1,310 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package recording records the gRPC calls served by a ResourceProviderServer in the JSON log format consumed by
+// ReplaySequence and ReplayFile from github.com/pulumi/providertest/replay.
+//
+// Providers built with tfbridge.Main record their calls when the PULUMI_TFBRIDGE_GRPC_RECORD environment variable
+// points to a file:
+//
+//	PULUMI_TFBRIDGE_GRPC_RECORD=$PWD/log.json pulumi up
+//
+// StreamInvoke calls are not recorded, since the log format has no room for streamed responses.
+package recording
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"regexp"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	jsonpb "google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// EnvVar names the environment variable holding the path of the file to record gRPC calls to.
+const EnvVar = "PULUMI_TFBRIDGE_GRPC_RECORD"
+
+// Placeholder replaces redacted and normalized values. Replay matches it against any value.
+const Placeholder = "*"
+
+// The signature Pulumi uses to mark secret property values in the gRPC protocol.
+const (
+	sigKey    = "4dabf18193072939515e22adb298388d"
+	secretSig = "1b47061264138c4ac30d75fd1eb44270"
+)
+
+// Options configure how calls are recorded.
+type Options struct {
+	// Records secret values as is instead of replacing them with Placeholder. Requests holding redacted values
+	// cannot be replayed, since the provider would receive Placeholder in their place.
+	KeepSecrets bool
+
+	// Names of properties whose values are always replaced with Placeholder, such as "password".
+	RedactProperties []string
+
+	// String values of responses matching any of these patterns are replaced with Placeholder. This makes the
+	// recorded responses match when replayed even though values such as timestamps and random IDs change. Requests
+	// are not normalized, since they are sent to the provider as recorded when replayed.
+	Normalize []*regexp.Regexp
+}
+
+// DefaultNormalize matches RFC 3339 timestamps and UUIDs.
+var DefaultNormalize = []*regexp.Regexp{
+	regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})$`),
+	regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`),
+}
+
+// FromEnv wraps server with a recording server if EnvVar is set, and returns server otherwise. Secrets are redacted
+// and the values of responses matching DefaultNormalize are normalized.
+func FromEnv(server pulumirpc.ResourceProviderServer) pulumirpc.ResourceProviderServer {
+	path, ok := os.LookupEnv(EnvVar)
+	if !ok || path == "" {
+		return server
+	}
+	return New(server, path, Options{Normalize: DefaultNormalize})
+}
+
+// New wraps server so that every call it serves is appended to the JSON log at path. The log is a valid JSON array
+// after every call, so that it remains usable if the provider is killed.
+func New(server pulumirpc.ResourceProviderServer, path string, opts Options) pulumirpc.ResourceProviderServer {
+	redact := make(map[string]bool, len(opts.RedactProperties))
+	for _, p := range opts.RedactProperties {
+		redact[p] = true
+	}
+	return &recordingServer{
+		ResourceProviderServer: server,
+		path:                   path,
+		opts:                   opts,
+		redact:                 redact,
+	}
+}
+
+// Entry is a single call in the log. It matches the format of the logs emitted by PULUMI_DEBUG_GRPC.
+type Entry struct {
+	Method   string          `json:"method"`
+	Request  json.RawMessage `json:"request,omitempty"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Errors   []string        `json:"errors,omitempty"`
+}
+
+type recordingServer struct {
+	pulumirpc.ResourceProviderServer
+
+	path   string
+	opts   Options
+	redact map[string]bool
+
+	mu      sync.Mutex
+	written int // the number of entries in the log
+}
+
+func record[Req, Resp proto.Message](
+	ctx context.Context, s *recordingServer, method string, req Req, serve func(context.Context, Req) (Resp, error),
+) (Resp, error) {
+	resp, err := serve(ctx, req)
+	entry := Entry{Method: "/pulumirpc.ResourceProvider/" + method, Request: s.marshal(req, nil)}
+	if err != nil {
+		entry.Errors = []string{err.Error()}
+	} else {
+		entry.Response = s.marshal(resp, s.opts.Normalize)
+	}
+	s.append(entry)
+	return resp, err
+}
+
+func (s *recordingServer) marshal(m proto.Message, normalize []*regexp.Regexp) json.RawMessage {
+	bytes, err := jsonpb.Marshal(m)
+	if err != nil {
+		glog.V(9).Infof("failed to record %T: %v", m, err)
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(bytes, &value); err != nil {
+		glog.V(9).Infof("failed to record %T: %v", m, err)
+		return nil
+	}
+	bytes, err = json.Marshal(s.sanitize(value, normalize))
+	if err != nil {
+		glog.V(9).Infof("failed to record %T: %v", m, err)
+		return nil
+	}
+	return bytes
+}
+
+// sanitize redacts secrets and configured properties, and replaces the strings matching normalize.
+func (s *recordingServer) sanitize(value interface{}, normalize []*regexp.Regexp) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if sig, ok := v[sigKey]; ok && sig == secretSig && !s.opts.KeepSecrets {
+			return map[string]interface{}{sigKey: secretSig, "value": Placeholder}
+		}
+		for k, e := range v {
+			if s.redact[k] {
+				v[k] = Placeholder
+				continue
+			}
+			v[k] = s.sanitize(e, normalize)
+		}
+		return v
+	case []interface{}:
+		for i, e := range v {
+			v[i] = s.sanitize(e, normalize)
+		}
+		return v
+	case string:
+		for _, re := range normalize {
+			if re.MatchString(v) {
+				return Placeholder
+			}
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func (s *recordingServer) append(entry Entry) {
+	bytes, err := json.MarshalIndent(entry, "  ", "  ")
+	if err != nil {
+		glog.V(9).Infof("failed to record %s: %v", entry.Method, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.write(bytes); err != nil {
+		glog.V(9).Infof("failed to write the gRPC recording to %s: %v", s.path, err)
+		return
+	}
+	s.written++
+}
+
+// logEnd closes the JSON array of entries. Every write ends with it so that the log stays valid.
+const logEnd = "\n]\n"
+
+// write appends an entry to the log by overwriting the closing logEnd of the previous write, so that the cost of
+// recording a call does not grow with the number of calls recorded before it.
+func (s *recordingServer) write(entry []byte) error {
+	if s.written == 0 {
+		return os.WriteFile(s.path, append(append([]byte("[\n  "), entry...), logEnd...), 0o600)
+	}
+	f, err := os.OpenFile(s.path, os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Seek(-int64(len(logEnd)), io.SeekEnd); err != nil {
+		contract.IgnoreClose(f)
+		return err
+	}
+	if _, err := f.Write(append(append([]byte(",\n  "), entry...), logEnd...)); err != nil {
+		contract.IgnoreClose(f)
+		return err
+	}
+	return f.Close()
+}
+
+func (s *recordingServer) GetSchema(
+	ctx context.Context, req *pulumirpc.GetSchemaRequest,
+) (*pulumirpc.GetSchemaResponse, error) {
+	return record(ctx, s, "GetSchema", req, s.ResourceProviderServer.GetSchema)
+}
+
+func (s *recordingServer) CheckConfig(
+	ctx context.Context, req *pulumirpc.CheckRequest,
+) (*pulumirpc.CheckResponse, error) {
+	return record(ctx, s, "CheckConfig", req, s.ResourceProviderServer.CheckConfig)
+}
+
+func (s *recordingServer) DiffConfig(
+	ctx context.Context, req *pulumirpc.DiffRequest,
+) (*pulumirpc.DiffResponse, error) {
+	return record(ctx, s, "DiffConfig", req, s.ResourceProviderServer.DiffConfig)
+}
+
+func (s *recordingServer) Configure(
+	ctx context.Context, req *pulumirpc.ConfigureRequest,
+) (*pulumirpc.ConfigureResponse, error) {
+	return record(ctx, s, "Configure", req, s.ResourceProviderServer.Configure)
+}
+
+func (s *recordingServer) Invoke(
+	ctx context.Context, req *pulumirpc.InvokeRequest,
+) (*pulumirpc.InvokeResponse, error) {
+	return record(ctx, s, "Invoke", req, s.ResourceProviderServer.Invoke)
+}
+
+func (s *recordingServer) Call(ctx context.Context, req *pulumirpc.CallRequest) (*pulumirpc.CallResponse, error) {
+	return record(ctx, s, "Call", req, s.ResourceProviderServer.Call)
+}
+
+func (s *recordingServer) Check(ctx context.Context, req *pulumirpc.CheckRequest) (*pulumirpc.CheckResponse, error) {
+	return record(ctx, s, "Check", req, s.ResourceProviderServer.Check)
+}
+
+func (s *recordingServer) Diff(ctx context.Context, req *pulumirpc.DiffRequest) (*pulumirpc.DiffResponse, error) {
+	return record(ctx, s, "Diff", req, s.ResourceProviderServer.Diff)
+}
+
+func (s *recordingServer) Create(
+	ctx context.Context, req *pulumirpc.CreateRequest,
+) (*pulumirpc.CreateResponse, error) {
+	return record(ctx, s, "Create", req, s.ResourceProviderServer.Create)
+}
+
+func (s *recordingServer) Read(ctx context.Context, req *pulumirpc.ReadRequest) (*pulumirpc.ReadResponse, error) {
+	return record(ctx, s, "Read", req, s.ResourceProviderServer.Read)
+}
+
+func (s *recordingServer) Update(
+	ctx context.Context, req *pulumirpc.UpdateRequest,
+) (*pulumirpc.UpdateResponse, error) {
+	return record(ctx, s, "Update", req, s.ResourceProviderServer.Update)
+}
+
+func (s *recordingServer) Delete(ctx context.Context, req *pulumirpc.DeleteRequest) (*emptypb.Empty, error) {
+	return record(ctx, s, "Delete", req, s.ResourceProviderServer.Delete)
+}
+
+func (s *recordingServer) Construct(
+	ctx context.Context, req *pulumirpc.ConstructRequest,
+) (*pulumirpc.ConstructResponse, error) {
+	return record(ctx, s, "Construct", req, s.ResourceProviderServer.Construct)
+}
+
+func (s *recordingServer) Cancel(ctx context.Context, req *emptypb.Empty) (*emptypb.Empty, error) {
+	return record(ctx, s, "Cancel", req, s.ResourceProviderServer.Cancel)
+}
+
+func (s *recordingServer) Attach(ctx context.Context, req *pulumirpc.PluginAttach) (*emptypb.Empty, error) {
+	return record(ctx, s, "Attach", req, s.ResourceProviderServer.Attach)
+}
+
+func (s *recordingServer) GetMapping(
+	ctx context.Context, req *pulumirpc.GetMappingRequest,
+) (*pulumirpc.GetMappingResponse, error) {
+	return record(ctx, s, "GetMapping", req, s.ResourceProviderServer.GetMapping)
+}
+
+func (s *recordingServer) GetMappings(
+	ctx context.Context, req *pulumirpc.GetMappingsRequest,
+) (*pulumirpc.GetMappingsResponse, error) {
+	return record(ctx, s, "GetMappings", req, s.ResourceProviderServer.GetMappings)
+}
@@ -0,0 +1,162 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recording
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pulumi/providertest/replay"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A provider whose outputs change with every call.
+type clockServer struct {
+	pulumirpc.UnimplementedResourceProviderServer
+}
+
+func (clockServer) Create(_ context.Context, req *pulumirpc.CreateRequest) (*pulumirpc.CreateResponse, error) {
+	props, err := plugin.UnmarshalProperties(req.GetProperties(), plugin.MarshalOptions{KeepSecrets: true})
+	if err != nil {
+		return nil, err
+	}
+	if props["fail"].IsBool() && props["fail"].BoolValue() {
+		return nil, fmt.Errorf("failed to create")
+	}
+	props["createdAt"] = resource.NewStringProperty(time.Now().Format(time.RFC3339Nano))
+	outs, err := plugin.MarshalProperties(props, plugin.MarshalOptions{KeepSecrets: true})
+	if err != nil {
+		return nil, err
+	}
+	return &pulumirpc.CreateResponse{Id: "id", Properties: outs}, nil
+}
+
+// Read fails unless the ID is a UUID, so that replaying a Read with a normalized ID fails.
+func (clockServer) Read(_ context.Context, req *pulumirpc.ReadRequest) (*pulumirpc.ReadResponse, error) {
+	if !DefaultNormalize[1].MatchString(req.GetId()) {
+		return nil, fmt.Errorf("invalid ID %q", req.GetId())
+	}
+	outs, err := plugin.MarshalProperties(resource.PropertyMap{
+		"readAt": resource.NewStringProperty(time.Now().Format(time.RFC3339Nano)),
+	}, plugin.MarshalOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return &pulumirpc.ReadResponse{Id: req.GetId(), Properties: outs}, nil
+}
+
+func TestRecording(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.json")
+	server := New(clockServer{}, path, Options{
+		RedactProperties: []string{"password"},
+		Normalize:        DefaultNormalize,
+	})
+
+	create := func(props resource.PropertyMap) error {
+		s, err := plugin.MarshalProperties(props, plugin.MarshalOptions{KeepSecrets: true})
+		require.NoError(t, err)
+		_, err = server.Create(context.Background(), &pulumirpc.CreateRequest{
+			Urn:        "urn:pulumi:dev::stack::test:index:Clock::c",
+			Properties: s,
+		})
+		return err
+	}
+
+	require.NoError(t, create(resource.PropertyMap{
+		"name":     resource.NewStringProperty("clock"),
+		"password": resource.NewStringProperty("hunter2"),
+		"token":    resource.MakeSecret(resource.NewStringProperty("s3cr3t")),
+	}))
+	require.Error(t, create(resource.PropertyMap{"fail": resource.NewBoolProperty(true)}))
+
+	log, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(log), "hunter2")
+	assert.NotContains(t, string(log), "s3cr3t")
+
+	assert.JSONEq(t, `[
+	  {
+	    "method": "/pulumirpc.ResourceProvider/Create",
+	    "request": {
+	      "urn": "urn:pulumi:dev::stack::test:index:Clock::c",
+	      "properties": {
+	        "name": "clock",
+	        "password": "*",
+	        "token": {"4dabf18193072939515e22adb298388d": "1b47061264138c4ac30d75fd1eb44270", "value": "*"}
+	      }
+	    },
+	    "response": {
+	      "id": "id",
+	      "properties": {
+	        "createdAt": "*",
+	        "name": "clock",
+	        "password": "*",
+	        "token": {"4dabf18193072939515e22adb298388d": "1b47061264138c4ac30d75fd1eb44270", "value": "*"}
+	      }
+	    }
+	  },
+	  {
+	    "method": "/pulumirpc.ResourceProvider/Create",
+	    "request": {
+	      "urn": "urn:pulumi:dev::stack::test:index:Clock::c",
+	      "properties": {"fail": true}
+	    },
+	    "errors": ["failed to create"]
+	  }
+	]`, string(log))
+
+	// The recording replays against the original server even though its outputs changed.
+	replay.ReplaySequence(t, clockServer{}, string(log))
+}
+
+func TestRecordingKeepsRequests(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.json")
+	server := New(clockServer{}, path, Options{Normalize: DefaultNormalize})
+
+	const id = "0b9f3a5e-2f5c-4a77-9d1e-5b8f3c2a1d4e"
+	_, err := server.Read(context.Background(), &pulumirpc.ReadRequest{
+		Id:  id,
+		Urn: "urn:pulumi:dev::stack::test:index:Clock::c",
+	})
+	require.NoError(t, err)
+
+	log, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+	  {
+	    "method": "/pulumirpc.ResourceProvider/Read",
+	    "request": {"id": "`+id+`", "urn": "urn:pulumi:dev::stack::test:index:Clock::c"},
+	    "response": {"id": "*", "properties": {"readAt": "*"}}
+	  }
+	]`, string(log))
+
+	replay.ReplaySequence(t, clockServer{}, string(log))
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv(EnvVar, "")
+	assert.Equal(t, clockServer{}, FromEnv(clockServer{}))
+
+	t.Setenv(EnvVar, filepath.Join(t.TempDir(), "log.json"))
+	assert.IsType(t, &recordingServer{}, FromEnv(clockServer{}))
+}
@@ -0,0 +1,173 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This script assists the rollout of WithPlanResourceChange across bridged providers by aggregating the comparisons
+// reported by the TryPlanState diff strategy into a per-resource readiness report.
+//
+// A resource is "ready" when both strategies always agreed, needs "review" when they only disagreed on attribute
+// diffs, and is "blocked" when PlanState failed or changed the update or replace decision.
+//
+// How to run:
+//
+//	cd ~/code/pulumi-aws/examples
+//	PULUMI_DIFF_STRATEGY=TryPlanState PULUMI_DIFF_STRATEGY_REPORT=$PWD/diffs.jsonl go test ./...
+//	go run ~/code/pulumi-terraform-bridge/unstable/scripts/planstatereport/main.go diffs.jsonl
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+
+	sdkv2 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2"
+)
+
+type resourceReport struct {
+	ResourceType    string         `json:"resourceType"`
+	Readiness       string         `json:"readiness"`
+	Diffs           int            `json:"diffs"`
+	Mismatches      int            `json:"mismatches"`
+	DecisionChanges int            `json:"decisionChanges"`
+	Errors          int            `json:"errors"`
+	Attributes      map[string]int `json:"attributes,omitempty"`
+}
+
+// Numeric flatmap segments are list indexes or set hashes, which are merged to aggregate paths across diffs.
+var indexSegment = regexp.MustCompile(`(^|\.)\d+(\.|$)`)
+
+func main() {
+	jsonOut := flag.Bool("json", false, "print the report as JSON instead of Markdown")
+	flag.Parse()
+	if flag.NArg() == 0 {
+		log.Fatal("usage: planstatereport [-json] REPORT.jsonl...")
+	}
+
+	reports := map[string]*resourceReport{}
+	for _, path := range flag.Args() {
+		noerr(readComparisons(path, func(c sdkv2.DiffComparison) {
+			r, ok := reports[c.ResourceType]
+			if !ok {
+				r = &resourceReport{ResourceType: c.ResourceType, Attributes: map[string]int{}}
+				reports[c.ResourceType] = r
+			}
+			r.Diffs++
+			if c.Mismatch() {
+				r.Mismatches++
+			}
+			if c.DecisionChanged {
+				r.DecisionChanges++
+			}
+			if c.PlanStateError != "" {
+				r.Errors++
+			}
+			for _, a := range c.Attributes {
+				r.Attributes[normalizePath(a)]++
+			}
+		}))
+	}
+
+	sorted := make([]*resourceReport, 0, len(reports))
+	for _, r := range reports {
+		switch {
+		case r.DecisionChanges > 0 || r.Errors > 0:
+			r.Readiness = "blocked"
+		case r.Mismatches > 0:
+			r.Readiness = "review"
+		default:
+			r.Readiness = "ready"
+		}
+		sorted = append(sorted, r)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ResourceType < sorted[j].ResourceType
+	})
+
+	if *jsonOut {
+		bytes, err := json.MarshalIndent(sorted, "", "  ")
+		noerr(err)
+		fmt.Println(string(bytes))
+		return
+	}
+
+	fmt.Println("| Resource | Readiness | Diffs | Mismatches | Decision changes | Errors | Differing attributes |")
+	fmt.Println("|---|---|---|---|---|---|---|")
+	for _, r := range sorted {
+		fmt.Printf("| %s | %s | %d | %d | %d | %d | %s |\n", r.ResourceType, r.Readiness, r.Diffs, r.Mismatches,
+			r.DecisionChanges, r.Errors, formatAttributes(r.Attributes))
+	}
+}
+
+func readComparisons(path string, visit func(sdkv2.DiffComparison)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var c sdkv2.DiffComparison
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+			return fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		visit(c)
+	}
+	return scanner.Err()
+}
+
+func normalizePath(path string) string {
+	// Apply twice since adjacent numeric segments share a separator.
+	for i := 0; i < 2; i++ {
+		path = indexSegment.ReplaceAllString(path, "${1}#${2}")
+	}
+	return path
+}
+
+// formatAttributes lists the attributes by decreasing number of mismatches.
+func formatAttributes(attributes map[string]int) string {
+	paths := make([]string, 0, len(attributes))
+	for p := range attributes {
+		paths = append(paths, p)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if attributes[paths[i]] != attributes[paths[j]] {
+			return attributes[paths[i]] > attributes[paths[j]]
+		}
+		return paths[i] < paths[j]
+	})
+
+	var result string
+	for i, p := range paths {
+		if i > 0 {
+			result += ", "
+		}
+		result += fmt.Sprintf("`%s` (%d)", p, attributes[p])
+	}
+	return result
+}
+
+func noerr(err error) {
+	if err != nil {
+		log.Fatal(err)
+	}
+}
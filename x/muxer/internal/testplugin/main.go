@@ -0,0 +1,70 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// A minimal provider plugin serving the test:index:Remote resource, used to test muxing out-of-process plugins.
+//
+// Setting TEST_PLUGIN_MODE changes the behavior of the plugin: "silent" never announces a port, and "hang" blocks
+// Create until the request is aborted.
+package main
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type server struct {
+	rpc.UnimplementedResourceProviderServer
+}
+
+func (server) GetSchema(context.Context, *rpc.GetSchemaRequest) (*rpc.GetSchemaResponse, error) {
+	return &rpc.GetSchemaResponse{Schema: `{"name":"test","resources":{"test:index:Remote":{}}}`}, nil
+}
+
+func (server) GetPluginInfo(context.Context, *emptypb.Empty) (*rpc.PluginInfo, error) {
+	return &rpc.PluginInfo{Version: "1.0.0"}, nil
+}
+
+func (server) Create(ctx context.Context, req *rpc.CreateRequest) (*rpc.CreateResponse, error) {
+	if os.Getenv("TEST_PLUGIN_MODE") == "hang" {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return &rpc.CreateResponse{Id: "remote"}, nil
+}
+
+func (server) Attach(context.Context, *rpc.PluginAttach) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+func (server) Cancel(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+func main() {
+	if os.Getenv("TEST_PLUGIN_MODE") == "silent" {
+		time.Sleep(time.Hour)
+	}
+	err := provider.Main("test", func(*provider.HostClient) (rpc.ResourceProviderServer, error) {
+		return server{}, nil
+	})
+	if err != nil {
+		cmdutil.ExitError(err.Error())
+	}
+}
@@ -17,6 +17,7 @@ package muxer
 import (
 	"context"
 	"encoding/json"
+	"io"
 
 	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
 	"github.com/pulumi/pulumi/pkg/v3/resource/provider"
@@ -65,7 +66,7 @@ import (
 // A dispatch strategy doesn't make sense for methods related to the provider as a
 // whole. The following methods are broadcast to all providers:
 //
-//   - Cancel: Each server receives a cancel request.
+//   - Cancel: Each server receives a cancel request. Plugins launched by PluginEndpoint are then stopped.
 //
 // The remaining methods are treated specially by the Muxed server:
 //
@@ -76,7 +77,8 @@ import (
 //
 //   - Attach: `Attach` is never called on Muxed providers. Instead the host passed into
 //     `Mux` is replaced. If subsidiary servers where constructed with the same `host` as
-//     passed to `Mux`, then they will observe the new `host` spurred by `Attach`.
+//     passed to `Mux`, then they will observe the new `host` spurred by `Attach`. Servers out
+//     of process, see PluginEndpoint and AddressEndpoint, receive the `Attach` request.
 //
 //   - GetMapping: `GetMapping` dispatches on all underlerver Servers. If zero or 1 server
 //     responds with a non-empty data section, we call GetMappingHandler[Key] to merge the
@@ -102,6 +104,12 @@ func (m Main) Server(host *provider.HostClient, module, version string) (rpc.Res
 		var err error
 		servers[i], err = s.Server(host)
 		if err != nil {
+			// Stop the plugins launched for earlier endpoints.
+			for _, started := range servers[:i] {
+				if closer, ok := started.(io.Closer); ok {
+					contract.IgnoreError(closer.Close())
+				}
+			}
 			return nil, err
 		}
 	}
@@ -138,6 +146,8 @@ func (m Main) Server(host *provider.HostClient, module, version string) (rpc.Res
 	return server, nil
 }
 
+// Endpoint is a server muxed by Main. Server either constructs an in-process server or connects to a provider served
+// out of process, see PluginEndpoint and AddressEndpoint.
 type Endpoint struct {
 	Server func(*provider.HostClient) (rpc.ResourceProviderServer, error)
 }
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"sync"
 
 	"github.com/golang/glog"
@@ -110,6 +111,16 @@ func (m *muxer) getResource(token string) server {
 	return m.servers[i]
 }
 
+// getMethodResource finds the server of the resource a method token such as "pkg:mod:Resource/method" is declared
+// on. Methods are implemented by the server of their resource even if the dispatch table does not list them.
+func (m *muxer) getMethodResource(token string) server {
+	i := strings.LastIndex(token, "/")
+	if i == -1 || i < strings.LastIndex(token, ":") {
+		return nil
+	}
+	return m.getResource(token[:i])
+}
+
 func (m *muxer) GetSchema(ctx context.Context, req *rpc.GetSchemaRequest) (*rpc.GetSchemaResponse, error) {
 	if req.Version != SchemaVersion {
 		return nil, fmt.Errorf("Expected schema version %d, got %d",
@@ -352,6 +363,9 @@ func (m *muxer) StreamInvoke(req *rpc.InvokeRequest, s rpc.ResourceProvider_Stre
 
 func (m *muxer) Call(ctx context.Context, req *rpc.CallRequest) (*rpc.CallResponse, error) {
 	server := m.getFunction(req.GetTok())
+	if server == nil {
+		server = m.getMethodResource(req.GetTok())
+	}
 	if server == nil {
 		return nil, status.Errorf(codes.NotFound, "Resource Method '%s' not found.", req.GetTok())
 	}
@@ -410,7 +424,8 @@ func (m *muxer) Cancel(ctx context.Context, e *emptypb.Empty) (*emptypb.Empty, e
 	}
 	errs := new(multierror.Error)
 	for _, err := range asyncJoin(subs) {
-		if err != nil {
+		// Cancellation is advisory, so servers that cannot cancel do not fail the request.
+		if err != nil && status.Code(err) != codes.Unimplemented {
 			errs.Errors = append(errs.Errors, err)
 		}
 	}
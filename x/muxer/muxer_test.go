@@ -24,6 +24,7 @@ import (
 	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/structpb"
 )
@@ -197,3 +198,129 @@ func (s diffConfigServer) DiffConfig(
 	}
 	return s.UnimplementedResourceProviderServer.DiffConfig(ctx, req)
 }
+
+func TestCall(t *testing.T) {
+	ctx := context.Background()
+	m := &muxer{
+		dispatchTable: dispatchTable{
+			Resources: map[string]int{"pkg:index:Cluster": 1},
+			Functions: map[string]int{"pkg:index:getCluster": 0, "pkg:index:Cluster/listNodes": 0},
+		},
+		servers: []server{&callServer{name: "0"}, &callServer{name: "1"}},
+	}
+
+	call := func(tok string) (string, error) {
+		resp, err := m.Call(ctx, &pulumirpc.CallRequest{Tok: tok})
+		if err != nil {
+			return "", err
+		}
+		return resp.Return.Fields["server"].GetStringValue(), nil
+	}
+
+	t.Run("function", func(t *testing.T) {
+		server, err := call("pkg:index:Cluster/listNodes")
+		require.NoError(t, err)
+		assert.Equal(t, "0", server)
+	})
+
+	t.Run("method of resource", func(t *testing.T) {
+		server, err := call("pkg:index:Cluster/getKubeconfig")
+		require.NoError(t, err)
+		assert.Equal(t, "1", server)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := call("pkg:index:Unknown/getKubeconfig")
+		assert.ErrorContains(t, err, "not found")
+		_, err = call("pkg:index/sub:getThing")
+		assert.ErrorContains(t, err, "not found")
+	})
+}
+
+type callServer struct {
+	pulumirpc.UnimplementedResourceProviderServer
+	name string
+}
+
+func (s *callServer) Call(context.Context, *pulumirpc.CallRequest) (*pulumirpc.CallResponse, error) {
+	return &pulumirpc.CallResponse{Return: &structpb.Struct{Fields: map[string]*structpb.Value{
+		"server": structpb.NewStringValue(s.name),
+	}}}, nil
+}
+
+func TestStreamInvoke(t *testing.T) {
+	m := &muxer{
+		dispatchTable: dispatchTable{
+			Functions: map[string]int{"pkg:index:getImages": 1},
+		},
+		servers: []server{&streamInvokeServer{name: "0"}, &streamInvokeServer{name: "1"}},
+	}
+
+	stream := &invokeStream{ctx: context.Background()}
+	err := m.StreamInvoke(&pulumirpc.InvokeRequest{Tok: "pkg:index:getImages"}, stream)
+	require.NoError(t, err)
+	require.Len(t, stream.sent, 1)
+	assert.Equal(t, "1", stream.sent[0].Return.Fields["server"].GetStringValue())
+
+	err = m.StreamInvoke(&pulumirpc.InvokeRequest{Tok: "pkg:index:getUnknown"}, stream)
+	assert.ErrorContains(t, err, "not found")
+}
+
+type streamInvokeServer struct {
+	pulumirpc.UnimplementedResourceProviderServer
+	name string
+}
+
+func (s *streamInvokeServer) StreamInvoke(
+	_ *pulumirpc.InvokeRequest, stream pulumirpc.ResourceProvider_StreamInvokeServer,
+) error {
+	return stream.Send(&pulumirpc.InvokeResponse{Return: &structpb.Struct{Fields: map[string]*structpb.Value{
+		"server": structpb.NewStringValue(s.name),
+	}}})
+}
+
+type invokeStream struct {
+	grpc.ServerStream
+
+	ctx  context.Context
+	sent []*pulumirpc.InvokeResponse
+}
+
+func (s *invokeStream) Context() context.Context { return s.ctx }
+
+func (s *invokeStream) Send(resp *pulumirpc.InvokeResponse) error {
+	s.sent = append(s.sent, resp)
+	return nil
+}
+
+func TestCancel(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("dispatch", func(t *testing.T) {
+		s1, s2 := &cancelServer{}, &cancelServer{}
+		m := &muxer{servers: []server{s1, s2, &pulumirpc.UnimplementedResourceProviderServer{}}}
+		_, err := m.Cancel(ctx, &emptypb.Empty{})
+		require.NoError(t, err)
+		assert.True(t, s1.canceled)
+		assert.True(t, s2.canceled)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		s1 := &cancelServer{}
+		m := &muxer{servers: []server{s1, &cancelServer{err: fmt.Errorf("boom")}}}
+		_, err := m.Cancel(ctx, &emptypb.Empty{})
+		assert.ErrorContains(t, err, "boom")
+		assert.True(t, s1.canceled)
+	})
+}
+
+type cancelServer struct {
+	pulumirpc.UnimplementedResourceProviderServer
+	canceled bool
+	err      error
+}
+
+func (s *cancelServer) Cancel(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	s.canceled = true
+	return &emptypb.Empty{}, s.err
+}
@@ -0,0 +1,308 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package muxer
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/rpcutil"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+var (
+	// How long a plugin launched by PluginEndpoint is given to announce its port before it is killed.
+	pluginStartTimeout = time.Minute
+	// How long a plugin launched by PluginEndpoint is given to exit after being interrupted before it is killed.
+	pluginExitTimeout = 5 * time.Second
+	// How long Close waits for in-flight requests before disconnecting from the provider, which aborts them.
+	requestDrainTimeout = 30 * time.Second
+)
+
+// PluginEndpoint returns an Endpoint served by the Pulumi provider plugin binary at path, such as an independently
+// released native provider.
+//
+// The plugin is launched with args followed by the address of the engine, and dialed on the port it prints, as the
+// Pulumi engine does. The plugin is killed if it does not print a port within a minute. Attach is forwarded to the
+// plugin. Cancel is forwarded to the plugin, which is then stopped once in-flight requests have returned, or aborted
+// after 30 seconds.
+//
+// The address of the engine is required, so the Endpoint fails when Main is served without an engine host.
+func PluginEndpoint(path string, args ...string) Endpoint {
+	return Endpoint{Server: func(host *provider.HostClient) (rpc.ResourceProviderServer, error) {
+		if host == nil {
+			return nil, fmt.Errorf("launching %s: plugins require the engine host", path)
+		}
+		return launchPlugin(path, append(append([]string{}, args...), host.EngineConn().Target()))
+	}}
+}
+
+// AddressEndpoint returns an Endpoint served by the provider already listening for gRPC requests at address, such as
+// "127.0.0.1:50051". Attach and Cancel are forwarded to the provider, whose lifecycle is managed by its launcher.
+func AddressEndpoint(address string) Endpoint {
+	return Endpoint{Server: func(*provider.HostClient) (rpc.ResourceProviderServer, error) {
+		return dialRemote(address, nil)
+	}}
+}
+
+func launchPlugin(path string, args []string) (*remoteServer, error) {
+	cmd := exec.Command(path, args...)
+	cmd.Stderr = os.Stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("launching %s: %w", path, err)
+	}
+
+	// The plugin announces its port on the first line of stdout. The rest of stdout is forwarded to stderr, since
+	// the stdout of the muxer is reserved for announcing its own port.
+	reader := bufio.NewReader(stdout)
+	type announcement struct {
+		line string
+		err  error
+	}
+	announced := make(chan announcement, 1)
+	go func() {
+		line, err := reader.ReadString('\n')
+		announced <- announcement{line, err}
+	}()
+	var line string
+	select {
+	case a := <-announced:
+		line, err = a.line, a.err
+	case <-time.After(pluginStartTimeout):
+		err = fmt.Errorf("timed out after %v", pluginStartTimeout)
+	}
+	port, convErr := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || convErr != nil {
+		// Killing the plugin closes its stdout, which unblocks the pending read.
+		killErr := cmd.Process.Kill()
+		waitErr := cmd.Wait()
+		return nil, fmt.Errorf("%s did not announce a port, got %q: %w", path, line,
+			errors.Join(err, convErr, killErr, waitErr))
+	}
+	go func() {
+		_, err := io.Copy(os.Stderr, reader)
+		contract.IgnoreError(err)
+	}()
+
+	s, err := dialRemote("127.0.0.1:"+strconv.Itoa(port), cmd)
+	if err != nil {
+		return nil, errors.Join(err, cmd.Process.Kill())
+	}
+	return s, nil
+}
+
+func dialRemote(address string, cmd *exec.Cmd) (*remoteServer, error) {
+	conn, err := grpc.Dial(address,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		rpcutil.GrpcChannelOptions())
+	if err != nil {
+		return nil, fmt.Errorf("dialing %s: %w", address, err)
+	}
+	return &remoteServer{client: rpc.NewResourceProviderClient(conn), conn: conn, cmd: cmd}, nil
+}
+
+// remoteServer forwards requests to a provider served out of process.
+type remoteServer struct {
+	rpc.UnimplementedResourceProviderServer
+
+	client rpc.ResourceProviderClient
+	conn   *grpc.ClientConn
+	cmd    *exec.Cmd // the plugin process, if launched by PluginEndpoint.
+
+	// requests is read-locked by every forwarded request and locked by Close, so that the plugin is only stopped
+	// once in-flight requests have drained or requestDrainTimeout has expired.
+	requests  sync.RWMutex
+	closeOnce sync.Once
+	closeErr  error
+}
+
+var _ rpc.ResourceProviderServer = ((*remoteServer)(nil))
+
+// track marks a request as in flight until the returned function is called.
+func (s *remoteServer) track() func() {
+	s.requests.RLock()
+	return s.requests.RUnlock
+}
+
+// Close waits for in-flight requests to finish, then disconnects from the provider and stops the plugin process, if
+// any. Requests still in flight after requestDrainTimeout are aborted. Requests received after Close fail.
+func (s *remoteServer) Close() error {
+	s.closeOnce.Do(func() {
+		drained := make(chan struct{})
+		go func() {
+			s.requests.Lock()
+			close(drained)
+			s.requests.Unlock()
+		}()
+		select {
+		case <-drained:
+		case <-time.After(requestDrainTimeout):
+			// Disconnecting below aborts the requests, which lets the pending Lock through.
+		}
+
+		s.closeErr = s.conn.Close()
+		if s.cmd == nil {
+			return
+		}
+
+		// Ask the plugin to exit, then kill it if it does not. Interrupts are not supported on Windows, where the
+		// plugin is killed once the timeout expires.
+		contract.IgnoreError(s.cmd.Process.Signal(os.Interrupt))
+		exited := make(chan error, 1)
+		go func() { exited <- s.cmd.Wait() }()
+		select {
+		case <-exited:
+		case <-time.After(pluginExitTimeout):
+			s.closeErr = errors.Join(s.closeErr, s.cmd.Process.Kill())
+			<-exited
+		}
+	})
+	return s.closeErr
+}
+
+func (s *remoteServer) Cancel(ctx context.Context, req *emptypb.Empty) (*emptypb.Empty, error) {
+	resp, err := s.client.Cancel(ctx, req)
+	if s.cmd != nil {
+		// Stop the plugin so that it does not outlive the muxer, which is stopped by the engine after Cancel. Close
+		// lets the requests aborted by Cancel return first.
+		err = errors.Join(err, s.Close())
+	}
+	return resp, err
+}
+
+func (s *remoteServer) Attach(ctx context.Context, req *rpc.PluginAttach) (*emptypb.Empty, error) {
+	defer s.track()()
+	return s.client.Attach(ctx, req)
+}
+
+func (s *remoteServer) GetSchema(ctx context.Context, req *rpc.GetSchemaRequest) (*rpc.GetSchemaResponse, error) {
+	defer s.track()()
+	return s.client.GetSchema(ctx, req)
+}
+
+func (s *remoteServer) CheckConfig(ctx context.Context, req *rpc.CheckRequest) (*rpc.CheckResponse, error) {
+	defer s.track()()
+	return s.client.CheckConfig(ctx, req)
+}
+
+func (s *remoteServer) DiffConfig(ctx context.Context, req *rpc.DiffRequest) (*rpc.DiffResponse, error) {
+	defer s.track()()
+	return s.client.DiffConfig(ctx, req)
+}
+
+func (s *remoteServer) Configure(ctx context.Context, req *rpc.ConfigureRequest) (*rpc.ConfigureResponse, error) {
+	defer s.track()()
+	return s.client.Configure(ctx, req)
+}
+
+func (s *remoteServer) Invoke(ctx context.Context, req *rpc.InvokeRequest) (*rpc.InvokeResponse, error) {
+	defer s.track()()
+	return s.client.Invoke(ctx, req)
+}
+
+func (s *remoteServer) StreamInvoke(req *rpc.InvokeRequest, stream rpc.ResourceProvider_StreamInvokeServer) error {
+	defer s.track()()
+	client, err := s.client.StreamInvoke(stream.Context(), req)
+	if err != nil {
+		return err
+	}
+	for {
+		resp, err := client.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *remoteServer) Call(ctx context.Context, req *rpc.CallRequest) (*rpc.CallResponse, error) {
+	defer s.track()()
+	return s.client.Call(ctx, req)
+}
+
+func (s *remoteServer) Check(ctx context.Context, req *rpc.CheckRequest) (*rpc.CheckResponse, error) {
+	defer s.track()()
+	return s.client.Check(ctx, req)
+}
+
+func (s *remoteServer) Diff(ctx context.Context, req *rpc.DiffRequest) (*rpc.DiffResponse, error) {
+	defer s.track()()
+	return s.client.Diff(ctx, req)
+}
+
+func (s *remoteServer) Create(ctx context.Context, req *rpc.CreateRequest) (*rpc.CreateResponse, error) {
+	defer s.track()()
+	return s.client.Create(ctx, req)
+}
+
+func (s *remoteServer) Read(ctx context.Context, req *rpc.ReadRequest) (*rpc.ReadResponse, error) {
+	defer s.track()()
+	return s.client.Read(ctx, req)
+}
+
+func (s *remoteServer) Update(ctx context.Context, req *rpc.UpdateRequest) (*rpc.UpdateResponse, error) {
+	defer s.track()()
+	return s.client.Update(ctx, req)
+}
+
+func (s *remoteServer) Delete(ctx context.Context, req *rpc.DeleteRequest) (*emptypb.Empty, error) {
+	defer s.track()()
+	return s.client.Delete(ctx, req)
+}
+
+func (s *remoteServer) Construct(ctx context.Context, req *rpc.ConstructRequest) (*rpc.ConstructResponse, error) {
+	defer s.track()()
+	return s.client.Construct(ctx, req)
+}
+
+func (s *remoteServer) GetPluginInfo(ctx context.Context, req *emptypb.Empty) (*rpc.PluginInfo, error) {
+	defer s.track()()
+	return s.client.GetPluginInfo(ctx, req)
+}
+
+func (s *remoteServer) GetMapping(ctx context.Context, req *rpc.GetMappingRequest) (*rpc.GetMappingResponse, error) {
+	defer s.track()()
+	return s.client.GetMapping(ctx, req)
+}
+
+func (s *remoteServer) GetMappings(
+	ctx context.Context, req *rpc.GetMappingsRequest,
+) (*rpc.GetMappingsResponse, error) {
+	defer s.track()()
+	return s.client.GetMappings(ctx, req)
+}
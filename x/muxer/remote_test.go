@@ -0,0 +1,215 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package muxer
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/pkg/v3/resource/provider"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// buildTestPlugin builds the plugin in internal/testplugin and returns its path, along with a host client whose engine
+// is never dialed, since the test plugin does not log.
+func buildTestPlugin(t *testing.T) (string, *provider.HostClient) {
+	if testing.Short() {
+		t.Skip("building the test plugin is slow")
+	}
+	bin := filepath.Join(t.TempDir(), "pulumi-resource-test")
+	out, err := exec.Command("go", "build", "-o", bin, "./internal/testplugin").CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	host, err := provider.NewHostClient("127.0.0.1:1")
+	require.NoError(t, err)
+	return bin, host
+}
+
+// setTimeout overrides one of the timeouts of remote.go for the duration of the test.
+func setTimeout(t *testing.T, timeout *time.Duration, d time.Duration) {
+	prev := *timeout
+	*timeout = d
+	t.Cleanup(func() { *timeout = prev })
+}
+
+func TestPluginEndpoint(t *testing.T) {
+	bin, host := buildTestPlugin(t)
+
+	ctx := context.Background()
+	m, err := Main{Servers: []Endpoint{
+		PluginEndpoint(bin),
+		{Server: func(*provider.HostClient) (pulumirpc.ResourceProviderServer, error) {
+			return localServer{}, nil
+		}},
+	}}.Server(host, "test", "0.0.1")
+	require.NoError(t, err)
+
+	resp, err := m.GetSchema(ctx, &pulumirpc.GetSchemaRequest{})
+	require.NoError(t, err)
+	var spec schema.PackageSpec
+	require.NoError(t, json.Unmarshal([]byte(resp.Schema), &spec))
+	assert.Contains(t, spec.Resources, "test:index:Remote")
+	assert.Contains(t, spec.Resources, "test:index:Local")
+
+	created, err := m.Create(ctx, &pulumirpc.CreateRequest{Urn: "urn:pulumi:stack::project::test:index:Remote::r"})
+	require.NoError(t, err)
+	assert.Equal(t, "remote", created.Id)
+	created, err = m.Create(ctx, &pulumirpc.CreateRequest{Urn: "urn:pulumi:stack::project::test:index:Local::r"})
+	require.NoError(t, err)
+	assert.Equal(t, "local", created.Id)
+
+	_, err = m.Cancel(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+	remote := m.(*muxer).servers[0].(*remoteServer)
+	assert.NotNil(t, remote.cmd.ProcessState, "the plugin should be stopped by Cancel")
+}
+
+func TestPluginEndpointRequiresHost(t *testing.T) {
+	_, err := PluginEndpoint("pulumi-resource-test").Server(nil)
+	assert.ErrorContains(t, err, "plugins require the engine host")
+}
+
+func TestPluginEndpointStartTimeout(t *testing.T) {
+	bin, host := buildTestPlugin(t)
+	t.Setenv("TEST_PLUGIN_MODE", "silent")
+	setTimeout(t, &pluginStartTimeout, 100*time.Millisecond)
+
+	start := time.Now()
+	_, err := PluginEndpoint(bin).Server(host)
+	assert.ErrorContains(t, err, "did not announce a port")
+	assert.ErrorContains(t, err, "timed out")
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
+
+func TestPluginEndpointCancelAbortsHangingRequests(t *testing.T) {
+	bin, host := buildTestPlugin(t)
+	t.Setenv("TEST_PLUGIN_MODE", "hang")
+	setTimeout(t, &requestDrainTimeout, 100*time.Millisecond)
+	setTimeout(t, &pluginExitTimeout, 100*time.Millisecond)
+
+	server, err := PluginEndpoint(bin).Server(host)
+	require.NoError(t, err)
+
+	created := make(chan error, 1)
+	go func() {
+		_, err := server.Create(context.Background(), &pulumirpc.CreateRequest{})
+		created <- err
+	}()
+	// Give the request time to reach the plugin.
+	time.Sleep(100 * time.Millisecond)
+
+	cancelled := make(chan error, 1)
+	go func() {
+		_, err := server.Cancel(context.Background(), &emptypb.Empty{})
+		cancelled <- err
+	}()
+	select {
+	case err := <-cancelled:
+		assert.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("Cancel did not return while a request was hanging")
+	}
+	assert.Error(t, <-created, "the hanging request should be aborted")
+	assert.NotNil(t, server.(*remoteServer).cmd.ProcessState, "the plugin should be stopped by Cancel")
+}
+
+func TestAddressEndpoint(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := grpc.NewServer()
+	pulumirpc.RegisterResourceProviderServer(s, localServer{})
+	go func() { _ = s.Serve(listener) }()
+	defer s.Stop()
+
+	server, err := AddressEndpoint(listener.Addr().String()).Server(nil)
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, server.(*remoteServer).Close()) }()
+
+	ctx := context.Background()
+	created, err := server.Create(ctx, &pulumirpc.CreateRequest{Urn: "urn:pulumi:stack::project::test:index:Local::r"})
+	require.NoError(t, err)
+	assert.Equal(t, "local", created.Id)
+	_, err = server.Cancel(ctx, &emptypb.Empty{})
+	assert.NoError(t, err)
+}
+
+func TestRemoteCloseDrainsRequests(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := grpc.NewServer()
+	blocking := blockingServer{started: make(chan struct{}), release: make(chan struct{})}
+	pulumirpc.RegisterResourceProviderServer(s, blocking)
+	go func() { _ = s.Serve(listener) }()
+	defer s.Stop()
+
+	server, err := AddressEndpoint(listener.Addr().String()).Server(nil)
+	require.NoError(t, err)
+
+	created := make(chan error, 1)
+	go func() {
+		_, err := server.Create(context.Background(), &pulumirpc.CreateRequest{})
+		created <- err
+	}()
+	<-blocking.started
+
+	closed := make(chan error, 1)
+	go func() { closed <- server.(*remoteServer).Close() }()
+	select {
+	case <-closed:
+		t.Fatal("Close returned before the in-flight request")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(blocking.release)
+	assert.NoError(t, <-created)
+	assert.NoError(t, <-closed)
+}
+
+type blockingServer struct {
+	pulumirpc.UnimplementedResourceProviderServer
+	started, release chan struct{}
+}
+
+func (s blockingServer) Create(context.Context, *pulumirpc.CreateRequest) (*pulumirpc.CreateResponse, error) {
+	close(s.started)
+	<-s.release
+	return &pulumirpc.CreateResponse{Id: "blocked"}, nil
+}
+
+type localServer struct {
+	pulumirpc.UnimplementedResourceProviderServer
+}
+
+func (localServer) GetSchema(context.Context, *pulumirpc.GetSchemaRequest) (*pulumirpc.GetSchemaResponse, error) {
+	return &pulumirpc.GetSchemaResponse{Schema: `{"name":"test","resources":{"test:index:Local":{}}}`}, nil
+}
+
+func (localServer) Create(context.Context, *pulumirpc.CreateRequest) (*pulumirpc.CreateResponse, error) {
+	return &pulumirpc.CreateResponse{Id: "local"}, nil
+}
+
+func (localServer) Cancel(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}